@@ -6,6 +6,11 @@ import (
     "fmt"
     "math"
 )
+
+// nodeSpan returns span of the rule being reduced, from the first symbol up to the last consumed token
+func nodeSpan(yylex yyLexer, first Span, lookahead int) Span {
+    return yylex.(*MyLexer).spanFrom(first.Start, lookahead >= 0)
+}
 %}
 ////////////////////////////
 //  declarations section
@@ -15,6 +20,10 @@ import (
 %union{
     name         string
     numberRepr   string
+    span         Span
+    cstring      string
+    bstring      BitString
+    hstring      OctetString
 
     Number       Number
     Real         Real
@@ -29,6 +38,8 @@ import (
     DefinedValue DefinedValue
     ObjectIdentifierValue ObjectIdentifierValue
     Value Value
+    ValueList []Value
+    BracedValue BracedValue
     Assignment Assignment
     AssignmentList AssignmentList
     ModuleBody ModuleBody
@@ -48,9 +59,8 @@ import (
     NamedType NamedType
     ComponentType ComponentType
     ComponentTypeList ComponentTypeList
-    IntegerEnumType IntegerEnumType
-    IntegerEnumItemList IntegerEnumItemList
-    IntegerEnumItem IntegerEnumItem
+    NamedNumberList NamedNumberList
+    NamedNumber NamedNumber
     EnumeratedType EnumeratedType
     EnumeratedItemList EnumeratedItemList
     EnumeratedItem EnumeratedItem
@@ -62,6 +72,7 @@ import (
     SetOfType SetOfType
     NamedBitList []NamedBit
     NamedBit NamedBit
+    Exports Exports
     Imports []SymbolsFromModule
     SymbolsFromModule SymbolsFromModule
     SymbolList []Symbol
@@ -71,6 +82,9 @@ import (
     ChoiceType ChoiceType
     ExtensionAdditionAlternative ChoiceExtension
     ExtensionAdditionAlternativesList []ChoiceExtension
+    ExtensionAddition ExtensionAddition
+    ExtensionAdditionList []ExtensionAddition
+    ExceptionSpec *ExceptionSpec
 }
 
 %token WHITESPACE
@@ -78,11 +92,11 @@ import (
 %token <name> TYPEORMODULEREFERENCE
 %token <name> VALUEIDENTIFIER
 %token <Number> NUMBER
-%token <bstring> BSTRING          // TODO not implemented in lexer
+%token <bstring> BSTRING
 %token <bstring> XMLBSTRING       // TODO not implemented in lexer
-%token <hstring> HSTRING          // TODO not implemented in lexer
+%token <hstring> HSTRING
 %token <hstring> XMLHSTRING       // TODO not implemented in lexer
-%token <cstring> CSTRING          // TODO not implemented in lexer
+%token <cstring> CSTRING
 %token <cstring> XMLCSTRING       // TODO not implemented in lexer
 %token ASSIGNMENT
 %token RANGE_SEPARATOR
@@ -217,7 +231,7 @@ import (
 %type <DefinitiveObjIdComponent> DefinitiveNameAndNumberForm
 %type <DefinitiveIdentifier> DefinitiveIdentifier
 %type <name> NameForm
-%type <DefinedValue> DefinedValue
+%type <DefinedValue> DefinedValue ExternalValueReference
 %type <Type> ObjectIdentifierType
 %type <Type> IntegerType
 %type <Type> BooleanType
@@ -237,6 +251,10 @@ import (
 %type <Value> RealValue
 %type <Type> BooleanType
 %type <Value> BooleanValue
+%type <Value> BitStringValue OctetStringValue CharacterStringValue
+%type <Value> ChoiceValue NullValue ContainingValue BracedElement ReferencedValue
+%type <BracedValue> BracedValue BracedItemList
+%type <ValueList> BracedItem
 %type <Value> NumericRealValue SpecialRealValue
 %type <Number> SignedNumber
 %type <Number> number
@@ -271,13 +289,13 @@ import (
 %type <RangeEndpoint> LowerEndpoint UpperEndpoint
 %type <Value> LowerEndValue UpperEndValue
 %type <Type> CharacterStringType RestrictedCharacterStringType UnrestrictedCharacterStringType
-%type <Type> DefinedType ReferencedType
+%type <Type> DefinedType ReferencedType ExternalTypeReference
 %type <Type> EnumeratedType
+%type <EnumeratedType> Enumerations
 %type <EnumeratedItemList> EnumeratedItemList
 %type <EnumeratedItem> EnumeratedItem
-%type <Type> IntegerEnumType
-%type <IntegerEnumItemList> IntegerEnumItemList
-%type <IntegerEnumItem> IntegerEnumItem
+%type <NamedNumberList> NamedNumberList
+%type <NamedNumber> NamedNumber
 %type <Type> SequenceType
 %type <Type> SetType
 %type <Type> SequenceOfType
@@ -285,7 +303,12 @@ import (
 %type <ComponentType> ComponentType
 %type <ComponentTypeList> ComponentTypeList
 %type <ComponentTypeList> RootComponentTypeList
-%type <ComponentTypeList> ComponentTypeLists
+%type <SequenceType> ComponentTypeLists ExtensionAdditions
+%type <ExtensionAddition> ExtensionAddition ExtensionAdditionGroup
+%type <ExtensionAdditionList> ExtensionAdditionList
+%type <Number> VersionNumber
+%type <ExceptionSpec> ExtensionAndException ExceptionSpec
+%type <ExceptionSpec> ExceptionIdentification
 %type <Type> TaggedType
 %type <Tag> Tag
 %type <Value> ClassNumber
@@ -295,6 +318,8 @@ import (
 %type <Type> BitStringType
 %type <NamedBitList> NamedBitList
 %type <NamedBit> NamedBit
+%type <Exports> Exports
+%type <SymbolList> SymbolsExported
 %type <Imports> Imports
 %type <Imports> SymbolsImported
 %type <Imports> SymbolsFromModuleList
@@ -308,7 +333,7 @@ import (
 %type <ChoiceType> AlternativeTypeLists
 %type <AlternativeTypeList> AlternativeTypeList RootAlternativeTypeList
 %type <NamedType> NamedType
-%type <ExtensionAdditionAlternative> ExtensionAdditionAlternative
+%type <ExtensionAdditionAlternative> ExtensionAdditionAlternative ExtensionAdditionAlternativesGroup
 %type <ExtensionAdditionAlternativesList> ExtensionAdditionAlternatives
 %type <ExtensionAdditionAlternativesList> ExtensionAdditionAlternativesList
 %type <ModuleDefinition> ModuleDefinition
@@ -328,6 +353,9 @@ import (
 
 ModuleDefinitionList : ModuleDefinition  { yylex.(*MyLexer).result = append(make([]ModuleDefinition,0),$1) }
                   | ModuleDefinitionList ModuleDefinition  { yylex.(*MyLexer).result = append(yylex.(*MyLexer).result, $2) }
+                  // broken module is skipped up to its END
+                  | error END
+                  | ModuleDefinitionList error END
 ;
 
 
@@ -340,7 +368,7 @@ ModuleDefinition :
     BEGIN
     ModuleBody
     END
-    { $$ = ModuleDefinition{ModuleIdentifier: $1, TagDefault: $3, ExtensibilityImplied: $4, ModuleBody: $7} }
+    { $$ = ModuleDefinition{ModuleIdentifier: $1, TagDefault: $3, ExtensibilityImplied: $4, ModuleBody: $7, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 
     // { yylex.(*MyLexer).result = &ModuleDefinition{ModuleIdentifier: $1, TagDefault: $3, ExtensibilityImplied: $4, ModuleBody: $7} }
 ;
@@ -348,6 +376,8 @@ ModuleDefinition :
 typereference: TYPEORMODULEREFERENCE  { $$ = TypeReference($1) }
 ;
 
+// typereference and modulereference can't be told apart before DOT of ExternalTypeReference or end of symbol in
+// SymbolList, this reduce/reduce conflict is resolved by order of rules to typereference unless DOT follows
 modulereference: TYPEORMODULEREFERENCE;
 
 valuereference: VALUEIDENTIFIER  {  $$ = ValueReference($1)  }
@@ -394,21 +424,31 @@ ExtensionDefault : EXTENSIBILITY IMPLIED { $$ = true }
                  | /*empty*/             { $$ = false }
 ;
 
-ModuleBody : Exports Imports AssignmentList  { $$ = ModuleBody{Imports: $2, AssignmentList: $3} }
+ModuleBody : Exports Imports AssignmentList  { $$ = ModuleBody{Exports: $1, Imports: $2, AssignmentList: $3} }
            | /*empty*/  { $$ = ModuleBody{} }
 ;
 
 
-Exports : EXPORTS SymbolsExported SEMICOLON
-        | EXPORTS ALL SEMICOLON
-        | /*empty*/
+Exports : EXPORTS SymbolsExported SEMICOLON  { $$ = Exports{Mode: EXPORTS_SYMBOLS, SymbolList: $2} }
+        | EXPORTS ALL SEMICOLON  { $$ = Exports{Mode: EXPORTS_ALL} }
+        // broken list is not enforced, so that it doesn't cause import errors on top of syntax error
+        | EXPORTS error SEMICOLON  { $$ = Exports{Mode: EXPORTS_ALL} }
+        | /*empty*/  { $$ = Exports{Mode: EXPORTS_ABSENT} }
 ;
 
-SymbolsExported : SymbolList
-                | /*empty*/
+SymbolsExported : SymbolList  { $$ = $1 }
+                | /*empty*/  { $$ = make([]Symbol, 0) }
 ;
 
 Imports : IMPORTS SymbolsImported SEMICOLON  { $$ = $2 }
+        | IMPORTS error
+          {
+              $$ = make([]SymbolsFromModule, 0)
+              if yylex.(*MyLexer).resync() {
+                  yyrcvr.char = -1 // yyclearin
+                  Errflag = 0      // yyerrok
+              }
+          }
         | /*empty*/  { $$ = make([]SymbolsFromModule, 0) }
 ;
 
@@ -420,14 +460,15 @@ SymbolsFromModuleList : SymbolsFromModule  { $$ = append(make([]SymbolsFromModul
                       | SymbolsFromModuleList SymbolsFromModule  { $$ = append($1, $2) }
 ;
 
-SymbolsFromModule : SymbolList FROM GlobalModuleReference  { $$ = SymbolsFromModule{$1, $3} }
+SymbolsFromModule : SymbolList FROM GlobalModuleReference  { $$ = SymbolsFromModule{SymbolList: $1, Module: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 GlobalModuleReference : modulereference AssignedIdentifier  { $$ = GlobalModuleReference{$1, $2} }
 ;
 
+// DefinedValue form is not supported: telling it from the first symbol imported from the next module
+// takes two tokens of lookahead
 AssignedIdentifier : ObjectIdentifierValue  { $$ = $1 }
-                   | DefinedValue  { $$ = $1 }
                    | /*empty*/  { $$ = nil }
 ;
 
@@ -449,6 +490,23 @@ Reference : typereference  { $$ = TypeReference($1) }
 
 AssignmentList : Assignment  { $$ = NewAssignmentList($1) }
                | AssignmentList Assignment  { $$ = $1.Append($2) }
+               // broken assignment is skipped, lexer moves input to the start of the next one
+               | error
+                 {
+                     $$ = NewAssignmentList()
+                     if yylex.(*MyLexer).resync() {
+                         yyrcvr.char = -1 // yyclearin
+                         Errflag = 0      // yyerrok
+                     }
+                 }
+               | AssignmentList error
+                 {
+                     $$ = $1
+                     if yylex.(*MyLexer).resync() {
+                         yyrcvr.char = -1 // yyclearin
+                         Errflag = 0      // yyerrok
+                     }
+                 }
 ;
 
 Assignment : TypeAssignment
@@ -463,26 +521,35 @@ Assignment : TypeAssignment
 
 // 13.1
 
-DefinedType : // ExternalTypeReference
-            /*|*/ typereference  { $$ = $1 }
+DefinedType : ExternalTypeReference
+            | typereference  { $$ = $1 }
 //            | ParameterizedType
 //            | ParameterizedValueSetType
 ;
 
+// 14.1
+
+ExternalTypeReference : modulereference DOT typereference  { $$ = ExternalTypeReference{ModuleReference: ModuleReference($1), TypeReference: $3} }
+;
+
 // 13.3
 
-DefinedValue : "t" "o" "d" "o"  { $$ = DefinedValue{} }
-// ExternalValueReference
-// | Valuereference
+DefinedValue : ExternalValueReference
+             | valuereference  { $$ = DefinedValue{ValueReference: $1} }
 // | ParameterizedValue
-//;
+;
+
+// 14.6
+
+ExternalValueReference : modulereference DOT valuereference  { $$ = DefinedValue{ModuleReference: ModuleReference($1), ValueReference: $3} }
+;
 
 // 15.1
 
-TypeAssignment : typereference ASSIGNMENT Type  { $$ = TypeAssignment{$1, $3 , ""} }
+TypeAssignment : typereference ASSIGNMENT Type  { $$ = TypeAssignment{TypeReference: $1, Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
-ValueAssignment : valuereference Type ASSIGNMENT Value  { $$ = ValueAssignment{$1, $2, $4} }
+ValueAssignment : valuereference Type ASSIGNMENT Value  { $$ = ValueAssignment{ValueReference: $1, Type: $2, Value: valueNotation($2, $4), Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 16.1
@@ -499,7 +566,6 @@ BuiltinType : BitStringType
             | CharacterStringType
             | ChoiceType
 //            | EmbeddedPDVType
-            | IntegerEnumType
             | EnumeratedType
 //            | ExternalType
 //            | InstanceOfType
@@ -528,43 +594,70 @@ ReferencedType : DefinedType
 
 // 16.5
 
-NamedType : identifier Type  { $$ = NamedType{Identifier: Identifier($1), Type: $2} }
+NamedType : identifier Type  { $$ = NamedType{Identifier: Identifier($1), Type: $2, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 16.7
 
 Value : BuiltinValue
-//      | ReferencedValue
+      | ReferencedValue
 //      | ObjectClassFieldValue
-        | QUOTATION_MARK TYPEORMODULEREFERENCE QUOTATION_MARK  { $$ = String($2) }
-        | QUOTATION_MARK VALUEIDENTIFIER QUOTATION_MARK  { $$ = String($2) }
-        | QUOTATION_MARK RealValue QUOTATION_MARK  { $$ = $2 }
+;
+
+// 16.11
+
+// valuereference alternative of DefinedValue is IntegerValue given by identifier, which Registry.ResolveValue
+// resolves as reference to value unless it names a number of the type, so only ExternalValueReference is here
+ReferencedValue : ExternalValueReference  { $$ = $1 }
+//              | ValueFromObject
 ;
 
 // 16.8
 
-// TODO
-BuiltinValue : // BitStringValue
-             /*|*/ BooleanValue
-//             | CharacterStringValue
-//             | ChoiceValue
+// values in curly braces can't be told apart without their type: SequenceValue, SequenceOfValue, SetValue,
+// SetOfValue, ObjectIdentifierValue, BitStringValue given as IdentifierList and CharacterStringValue given as
+// CharacterStringList, Quadruple or Tuple are all parsed as BracedValue, see resolveValueNotation.
+// EnumeratedValue is IntegerValue given by identifier.
+BuiltinValue : BitStringValue
+               | BooleanValue
+               | CharacterStringValue
+               | ChoiceValue
 //             | EmbeddedPDVValue
-//             | EnumeratedValue
 //             | ExternalValue
 //             | InstanceOfValue
                | IntegerValue
-//             | NullValue
-               | ObjectIdentifierValue  { $$ = $1 }
-//             | OctetStringValue
+               | NullValue
+               | OctetStringValue
                | RealValue
 //             | RelativeOIDValue
-//             | SequenceValue
-//             | SequenceOfValue
-//             | SetValue
-//             | SetOfValue
+               | BracedValue  { $$ = $1 }
+               | ContainingValue
 //             | TaggedValue
 ;
 
+BracedValue : OPEN_CURLY CLOSE_CURLY  { $$ = BracedValue{} }
+            | OPEN_CURLY BracedItemList CLOSE_CURLY  { $$ = $2 }
+;
+
+BracedItemList : BracedItem  { $$ = BracedValue{$1} }
+               | BracedItemList COMMA BracedItem  { $$ = append($1, $3) }
+;
+
+BracedItem : BracedElement  { $$ = []Value{$1} }
+           | BracedItem BracedElement  { $$ = append($1, $2) }
+;
+
+// NameAndNumberForm of OBJECT IDENTIFIER is kept as ObjectIdentifierValue with single component, references are
+// ReferencedValue.
+BracedElement : Value
+              | NameAndNumberForm  { $$ = NewObjectIdentifierValue($1) }
+;
+
+// 21.9, 22.3
+
+ContainingValue : CONTAINING Value  { $$ = ContainingValue{Value: $2} }
+;
+
 // 17.3
 
 BooleanType : BOOLEAN  { $$ = BooleanType{} }
@@ -576,16 +669,18 @@ BooleanValue : TRUE  { $$ = Boolean(true) }
 
 // 18.1
 
+// empty NamedNumberList is not allowed by X.680, but accepted
 IntegerType : INTEGER  { $$ = IntegerType{} }
-            | INTEGER OPEN_CURLY NamedNumberList CLOSE_CURLY  { $$ = IntegerType{} }    // TODO support NamedNumberList
+            | INTEGER OPEN_CURLY CLOSE_CURLY  { $$ = IntegerType{} }
+            | INTEGER OPEN_CURLY NamedNumberList CLOSE_CURLY  { $$ = IntegerType{NamedNumberList: $3} }
 ;
 
-NamedNumberList : NamedNumber
-                | NamedNumberList COMMA NamedNumber
+NamedNumberList : NamedNumber  { $$ = append(make(NamedNumberList, 0), $1) }
+                | NamedNumberList COMMA NamedNumber  { $$ = append($1, $3) }
 ;
 
-NamedNumber : identifier OPEN_ROUND SignedNumber CLOSE_ROUND
-          | identifier OPEN_ROUND DefinedValue CLOSE_ROUND
+NamedNumber : identifier OPEN_ROUND SignedNumber CLOSE_ROUND  { $$ = NamedNumber{Name: Identifier($1), Value: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+          | identifier OPEN_ROUND DefinedValue CLOSE_ROUND  { $$ = NamedNumber{Name: Identifier($1), Value: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 SignedNumber : NUMBER  { $$ = $1 }
@@ -619,8 +714,8 @@ SpecialRealValue : PLUS_INFINITY  { $$ = Real(math.Inf(1)) }
 ;
 
 // TODO this seem to be not strict enough (spaces can sneak in into composite value)
-realnumber : NUMBER  { $$ = parseRealNumber($1, 0, 0) }
-           | NUMBER DOT NUMBER  { $$ = parseRealNumber($1, $3, 0) }
+// NUMBER alone is IntegerValue, it would conflict with SignedNumber otherwise, so REAL value may be Number
+realnumber : NUMBER DOT NUMBER  { $$ = parseRealNumber($1, $3, 0) }
            | NUMBER DOT NUMBER EXPONENT SignedExponent  { $$ = parseRealNumber($1, $3, $5) }
            | NUMBER EXPONENT SignedExponent  { $$ = parseRealNumber($1, 0, $3) }
 ;
@@ -631,16 +726,19 @@ SignedExponent : NUMBER
 
 // 21.1
 
-BitStringType : BIT STRING  { $$ = BitStringType{} }
-              | BIT STRING OPEN_CURLY NamedBitList CLOSE_CURLY  { $$ = BitStringType{NamedBits: $4} }
+BitStringType : BIT STRING  { $$ = BitStringType{Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+              | BIT STRING OPEN_CURLY NamedBitList CLOSE_CURLY  { $$ = BitStringType{NamedBits: $4, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+;
+
+BitStringValue : BSTRING  { $$ = $1 }
 ;
 
 NamedBitList : NamedBit  { $$ = append(make([]NamedBit, 0), $1) }
-             | NamedBitList "," NamedBit  { $$ = append($1, $3) }
+             | NamedBitList COMMA NamedBit  { $$ = append($1, $3) }
 ;
 
-NamedBit : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = NamedBit{Name: Identifier($1), Index: $3} }
-         | identifier OPEN_ROUND DefinedValue CLOSE_ROUND  { $$ = NamedBit{Name: Identifier($1), Index: $3} }
+NamedBit : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = NamedBit{Name: Identifier($1), Index: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+         | identifier OPEN_ROUND DefinedValue CLOSE_ROUND  { $$ = NamedBit{Name: Identifier($1), Index: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 22.1
@@ -648,60 +746,111 @@ NamedBit : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = NamedBit{Name: Ident
 OctetStringType : OCTET STRING  { $$ = OctetStringType{} }
 ;
 
+OctetStringValue : HSTRING  { $$ = $1 }
+;
+
 // 23.1
 
 NullType : NULL  { $$ = NullType{} }
 ;
-// INTEGER { $$ = IntegerEnumType{} }
-IntegerEnumType : INTEGER OPEN_CURLY CLOSE_CURLY { $$ = IntegerEnumType{} }
-                | INTEGER OPEN_CURLY IntegerEnumItemList CLOSE_CURLY  { $$ = IntegerEnumType{Enums: $3} }
-;
-IntegerEnumItemList : IntegerEnumItem  { $$ = append(make(IntegerEnumItemList, 0), $1) }
-                  | IntegerEnumItemList COMMA IntegerEnumItem  { $$ = append($1, $3) }
-;
 
-IntegerEnumItem : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = IntegerEnumItem{Name: Identifier($1),Index: $3} }
+// 23.3
+
+// NULL in constraint, like (NULL), is either SingleValue or TypeConstraint, this reduce/reduce conflict is resolved
+// by order of rules to NullType, both constrain to NULL
+NullValue : NULL  { $$ = NullValue{} }
 ;
 
 
-// ENUMERATED { $$ = EnumeratedType{} }
-EnumeratedType : ENUMERATED OPEN_CURLY CLOSE_CURLY { $$ = EnumeratedType{} }
-                | ENUMERATED OPEN_CURLY EnumeratedItemList CLOSE_CURLY  { $$ = EnumeratedType{Enums: $3} }
+// 19.1
+
+// empty Enumerations are not allowed by X.680, but accepted
+EnumeratedType : ENUMERATED OPEN_CURLY CLOSE_CURLY { $$ = EnumeratedType{Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+                | ENUMERATED OPEN_CURLY Enumerations CLOSE_CURLY
+    {
+        enumerated := $3
+        enumerated.Span = nodeSpan(yylex, $<span>1, yyrcvr.char)
+        $$ = enumerated
+    }
 ;
+
+// RootEnumeration and AdditionalEnumeration are inlined, so comma after them can be shifted without reduction
+Enumerations : EnumeratedItemList  { $$ = EnumeratedType{Enums: $1} }
+             | EnumeratedItemList COMMA ExtensionAndException  { $$ = EnumeratedType{Enums: $1, Extensible: true, ExceptionSpec: $3} }
+             | EnumeratedItemList COMMA ExtensionAndException COMMA EnumeratedItemList
+    {
+        $$ = EnumeratedType{Enums: $1, Extensible: true, ExceptionSpec: $3, AdditionalEnums: $5}
+    }
+;
+
 EnumeratedItemList : EnumeratedItem  { $$ = append(make(EnumeratedItemList, 0), $1) }
                   | EnumeratedItemList COMMA EnumeratedItem  { $$ = append($1, $3) }
 ;
 
-EnumeratedItem : identifier OPEN_ROUND number CLOSE_ROUND  { $$ = EnumeratedItem{Name: Identifier($1),Index: $3} }
+EnumeratedItem : identifier  { $$ = EnumeratedItem{Name: Identifier($1), Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+               | identifier OPEN_ROUND SignedNumber CLOSE_ROUND  { $$ = EnumeratedItem{Name: Identifier($1), Index: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+               | identifier OPEN_ROUND DefinedValue CLOSE_ROUND  { $$ = EnumeratedItem{Name: Identifier($1), Index: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 
-SetType : SET OPEN_CURLY CLOSE_CURLY  { $$ = SetType{} }
-             | SET OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SetType{} }
-             | SET OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SetType{Components: $3} }
+// SET { ExtensionAndException OptionalExtensionMarker } is covered by ComponentTypeLists
+
+SetType : SET OPEN_CURLY CLOSE_CURLY  { $$ = SetType{Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+             | SET OPEN_CURLY ComponentTypeLists CLOSE_CURLY
+    {
+        set := SetType($3)
+        set.Span = nodeSpan(yylex, $<span>1, yyrcvr.char)
+        $$ = set
+    }
 ;
 
 // 24.1
 
-SequenceType : SEQUENCE OPEN_CURLY CLOSE_CURLY  { $$ = SequenceType{} }
-             | SEQUENCE OPEN_CURLY ExtensionAndException OptionalExtensionMarker CLOSE_CURLY  { $$ = SequenceType{} }
-             | SEQUENCE OPEN_CURLY ComponentTypeLists CLOSE_CURLY  { $$ = SequenceType{Components: $3} }
-;
+// SEQUENCE { ExtensionAndException OptionalExtensionMarker } is covered by ComponentTypeLists
 
+SequenceType : SEQUENCE OPEN_CURLY CLOSE_CURLY  { $$ = SequenceType{Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+             | SEQUENCE OPEN_CURLY ComponentTypeLists CLOSE_CURLY
+    {
+        sequence := $3
+        sequence.Span = nodeSpan(yylex, $<span>1, yyrcvr.char)
+        $$ = sequence
+    }
+;
 
-ExtensionAndException : ELLIPSIS
-                      | ELLIPSIS ExceptionSpec
+// ExceptionSpec may be empty
+ExtensionAndException : ELLIPSIS ExceptionSpec  { $$ = $2 }
 ;
 
 OptionalExtensionMarker : COMMA ELLIPSIS | /*empty*/
 ;
 
-// TODO Extensions are not supported
-ComponentTypeLists : RootComponentTypeList
-//                   | RootComponentTypeList "," ExtensionAndException ExtensionAdditions OptionalExtensionMarker
-//                   | RootComponentTypeList "," ExtensionAndException ExtensionAdditions ExtensionEndMarker "," RootComponentTypeList
-//                   | ExtensionAndException ExtensionAdditions ExtensionEndMarker "," RootComponentTypeList
-//                   | ExtensionAndException ExtensionAdditions OptionalExtensionMarker
+// ComponentTypeLists are rewritten to resolve conflicts: optional extension additions, extension end marker
+// and the second root component list are folded into ExtensionAdditions. ComponentTypeList is used instead
+// of RootComponentTypeList so comma after it can be shifted without reduction.
+//
+// ComponentTypeLists ::=
+//     RootComponentTypeList
+//   | RootComponentTypeList "," ExtensionAndException ExtensionAdditions OptionalExtensionMarker
+//   | RootComponentTypeList "," ExtensionAndException ExtensionAdditions ExtensionEndMarker "," RootComponentTypeList
+//   | ExtensionAndException ExtensionAdditions ExtensionEndMarker "," RootComponentTypeList
+//   | ExtensionAndException ExtensionAdditions OptionalExtensionMarker
+
+ComponentTypeLists : ComponentTypeList  { $$ = SequenceType{Components: $1} }
+                   | ComponentTypeList COMMA ExtensionAndException ExtensionAdditions
+    {
+        lists := $4
+        lists.Components = $1
+        lists.Extensible = true
+        lists.ExceptionSpec = $3
+        $$ = lists
+    }
+                   | ExtensionAndException ExtensionAdditions
+    {
+        lists := $2
+        lists.Extensible = true
+        lists.ExceptionSpec = $1
+        $$ = lists
+    }
 ;
 
 RootComponentTypeList : ComponentTypeList
@@ -710,73 +859,107 @@ RootComponentTypeList : ComponentTypeList
 ExtensionEndMarker : COMMA ELLIPSIS
 ;
 
-ExtensionAdditions : COMMA ExtensionAdditionList
-                   | /*empty*/
+// additions with optional extension end marker and root components after it
+ExtensionAdditions : /*empty*/  { $$ = SequenceType{} }
+                   | ExtensionEndMarker  { $$ = SequenceType{} }
+                   | ExtensionEndMarker COMMA ComponentTypeList  { $$ = SequenceType{TrailingRootComponents: $3} }
+                   | COMMA ExtensionAdditionList  { $$ = SequenceType{ExtensionAdditions: $2} }
+                   | COMMA ExtensionAdditionList ExtensionEndMarker  { $$ = SequenceType{ExtensionAdditions: $2} }
+                   | COMMA ExtensionAdditionList ExtensionEndMarker COMMA ComponentTypeList  { $$ = SequenceType{ExtensionAdditions: $2, TrailingRootComponents: $5} }
 ;
 
-ExtensionAdditionList : ExtensionAddition
-                      | ExtensionAdditionList COMMA ExtensionAddition
+ExtensionAdditionList : ExtensionAddition  { $$ = append(make([]ExtensionAddition, 0), $1) }
+                      | ExtensionAdditionList COMMA ExtensionAddition  { $$ = append($1, $3) }
 ;
 
-ExtensionAddition : ComponentType
-                  | ExtensionAdditionGroup
+ExtensionAddition : ComponentType  { $$ = $1.(ExtensionAddition) }
+                  | ExtensionAdditionGroup  { $$ = $1 }
+;
 
 ExtensionAdditionGroup : LEFT_VERSION_BRACKETS VersionNumber ComponentTypeList RIGHT_VERSION_BRACKETS
+    {
+        $$ = ExtensionAdditionGroup{Version: $2, Components: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}
+    }
 ;
 
-VersionNumber : /*empty*/
-              | NUMBER COLON
+VersionNumber : /*empty*/  { $$ = 0 }
+              | NUMBER COLON  { $$ = $1 }
 ;
 
 ComponentTypeList : ComponentType  { $$ = append(make(ComponentTypeList, 0), $1) }
                   | ComponentTypeList COMMA ComponentType  { $$ = append($1, $3) }
 ;
 
-ComponentType : NamedType  { $$ = NamedComponentType{NamedType: $1} }
-              | NamedType OPTIONAL  { $$ = NamedComponentType{NamedType: $1, IsOptional: true} }
-              | NamedType DEFAULT Value  { $$ = NamedComponentType{NamedType: $1, Default: $3} }
-              | COMPONENTS OF Type  { $$ = ComponentsOfComponentType{Type: $3} }
+ComponentType : NamedType  { $$ = NamedComponentType{NamedType: $1, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+              | NamedType OPTIONAL  { $$ = NamedComponentType{NamedType: $1, IsOptional: true, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+              | NamedType DEFAULT Value  { $$ = NamedComponentType{NamedType: $1, Default: valueNotation($1.Type, $3), Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+              | COMPONENTS OF Type  { $$ = ComponentsOfComponentType{Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 28.1
 
 
-ChoiceType : CHOICE OPEN_CURLY AlternativeTypeLists CLOSE_CURLY  { $$ = $3 }
+ChoiceType : CHOICE OPEN_CURLY AlternativeTypeLists CLOSE_CURLY
+    {
+        choice := $3
+        choice.Span = nodeSpan(yylex, $<span>1, yyrcvr.char)
+        $$ = choice
+    }
 ;
 
-AlternativeTypeLists : AlternativeTypeList COMMA ExtensionAndException ExtensionAdditionAlternatives OptionalExtensionMarker { $$ = ChoiceType{$1,$4} }
-                     | AlternativeTypeList  { $$ = ChoiceType{AlternativeTypeList: $1} }
+// AlternativeTypeLists are rewritten the same way as ComponentTypeLists: optional extension additions and
+// extension marker are folded into ExtensionAdditionAlternatives, AlternativeTypeList is used instead of
+// RootAlternativeTypeList.
+//
+// AlternativeTypeLists ::=
+//     RootAlternativeTypeList
+//   | RootAlternativeTypeList "," ExtensionAndException ExtensionAdditionAlternatives OptionalExtensionMarker
+
+AlternativeTypeLists : AlternativeTypeList  { $$ = ChoiceType{AlternativeTypeList: $1} }
+                     | AlternativeTypeList COMMA ExtensionAndException ExtensionAdditionAlternatives
+    {
+        $$ = ChoiceType{AlternativeTypeList: $1, Extensible: true, ExceptionSpec: $3, ExtensionTypes: $4}
+    }
 ;
 
-// defined in grammar, but screws up ExtensionAndException parsing
 RootAlternativeTypeList : AlternativeTypeList
 ;
 
-ExtensionAdditionAlternatives : COMMA ExtensionAdditionAlternativesList { $$ = $2 }
-                              | /*empty*/ { $$ = make([]ChoiceExtension, 0) }
+// additions with optional extension marker
+ExtensionAdditionAlternatives : /*empty*/  { $$ = make([]ChoiceExtension, 0) }
+                              | COMMA ELLIPSIS  { $$ = make([]ChoiceExtension, 0) }
+                              | COMMA ExtensionAdditionAlternativesList  { $$ = $2 }
+                              | COMMA ExtensionAdditionAlternativesList COMMA ELLIPSIS  { $$ = $2 }
 ;
 
 ExtensionAdditionAlternativesList : ExtensionAdditionAlternative  { $$ = append(make([]ChoiceExtension, 0), $1) }
                                   | ExtensionAdditionAlternativesList COMMA ExtensionAdditionAlternative  { $$ = append($1, $3) }
 ;
 
-ExtensionAdditionAlternative : /*ExtensionAdditionAlternativesGroup
-                             | */NamedType  { $$ = $1 }
+ExtensionAdditionAlternative : ExtensionAdditionAlternativesGroup  { $$ = $1 }
+                             | NamedType  { $$ = $1 }
 ;
 
-// TODO
 ExtensionAdditionAlternativesGroup : LEFT_VERSION_BRACKETS VersionNumber AlternativeTypeList RIGHT_VERSION_BRACKETS
+    {
+        $$ = ExtensionAdditionAlternativesGroup{Version: $2, AlternativeTypeList: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}
+    }
 ;
 
 AlternativeTypeList : NamedType  { $$ = append(make([]NamedType, 0), $1) }
                     | AlternativeTypeList COMMA NamedType  { $$ = append($1, $3) }
 ;
 
+// 28.9
+
+ChoiceValue : identifier COLON Value  { $$ = ChoiceValue{Identifier: Identifier($1), Value: $3} }
+;
+
 // 30.1
 
-TaggedType : Tag Type  { $$ = TaggedType{Tag: $1, Type: $2} }
-           | Tag IMPLICIT Type  { $$ = TaggedType{Tag: $1, Type: $3, TagType: TAGS_IMPLICIT, HasTagType: true} }
-           | Tag EXPLICIT Type  { $$ = TaggedType{Tag: $1, Type: $3, TagType: TAGS_EXPLICIT, HasTagType: true} }
+TaggedType : Tag Type  { $$ = TaggedType{Tag: $1, Type: $2, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+           | Tag IMPLICIT Type  { $$ = TaggedType{Tag: $1, Type: $3, TagType: TAGS_IMPLICIT, HasTagType: true, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+           | Tag EXPLICIT Type  { $$ = TaggedType{Tag: $1, Type: $3, TagType: TAGS_EXPLICIT, HasTagType: true, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 Tag : OPEN_SQUARE Class ClassNumber CLOSE_SQUARE  { $$ = Tag{Class: $2, ClassNumber: $3} }
@@ -794,12 +977,12 @@ Class : UNIVERSAL  { $$ = CLASS_UNIVERSAL }
 
 // 25.1
 
-SequenceOfType : SEQUENCE OF Type  { $$ = SequenceOfType{$3} }
-               | SEQUENCE OF NamedType  { $$ = SequenceOfType{$3} }
+SequenceOfType : SEQUENCE OF Type  { $$ = SequenceOfType{Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+               | SEQUENCE OF NamedType  { $$ = SequenceOfType{Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
-SetOfType : SET OF Type  { $$ = SetOfType{$3} }
-               | SET OF NamedType  { $$ = SetOfType{$3} }
+SetOfType : SET OF Type  { $$ = SetOfType{Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+               | SET OF NamedType  { $$ = SetOfType{Type: $3, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 31.1
@@ -809,18 +992,19 @@ ObjectIdentifierType : OBJECT IDENTIFIER  { $$ = ObjectIdentifierType{} }
 
 // 31.3
 
+// leading valuereference can't be told from NameForm by parser, it's parsed as NameForm and resolved
+// on value lookup, see Registry.ResolveValue
 ObjectIdentifierValue : OPEN_CURLY ObjIdComponentsList CLOSE_CURLY  { $$ = $2 }
-                      | OPEN_CURLY DefinedValue ObjIdComponentsList CLOSE_CURLY  { $$ = NewObjectIdentifierValue($2).Append($3...) }
 ;
 
 ObjIdComponentsList :  ObjIdComponents  { $$ = NewObjectIdentifierValue($1)  }
                     | ObjIdComponents ObjIdComponentsList  { $$ = NewObjectIdentifierValue($1).Append($2...)  }
 ;
 
-ObjIdComponents : NameForm  { $$ = ObjectIdElement{Name: $1} }
-                | NumberForm
+ObjIdComponents : NameForm  { $$ = ObjectIdElement{Name: $1, NameForm: true} }
+                | NUMBER  { $$ = ObjectIdElement{Id: $1.IntValue()} }
                 | NameAndNumberForm
-                | DefinedValue  { $$ = $1 }
+                | ExternalValueReference  { $$ = $1 }
 ;
 
 NumberForm : NUMBER   { $$ = ObjectIdElement{Id: $1.IntValue()} }
@@ -869,6 +1053,11 @@ RestrictedCharacterStringType    : BMPString  { $$ = RestrictedStringType{LexTyp
 UnrestrictedCharacterStringType : CHARACTER STRING  { $$ = CharacterStringType{} }
 ;
 
+// 41.8
+
+CharacterStringValue : CSTRING  { $$ = String($1) }
+;
+
 // 41.1
 
 UsefulType : GeneralizedTime  { $$ = TypeReference("GeneralizedTime") }
@@ -876,7 +1065,7 @@ UsefulType : GeneralizedTime  { $$ = TypeReference("GeneralizedTime") }
 
 // 45.1
 
-ConstrainedType : Type Constraint  { $$ = ConstraintedType{$1, $2} }
+ConstrainedType : Type Constraint  { $$ = ConstraintedType{Type: $1, Constraint: $2, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
                 | TypeWithConstraint
 ;
 
@@ -884,12 +1073,12 @@ ConstrainedType : Type Constraint  { $$ = ConstraintedType{$1, $2} }
 
 TypeWithConstraint : //SET Constraint OF Type
                    //| SET SizeConstraint OF Type
-                   /*|*/ SEQUENCE Constraint OF Type  { $$ = ConstraintedType{SequenceOfType{$4}, $2} }
-                   | SEQUENCE SizeConstraint OF Type  { $$ = ConstraintedType{SequenceOfType{$4}, SingleElementConstraint($2)} }
+                   /*|*/ SEQUENCE Constraint OF Type  { $$ = ConstraintedType{Type: SequenceOfType{Type: $4, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}, Constraint: $2, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+                   | SEQUENCE SizeConstraint OF Type  { $$ = ConstraintedType{Type: SequenceOfType{Type: $4, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}, Constraint: SingleElementConstraint($2), Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
                    //| SET Constraint OF NamedType
                    //| SET SizeConstraint OF NamedType
-                   | SEQUENCE Constraint OF NamedType  { $$ = ConstraintedType{SequenceOfType{$4}, $2} }
-                   | SEQUENCE SizeConstraint OF NamedType  { $$ = ConstraintedType{SequenceOfType{$4}, SingleElementConstraint($2)} }
+                   | SEQUENCE Constraint OF NamedType  { $$ = ConstraintedType{Type: SequenceOfType{Type: $4, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}, Constraint: $2, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
+                   | SEQUENCE SizeConstraint OF NamedType  { $$ = ConstraintedType{Type: SequenceOfType{Type: $4, Span: nodeSpan(yylex, $<span>1, yyrcvr.char)}, Constraint: SingleElementConstraint($2), Span: nodeSpan(yylex, $<span>1, yyrcvr.char)} }
 ;
 
 // 45.6
@@ -1004,13 +1193,13 @@ TypeConstraint : Type  { $$ = TypeConstraint{$1} }
 
 // 49.4
 
-ExceptionSpec : EXCLAMATION ExceptionIdentification
-              | /* empty */
+ExceptionSpec : EXCLAMATION ExceptionIdentification  { $$ = $2 }
+              | /* empty */  { $$ = nil }
 ;
 
-ExceptionIdentification : SignedNumber
-                        | DefinedValue
-                        | Type COLON Value
+ExceptionIdentification : SignedNumber  { $$ = &ExceptionSpec{Value: $1} }
+                        | DefinedValue  { $$ = &ExceptionSpec{Value: $1} }
+                        | Type COLON Value  { $$ = &ExceptionSpec{Type: $1, Value: $3} }
 ;
 
 ///// X.681
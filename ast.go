@@ -1,14 +1,83 @@
 package asn1go
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type AstNode interface{}
 
+// Position of a character in source text, Line and Column start from 1
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats position as file:line:col, file is omitted when unknown
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if len(p.File) == 0 {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Span is a region of source text, End points right after the last character of the node.
+// Leaf types and values without structure (BOOLEAN, INTEGER, NULL, references...) do not carry spans,
+// their location is covered by the enclosing NamedType or assignment.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Comment is a single -- or /* */ comment, Text has comment delimiters and surrounding whitespace removed
+type Comment struct {
+	Text string
+	Span Span
+}
+
+// CommentGroup is a sequence of comments with no tokens or empty lines between them
+type CommentGroup []Comment
+
+// Lines returns text of comments in group split into lines. Separator lines made of dashes or asterisks
+// are turned into empty lines, consecutive empty lines are merged and leading and trailing ones are dropped.
+func (g CommentGroup) Lines() []string {
+	var res []string
+	for _, c := range g {
+		for _, line := range strings.Split(c.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.Trim(line, "-*") == "" {
+				line = ""
+			}
+			if line == "" && len(res) > 0 && res[len(res)-1] == "" {
+				continue
+			}
+			res = append(res, line)
+		}
+	}
+	for len(res) > 0 && res[0] == "" {
+		res = res[1:]
+	}
+	for len(res) > 0 && res[len(res)-1] == "" {
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
 type ModuleDefinition struct {
 	ModuleIdentifier     ModuleIdentifier
 	TagDefault           int
 	ExtensibilityImplied bool
 	ModuleBody           ModuleBody
+	Doc                  CommentGroup // comments right before module header
+	Span                 Span
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -37,12 +106,39 @@ const (
 
 type ModuleBody struct {
 	AssignmentList AssignmentList
+	Exports        Exports
 	Imports        []SymbolsFromModule
 }
 
+const (
+	EXPORTS_ABSENT  = iota // no EXPORTS clause, everything is exported
+	EXPORTS_ALL            // EXPORTS ALL
+	EXPORTS_SYMBOLS        // EXPORTS with list of symbols, nothing is exported if the list is empty
+)
+
+// Exports is EXPORTS clause of module
+type Exports struct {
+	Mode       int      // one of EXPORTS_*
+	SymbolList []Symbol // exported symbols, set only if Mode is EXPORTS_SYMBOLS
+}
+
+// Exported reports whether symbol with name can be imported from module
+func (e Exports) Exported(name string) bool {
+	if e.Mode != EXPORTS_SYMBOLS {
+		return true
+	}
+	for _, symbol := range e.SymbolList {
+		if reference, ok := symbol.(Reference); ok && reference.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 type SymbolsFromModule struct {
 	SymbolList []Symbol
 	Module     GlobalModuleReference
+	Span       Span
 }
 
 type Symbol interface {
@@ -108,6 +204,9 @@ type ValueAssignment struct {
 	ValueReference ValueReference
 	Type           Type
 	Value          Value
+	Doc            CommentGroup // comments on lines right before assignment
+	LineComment    CommentGroup // comments after assignment on its last line
+	Span           Span
 }
 
 func (v ValueAssignment) Reference() Reference {
@@ -119,6 +218,9 @@ type TypeAssignment struct {
 	TypeReference TypeReference
 	Type          Type
 	Module        string
+	Doc           CommentGroup // comments on lines right before assignment
+	LineComment   CommentGroup // comments after assignment on its last line
+	Span          Span
 }
 
 func (v TypeAssignment) Reference() Reference {
@@ -126,8 +228,11 @@ func (v TypeAssignment) Reference() Reference {
 }
 
 type NamedType struct {
-	Identifier Identifier
-	Type       Type
+	Identifier  Identifier
+	Type        Type
+	Doc         CommentGroup // comments on lines right before component or alternative
+	LineComment CommentGroup // comments after component or alternative on its last line
+	Span        Span
 }
 
 func (t NamedType) Zero() interface{} {
@@ -157,6 +262,20 @@ func (r TypeReference) Zero() interface{} {
 
 func (TypeReference) IsSymbol() {}
 
+// reference to type defined in other module, Module.Type
+type ExternalTypeReference struct {
+	ModuleReference ModuleReference
+	TypeReference   TypeReference
+}
+
+func (r ExternalTypeReference) String() string {
+	return fmt.Sprintf("%v.%v", r.ModuleReference, r.TypeReference)
+}
+
+func (r ExternalTypeReference) Zero() interface{} {
+	return nil
+}
+
 // value reference
 type ValueReference string
 
@@ -229,6 +348,31 @@ func (Boolean) Type() Type {
 	return BooleanType{}
 }
 
+// bstring lexem, bits are packed starting from the most significant bit of first byte
+type BitString struct {
+	Bytes     []byte
+	BitLength int
+}
+
+func (BitString) Type() Type {
+	return BitStringType{}
+}
+
+// At returns bit at the given index, or 0 if index is out of range
+func (x BitString) At(i int) int {
+	if i < 0 || i >= x.BitLength {
+		return 0
+	}
+	return int(x.Bytes[i/8]>>(7-uint(i%8))) & 1
+}
+
+// hstring lexem, odd number of hex digits is padded with trailing zero
+type OctetString []byte
+
+func (OctetString) Type() Type {
+	return OctetStringType{}
+}
+
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // types
 
@@ -251,13 +395,22 @@ func (ObjectIdentifierType) Zero() interface{} {
 
 // integer
 type IntegerType struct {
-	NamedNumberList map[string]int
+	NamedNumberList NamedNumberList
 }
 
 func (IntegerType) Zero() interface{} {
 	return 0
 }
 
+// NamedNumberList holds named values of INTEGER in order of definition
+type NamedNumberList []NamedNumber
+
+type NamedNumber struct {
+	Name  Identifier
+	Value Value // Number or DefinedValue
+	Span  Span
+}
+
 type BigInt struct{}
 
 func (BigInt) Zero() interface{} {
@@ -280,18 +433,45 @@ func (BooleanType) Zero() interface{} {
 
 type ChoiceType struct {
 	AlternativeTypeList []NamedType
-	ExtensionTypes      []ChoiceExtension
-	// TODO ExtensionAndException
+	Extensible          bool              // true if there is extension marker
+	ExceptionSpec       *ExceptionSpec    // exception of extension marker, nil if absent
+	ExtensionTypes      []ChoiceExtension // alternatives and groups following extension marker
+	Span                Span
 }
 
 func (ChoiceType) Zero() interface{} {
 	return nil
 }
 
+// Alternatives returns root alternatives followed by extension alternatives, including ones from groups
+func (t ChoiceType) Alternatives() []NamedType {
+	res := append(make([]NamedType, 0, len(t.AlternativeTypeList)), t.AlternativeTypeList...)
+	for _, extension := range t.ExtensionTypes {
+		switch e := extension.(type) {
+		case NamedType:
+			res = append(res, e)
+		case ExtensionAdditionAlternativesGroup:
+			res = append(res, e.AlternativeTypeList...)
+		}
+	}
+	return res
+}
+
+// ChoiceExtension is alternative added to CHOICE after extension marker: NamedType or
+// ExtensionAdditionAlternativesGroup
 type ChoiceExtension interface {
 	isChoiceExtension()
 }
 
+// alternatives added together in [[ ]] brackets
+type ExtensionAdditionAlternativesGroup struct {
+	Version             Number // zero if version number is not given
+	AlternativeTypeList []NamedType
+	Span                Span
+}
+
+func (ExtensionAdditionAlternativesGroup) isChoiceExtension() {}
+
 ////////////////////////////////////////////////
 // String types
 
@@ -315,26 +495,14 @@ func (OctetStringType) Zero() interface{} {
 	return make([]byte, 0)
 }
 
-// string enum
-type IntegerEnumType struct {
-	Enums IntegerEnumItemList
-}
-
-func (IntegerEnumType) Zero() interface{} {
-	return make([]int, 0)
-}
-
-type IntegerEnumItemList []IntegerEnumItem
-
-type IntegerEnumItem struct {
-	Name  Identifier
-	Index Value
-}
-
 // string enum
 // number enum
 type EnumeratedType struct {
-	Enums EnumeratedItemList
+	Enums           EnumeratedItemList // root enumeration
+	Extensible      bool               // true if there is extension marker
+	ExceptionSpec   *ExceptionSpec     // exception of extension marker, nil if absent
+	AdditionalEnums EnumeratedItemList // items following extension marker
+	Span            Span
 }
 
 func (EnumeratedType) Zero() interface{} {
@@ -345,13 +513,19 @@ type EnumeratedItemList []EnumeratedItem
 
 type EnumeratedItem struct {
 	Name  Identifier
-	Index Value
+	Index Value // Number or DefinedValue, nil if number is not given
+	Span  Span
 }
 
 // number enum
 
 type SetType struct {
-	Components ComponentTypeList
+	Components             ComponentTypeList   // root components before extension marker
+	Extensible             bool                // true if there is extension marker
+	ExceptionSpec          *ExceptionSpec      // exception of extension marker, nil if absent
+	ExtensionAdditions     []ExtensionAddition // components and groups between extension markers
+	TrailingRootComponents ComponentTypeList   // root components following extension end marker
+	Span                   Span
 }
 
 func (SetType) Zero() interface{} {
@@ -361,15 +535,33 @@ func (SetType) Zero() interface{} {
 ////////////////////////////////////////////////
 // sequence type
 
-// TODO Extensions are not supported
 type SequenceType struct {
-	Components ComponentTypeList
+	Components             ComponentTypeList   // root components before extension marker
+	Extensible             bool                // true if there is extension marker
+	ExceptionSpec          *ExceptionSpec      // exception of extension marker, nil if absent
+	ExtensionAdditions     []ExtensionAddition // components and groups between extension markers
+	TrailingRootComponents ComponentTypeList   // root components following extension end marker
+	Span                   Span
 }
 
 func (SequenceType) Zero() interface{} {
 	return nil
 }
 
+// AllComponents returns root components, extension additions with groups flattened and trailing root components
+func (t SequenceType) AllComponents() ComponentTypeList {
+	res := append(make(ComponentTypeList, 0, len(t.Components)), t.Components...)
+	for _, addition := range t.ExtensionAdditions {
+		switch a := addition.(type) {
+		case ComponentType:
+			res = append(res, a)
+		case ExtensionAdditionGroup:
+			res = append(res, a.Components...)
+		}
+	}
+	return append(res, t.TrailingRootComponents...)
+}
+
 type ComponentTypeList []ComponentType
 
 type ComponentType interface {
@@ -381,23 +573,52 @@ type NamedComponentType struct {
 	NamedType  NamedType
 	IsOptional bool
 	Default    Value
+	Span       Span
 }
 
 func (NamedComponentType) IsComponentType() {}
 
+func (NamedComponentType) isExtensionAddition() {}
+
 // reference to other SEQUENCE type to be expanded
 type ComponentsOfComponentType struct {
 	Type Type
+	Span Span
 }
 
 func (ComponentsOfComponentType) IsComponentType() {}
 
+func (ComponentsOfComponentType) isExtensionAddition() {}
+
+// ExtensionAddition is component added to SEQUENCE or SET after extension marker: ComponentType or
+// ExtensionAdditionGroup
+type ExtensionAddition interface {
+	isExtensionAddition()
+}
+
+// components added together in [[ ]] brackets
+type ExtensionAdditionGroup struct {
+	Version    Number // zero if version number is not given
+	Components ComponentTypeList
+	Span       Span
+}
+
+func (ExtensionAdditionGroup) isExtensionAddition() {}
+
+// ExceptionSpec is exception identification following "!", see X.680 49.4
+type ExceptionSpec struct {
+	Type  Type // type of exception value, nil if value is SignedNumber or DefinedValue
+	Value Value
+}
+
 // tagged types
 type TaggedType struct {
 	Tag        Tag
 	Type       Type
 	TagType    int  // one of TAGS_*
 	HasTagType bool // true if explicitly set
+	Automatic  bool // true if tag is assigned by automatic tagging
+	Span       Span
 }
 
 func (t TaggedType) Zero() interface{} {
@@ -418,6 +639,7 @@ const (
 
 type SequenceOfType struct {
 	Type Type
+	Span Span
 }
 
 func (SequenceOfType) Zero() interface{} {
@@ -426,6 +648,7 @@ func (SequenceOfType) Zero() interface{} {
 
 type SetOfType struct {
 	Type Type
+	Span Span
 }
 
 func (SetOfType) Zero() interface{} {
@@ -435,6 +658,7 @@ func (SetOfType) Zero() interface{} {
 // BIT STRING with optional named bits
 type BitStringType struct {
 	NamedBits []NamedBit
+	Span      Span
 }
 
 func (BitStringType) Zero() interface{} {
@@ -444,6 +668,7 @@ func (BitStringType) Zero() interface{} {
 type NamedBit struct {
 	Name  Identifier
 	Index Value // Number or DefinedValue
+	Span  Span
 }
 
 ////////////////////////////////////////////////
@@ -451,6 +676,7 @@ type NamedBit struct {
 type ConstraintedType struct {
 	Type       Type
 	Constraint Constraint
+	Span       Span
 }
 
 func (t ConstraintedType) Zero() interface{} {
@@ -564,9 +790,18 @@ type Value interface {
 	Type() Type
 }
 
-// TODO
-// represents value reference
-type DefinedValue struct{}
+// DefinedValue is reference to value assignment, either valuereference or external value reference Module.value
+type DefinedValue struct {
+	ModuleReference ModuleReference // empty if value is referenced without module
+	ValueReference  ValueReference
+}
+
+func (v DefinedValue) String() string {
+	if v.ModuleReference != "" {
+		return fmt.Sprintf("%v.%v", v.ModuleReference, v.ValueReference)
+	}
+	return v.ValueReference.Name()
+}
 
 func (DefinedValue) Type() Type {
 	return nil
@@ -615,6 +850,7 @@ type ObjectIdElement struct {
 	Name      string
 	Id        int
 	Reference *DefinedValue // nil if Id is set explicitly
+	NameForm  bool          // true if component is given by name only, its number is found by name
 }
 
 func (ObjectIdElement) IsObjectIdComponent() bool {
@@ -624,37 +860,89 @@ func (ObjectIdElement) IsObjectIdComponent() bool {
 // end OID
 //////////////////////////////
 
+type NullValue struct{}
+
+func (NullValue) Type() Type {
+	return NullType{}
+}
+
+// ChoiceValue is value of CHOICE alternative named Identifier
+type ChoiceValue struct {
+	Identifier Identifier
+	Value      Value
+}
+
+func (ChoiceValue) Type() Type {
+	return ChoiceType{}
+}
+
+// ContainingValue is BIT STRING or OCTET STRING value holding encoding of Value
+type ContainingValue struct {
+	Value Value
+}
+
+func (ContainingValue) Type() Type {
+	return OctetStringType{}
+}
+
+// BracedValue is value in curly braces as it's parsed, its meaning depends on type governing it, see
+// Registry.ResolveValueNotation. Items are separated by commas, each of them is a sequence of values.
+// NameAndNumberForm of OBJECT IDENTIFIER is kept as ObjectIdentifierValue with single component.
+type BracedValue [][]Value
+
+func (BracedValue) Type() Type {
+	return nil
+}
+
+// NamedValue is value of SEQUENCE or SET component
+type NamedValue struct {
+	Name  Identifier
+	Value Value
+}
+
+// SequenceValue is value of SEQUENCE or SET, components are in order of value notation
+type SequenceValue []NamedValue
+
+func (SequenceValue) Type() Type {
+	return SequenceType{}
+}
+
+// SequenceOfValue is value of SEQUENCE OF or SET OF
+type SequenceOfValue []Value
+
+func (SequenceOfValue) Type() Type {
+	return SequenceOfType{}
+}
+
+// NamedBitListValue is BIT STRING value given by identifiers of bits set to one
+type NamedBitListValue []Identifier
+
+func (NamedBitListValue) Type() Type {
+	return BitStringType{}
+}
+
+// CharacterStringList is character string value given as concatenation of strings and references to them,
+// characters given by Quadruple or Tuple are kept as String
+type CharacterStringList []Value
+
+func (CharacterStringList) Type() Type {
+	return RestrictedStringType{}
+}
+
 const (
 	GeneralizedTimeName = "GeneralizedTime"
 	UTCTimeName         = "UTCTime"
 )
 
-var (
-	USEFUL_TYPES map[string]Type = map[string]Type{
+// builtinUsefulTypes returns types every Compiler knows without them being defined in a module
+func builtinUsefulTypes() map[string]Type {
+	return map[string]Type{
 		GeneralizedTimeName: TaggedType{ // [UNIVERSAL 24] IMPLICIT VisibleString
-			Tag:  Tag{Class: CLASS_UNIVERSAL, ClassNumber: Number(24)},
-			Type: RestrictedStringType{VisibleString}},
+			Tag:        Tag{Class: CLASS_UNIVERSAL, ClassNumber: Number(24)},
+			Type:       RestrictedStringType{VisibleString},
+			TagType:    TAGS_IMPLICIT,
+			HasTagType: true},
 		"BigInt":      BigInt{},
 		"StringStore": StringType{},
 	}
-
-	USEFUL_TYPES_MODULE map[string]string = map[string]string{}
-)
-
-func UpdateTypeList(modules []ModuleDefinition) {
-	for _, module := range modules {
-		for _, assignment := range module.ModuleBody.AssignmentList {
-			name := assignment.Reference().Name()
-			find := module.ModuleBody.AssignmentList.GetType(name)
-			if find != nil {
-				USEFUL_TYPES[name] = find.Type
-				USEFUL_TYPES_MODULE[name] = module.ModuleIdentifier.Reference
-			} else {
-				value := module.ModuleBody.AssignmentList.GetValue(name)
-				if value != nil {
-					fmt.Printf("not support yet")
-				}
-			}
-		}
-	}
 }
@@ -0,0 +1,259 @@
+package asn1go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CheckError describes violation of X.680 rule found by Check
+type CheckError struct {
+	Pos     Position // position of the offending assignment, component or item
+	Module  string
+	Message string
+}
+
+func (e *CheckError) position() Position {
+	return e.Pos
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", e.Pos, e.Module, e.Message)
+}
+
+// CheckErrorList is a list of all violations found by Check, sorted by position
+type CheckErrorList []*CheckError
+
+func (l CheckErrorList) Error() string {
+	return errorList[*CheckError](l).Error()
+}
+
+// Check checks modules for violations of X.680 rules, which parser doesn't enforce, see Registry.Check.
+// References are resolved among modules only.
+func Check(modules []ModuleDefinition) error {
+	registry := NewRegistry()
+	registry.Add(modules...)
+	return registry.Check(modules...)
+}
+
+// Check checks that modules don't have duplicate assignments, that identifiers of components, alternatives,
+// enumerations, named numbers and named bits are distinct, and that effective outermost tags of CHOICE
+// alternatives (X.680 29.2), of SET components (X.680 27.3) and of each run of OPTIONAL or DEFAULT SEQUENCE
+// components together with the component following it (X.680 25.5) are distinct. Extension additions are
+// checked as optional components. References are resolved with registry, types that can't be resolved and
+// recursive untagged CHOICE are reported. Returns CheckErrorList or nil.
+func (r *Registry) Check(modules ...ModuleDefinition) error {
+	var errs CheckErrorList
+	for i := range modules {
+		c := checker{registry: r, module: &modules[i], errs: &errs}
+		c.checkModule()
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	errorList[*CheckError](errs).sort()
+	return errs
+}
+
+type checker struct {
+	registry *Registry
+	module   *ModuleDefinition
+	errs     *CheckErrorList
+}
+
+func (c *checker) report(pos Position, format string, args ...interface{}) {
+	*c.errs = append(*c.errs, &CheckError{Pos: pos, Module: c.module.ModuleIdentifier.Reference, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) checkModule() {
+	defined := map[string]Position{}
+	for _, assignment := range c.module.ModuleBody.AssignmentList {
+		var pos Position
+		switch a := assignment.(type) {
+		case TypeAssignment:
+			pos = a.Span.Start
+			c.checkType(a.TypeReference.Name(), a.Type)
+		case ValueAssignment:
+			pos = a.Span.Start
+			c.checkType(a.ValueReference.Name(), a.Type)
+		}
+		name := assignment.Reference().Name()
+		if first, ok := defined[name]; ok {
+			c.report(pos, "%v is already defined at %v", name, first)
+			continue
+		}
+		defined[name] = pos
+	}
+}
+
+// checkType checks t and types nested in it, path names t in messages
+func (c *checker) checkType(path string, t Type) {
+	switch tt := t.(type) {
+	case TaggedType:
+		c.checkType(path, tt.Type)
+	case ConstraintedType:
+		c.checkType(path, tt.Type)
+	case SequenceOfType:
+		c.checkType(path, tt.Type)
+	case SetOfType:
+		c.checkType(path, tt.Type)
+	case SequenceType, SetType:
+		c.checkComponents(path, tt)
+	case ChoiceType:
+		c.checkChoice(path, tt)
+	case EnumeratedType:
+		items := make([]namedItem, 0, len(tt.Enums)+len(tt.AdditionalEnums))
+		for _, item := range append(append(EnumeratedItemList{}, tt.Enums...), tt.AdditionalEnums...) {
+			items = append(items, namedItem{item.Name, item.Span.Start})
+		}
+		c.checkIdentifiers(path, "ENUMERATED", items)
+	case IntegerType:
+		items := make([]namedItem, 0, len(tt.NamedNumberList))
+		for _, number := range tt.NamedNumberList {
+			items = append(items, namedItem{number.Name, number.Span.Start})
+		}
+		c.checkIdentifiers(path, "INTEGER", items)
+	case BitStringType:
+		items := make([]namedItem, 0, len(tt.NamedBits))
+		for _, bit := range tt.NamedBits {
+			items = append(items, namedItem{bit.Name, bit.Span.Start})
+		}
+		c.checkIdentifiers(path, "BIT STRING", items)
+	}
+}
+
+type namedItem struct {
+	name Identifier
+	pos  Position
+}
+
+func (c *checker) checkIdentifiers(path, kind string, items []namedItem) {
+	seen := map[Identifier]Position{}
+	for _, item := range items {
+		if first, ok := seen[item.name]; ok {
+			c.report(item.pos, "duplicate identifier %v in %v %v, first defined at %v", item.name, kind, path, first)
+			continue
+		}
+		seen[item.name] = item.pos
+	}
+}
+
+// taggedItem is component or alternative with its effective outermost tags, tags is nil if they are unknown
+type taggedItem struct {
+	namedItem
+	tags []EffectiveTag
+}
+
+// checkComponents checks components of SEQUENCE or SET t
+func (c *checker) checkComponents(path string, sequenceOrSet Type) {
+	kind, set := "SEQUENCE", false
+	t, ok := sequenceOrSet.(SequenceType)
+	if !ok {
+		kind, set = "SET", true
+		t = SequenceType(sequenceOrSet.(SetType))
+	}
+	for _, component := range t.AllComponents() {
+		if named, ok := component.(NamedComponentType); ok {
+			c.checkType(path+"."+named.NamedType.Identifier.Name(), named.NamedType.Type)
+		}
+	}
+	expanded, err := c.registry.ExpandComponentsOf(c.module, sequenceOrSet)
+	if err != nil {
+		c.report(t.Span.Start, "%v: %v", path, err)
+		return
+	}
+	if set {
+		t = SequenceType(expanded.(SetType))
+	} else {
+		t = expanded.(SequenceType)
+	}
+	var items []namedItem
+	var run []taggedItem // consecutive optional components of SEQUENCE, all components of SET
+	add := func(f NamedComponentType, extension bool) {
+		items = append(items, namedItem{f.NamedType.Identifier, f.NamedType.Span.Start})
+		item := taggedItem{items[len(items)-1], c.effectiveTags(path, kind, items[len(items)-1], f.NamedType.Type)}
+		c.checkDistinctTags(path, kind, item, run)
+		if set || extension || f.IsOptional || f.Default != nil {
+			run = append(run, item)
+		} else {
+			run = nil
+		}
+	}
+	for _, component := range t.Components {
+		if f, ok := component.(NamedComponentType); ok {
+			add(f, false)
+		}
+	}
+	for _, addition := range t.ExtensionAdditions {
+		switch a := addition.(type) {
+		case NamedComponentType:
+			add(a, true)
+		case ExtensionAdditionGroup:
+			for _, component := range a.Components {
+				if f, ok := component.(NamedComponentType); ok {
+					add(f, true)
+				}
+			}
+		}
+	}
+	for _, component := range t.TrailingRootComponents {
+		if f, ok := component.(NamedComponentType); ok {
+			add(f, false)
+		}
+	}
+	c.checkIdentifiers(path, kind, items)
+}
+
+func (c *checker) checkChoice(path string, t ChoiceType) {
+	var items []namedItem
+	var previous []taggedItem
+	for _, alternative := range t.Alternatives() {
+		c.checkType(path+"."+alternative.Identifier.Name(), alternative.Type)
+		items = append(items, namedItem{alternative.Identifier, alternative.Span.Start})
+		item := taggedItem{items[len(items)-1], c.effectiveTags(path, "CHOICE", items[len(items)-1], alternative.Type)}
+		c.checkDistinctTags(path, "CHOICE", item, previous)
+		previous = append(previous, item)
+	}
+	c.checkIdentifiers(path, "CHOICE", items)
+}
+
+// checkDistinctTags reports tags of item, which are also tags of some of others, once for each of others.
+// Alternatives of untagged CHOICE may share a tag, so it's reported once.
+func (c *checker) checkDistinctTags(path, kind string, item taggedItem, others []taggedItem) {
+	var distinct taggedItem
+	for _, tag := range item.tags {
+		if !distinct.hasTag(tag) {
+			distinct.tags = append(distinct.tags, tag)
+		}
+	}
+	for _, other := range others {
+		for _, tag := range distinct.tags {
+			if other.hasTag(tag) {
+				c.report(item.pos, "tag %v of %v is not distinct from tag of %v in %v %v", tag, item.name, other.name, kind, path)
+			}
+		}
+	}
+}
+
+func (i taggedItem) hasTag(tag EffectiveTag) bool {
+	for _, t := range i.tags {
+		if t.Is(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveTags returns outermost tags of type t of item, nil if they are unknown. Open types have no tags and
+// imports are checked by Registry.ResolveImports, other types without tags, like recursive untagged CHOICE or
+// undefined references, are reported.
+func (c *checker) effectiveTags(path, kind string, item namedItem, t Type) []EffectiveTag {
+	_, resolved, _, err := c.registry.resolveUntagged(c.module, t, nil)
+	if reference, ok := resolved.(TypeReference); err == nil && ok && reference.Name() == "ANY" {
+		return nil
+	}
+	tags, err := c.registry.EffectiveTags(c.module, t)
+	if err != nil && !errors.As(err, &importError{}) {
+		c.report(item.pos, "tags of %v in %v %v are unknown: %v", item.name, kind, path, err)
+	}
+	return tags
+}
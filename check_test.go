@@ -0,0 +1,75 @@
+package asn1go
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	modules, err := ParseString(`Defs DEFINITIONS ::= BEGIN
+		Name ::= [APPLICATION 1] UTF8String
+		Base ::= SEQUENCE { id INTEGER OPTIONAL }
+	END
+	Test DEFINITIONS ::= BEGIN
+		IMPORTS Name, Base FROM Defs;
+		app INTEGER ::= 1
+		Choice ::= CHOICE { a INTEGER, b [APPLICATION 1] BOOLEAN, c Name }
+		Nested ::= CHOICE { x CHOICE { y NULL, z BOOLEAN }, w [0] NULL, v BOOLEAN }
+		Set ::= SET { a INTEGER, b [APPLICATION app] Name, c [1] INTEGER, d Name }
+		Seq ::= SEQUENCE { a INTEGER OPTIONAL, b BOOLEAN OPTIONAL, c INTEGER, d INTEGER }
+		Extended ::= SEQUENCE { a INTEGER, ..., b BOOLEAN, c BOOLEAN }
+		Included ::= SEQUENCE { COMPONENTS OF Base, id INTEGER }
+		Items ::= SEQUENCE { e ENUMERATED { x, y, x }, i INTEGER { one(1), one(2) }, b BIT STRING { f(0), f(1) } }
+		Seq ::= INTEGER
+		Rec ::= CHOICE { a Rec, b INTEGER }
+		Ref ::= SEQUENCE { u Undefined, n NULL }
+		Cit ::= SEQUENCE { authors SEQUENCE OF INTEGER OPTIONAL, from CHOICE { b [0] SEQUENCE OF NULL, c SEQUENCE { d NULL }, e SEQUENCE OF BOOLEAN, f [1] SEQUENCE OF NULL } }
+	END
+	Valid DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Choice ::= CHOICE { a INTEGER, b INTEGER, c CHOICE { d INTEGER } }
+		Seq ::= SEQUENCE { a INTEGER OPTIONAL, b INTEGER OPTIONAL, ..., c INTEGER }
+		Set ::= SET { a INTEGER, b INTEGER }
+		Recursive ::= CHOICE { r [0] Recursive, n NULL }
+		Open ::= SEQUENCE { a INTEGER, b ANY }
+	END
+	Importing DEFINITIONS ::= BEGIN
+		IMPORTS Missing FROM Nowhere;
+		Uses ::= CHOICE { m Missing, n NULL }
+	END`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if err := Check(modules[2:]); err != nil {
+		t.Errorf("Unexpected errors: %v", err)
+	}
+	err = Check(modules)
+	errs, ok := err.(CheckErrorList)
+	if !ok {
+		t.Fatalf("Expected CheckErrorList, got %v", err)
+	}
+	expected := []string{
+		"8:61: Test: tag [APPLICATION 1] of c is not distinct from tag of b in CHOICE Choice",
+		"9:67: Test: tag [UNIVERSAL 1] of v is not distinct from tag of x in CHOICE Nested",
+		"10:69: Test: tag [APPLICATION 1] of d is not distinct from tag of b in SET Set",
+		"11:62: Test: tag [UNIVERSAL 2] of c is not distinct from tag of a in SEQUENCE Seq",
+		"12:54: Test: tag [UNIVERSAL 1] of c is not distinct from tag of b in SEQUENCE Extended",
+		"13:47: Test: tag [UNIVERSAL 2] of id is not distinct from tag of id in SEQUENCE Included",
+		"13:47: Test: duplicate identifier id in SEQUENCE Included, first defined at 3:23",
+		"14:45: Test: duplicate identifier x in ENUMERATED Items.e, first defined at 14:39",
+		"14:70: Test: duplicate identifier one in INTEGER Items.i, first defined at 14:62",
+		"14:101: Test: duplicate identifier f in BIT STRING Items.b, first defined at 14:95",
+		"15:3: Test: Seq is already defined at 11:3",
+		"16:20: Test: tags of a in CHOICE Rec are unknown: alternative a: type Rec is recursive without tag",
+		"17:22: Test: tags of u in SEQUENCE Ref are unknown: type Undefined is not defined",
+		"18:60: Test: tag [UNIVERSAL 16] of from is not distinct from tag of authors in SEQUENCE Cit",
+		"18:121: Test: tag [UNIVERSAL 16] of e is not distinct from tag of c in CHOICE Cit.from",
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %v errors, got %v: %v", len(expected), len(errs), errs)
+	}
+	for i, exp := range expected {
+		if i < len(errs) && !strings.HasPrefix(errs[i].Error(), exp) {
+			t.Errorf("Expected error %q, got %q", exp, errs[i].Error())
+		}
+	}
+}
@@ -8,17 +8,39 @@ import (
 )
 
 var usage = `
-asn1go [[input] output]
+asn1go [-import file]... [-import-prefix path] [[input] output]
+asn1go check [-import file]... [input]
 
 Generates go file from input and writes to output.
 If output is omitted, uses stdout. If input is omitted,
-reads from stdin.
+reads from stdin. Files given with -import provide modules
+imported by input, code is not generated for them.
+Packages generated for imported modules are imported
+from -import-prefix path followed by module name.
+
+The check command reports violations of X.680 rules in
+modules of input, such as duplicate names or tags that
+are not distinct, and exits with status 1 if any is found.
 `
 
 type flagsType struct {
-	inputName   string
-	outputName  string
-	packageName string
+	inputName    string
+	outputName   string
+	packageName  string
+	importPrefix string
+	importNames  fileList
+}
+
+// fileList collects values of repeated flag
+type fileList []string
+
+func (l *fileList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *fileList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
 
 func failWithError(format string, args ...interface{}) {
@@ -27,9 +49,19 @@ func failWithError(format string, args ...interface{}) {
 	os.Exit(1)
 }
 
+// errorsFound returns summary line printed after count errors
+func errorsFound(count int) string {
+	if count == 1 {
+		return "1 error found"
+	}
+	return fmt.Sprintf("%v errors found", count)
+}
+
 func parseFlags(args []string) (res flagsType) {
 	cmd := flag.NewFlagSet(args[0], flag.ExitOnError)
 	cmd.StringVar(&res.packageName, "package", "", "package name for generated code")
+	cmd.Var(&res.importNames, "import", "file with modules imported by input, may be repeated")
+	cmd.StringVar(&res.importPrefix, "import-prefix", "", "import path of packages generated for imported modules")
 	cmd.Parse(args[1:])
 	if cmd.NArg() > 0 {
 		res.inputName = cmd.Arg(0)
@@ -62,23 +94,97 @@ func openChannels(inputName, outputName string) (input, output *os.File) {
 	return input, output
 }
 
+// filePrefix returns prefix for error positions in input file
+func filePrefix(inputName string) string {
+	if len(inputName) == 0 {
+		return ""
+	}
+	return inputName + ":"
+}
+
+// reportParseErrors prints parse errors and exits, prefix is prepended to positions without file name
+func reportParseErrors(prefix string, err error) {
+	if errs, ok := err.(asn1go.ErrorList); ok {
+		for _, parseErr := range errs {
+			if len(parseErr.Pos.File) > 0 {
+				prefix = ""
+			}
+			fmt.Fprintf(os.Stderr, "%v%v\n%v\n", prefix, parseErr.Error(), parseErr.Snippet)
+		}
+		failWithError(errorsFound(len(errs)))
+	} else if err != nil {
+		failWithError(err.Error())
+	}
+}
+
+// parseInput parses input together with modules it imports and reports import errors,
+// returns modules of input
+func parseInput(compiler *asn1go.Compiler, flags flagsType, input *os.File) []asn1go.ModuleDefinition {
+	if err := compiler.Registry().LoadFiles(flags.importNames...); err != nil {
+		reportParseErrors("", err)
+	}
+	modules, err := compiler.ParseStream(input)
+	reportParseErrors(filePrefix(flags.inputName), err)
+	if err := compiler.Registry().ResolveImports(); err != nil {
+		// code can still be generated for modules with unresolved imports
+		for _, importErr := range err.(asn1go.ImportErrorList) {
+			prefix := filePrefix(flags.inputName)
+			if len(importErr.Pos.File) > 0 {
+				prefix = ""
+			}
+			warning := ""
+			if importErr.Cycle != nil {
+				// X.680 allows modules to import each other
+				warning = "warning: "
+			}
+			fmt.Fprintf(os.Stderr, "%v%v%v\n", warning, prefix, importErr.Error())
+		}
+	}
+	return modules
+}
+
+// check reports violations of X.680 rules in input and exits with status 1 if there are any
+func check(args []string) {
+	flags := parseFlags(args)
+	if len(flags.outputName) != 0 {
+		failWithError(usage)
+	}
+	input, _ := openChannels(flags.inputName, "")
+	compiler := asn1go.NewCompiler()
+	modules := parseInput(compiler, flags, input)
+	input.Close()
+	if err := compiler.Registry().Check(modules...); err != nil {
+		errs := err.(asn1go.CheckErrorList)
+		for _, checkErr := range errs {
+			prefix := filePrefix(flags.inputName)
+			if len(checkErr.Pos.File) > 0 {
+				prefix = ""
+			}
+			fmt.Fprintf(os.Stderr, "%v%v\n", prefix, checkErr.Error())
+		}
+		failWithError(errorsFound(len(errs)))
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		check(os.Args[1:])
+		return
+	}
 	flags := parseFlags(os.Args)
 	input, output := openChannels(flags.inputName, flags.outputName)
 
-	modules, err := asn1go.ParseStream(input)
-	if err != nil {
-		failWithError(err.Error())
-	}
+	compiler := asn1go.NewCompiler()
+	modules := parseInput(compiler, flags, input)
 
-	asn1go.UpdateTypeList(modules)
+	compiler.UpdateTypeList(modules)
 	params := asn1go.GenParams{
-		Package: flags.packageName,
+		Package:      flags.packageName,
+		ImportPrefix: flags.importPrefix,
 	}
 	for _, module := range modules {
-		gen := asn1go.NewCodeGenerator(params)
-		err = gen.Generate(module, output)
-		if err != nil {
+		gen := compiler.NewCodeGenerator(params)
+		if err := gen.Generate(module, output); err != nil {
 			failWithError(err.Error())
 		}
 	}
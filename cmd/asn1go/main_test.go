@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestErrorsFound(t *testing.T) {
+	for count, expected := range map[int]string{1: "1 error found", 2: "2 errors found", 10: "10 errors found"} {
+		if got := errorsFound(count); got != expected {
+			t.Errorf("Expected %q for %v errors, got %q", expected, count, got)
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package asn1go
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	goast "go/ast"
+	goparser "go/parser"
 	goprint "go/printer"
 	gotoken "go/token"
 	"io"
+	"math"
+	"path"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -19,9 +24,10 @@ type CodeGenerator interface {
 }
 
 type GenParams struct {
-	Package string
-	Prefix  string
-	Type    GenType
+	Package      string
+	Prefix       string
+	Type         GenType
+	ImportPrefix string // import path of packages generated for other modules, without module name
 }
 
 type GenType int
@@ -30,26 +36,34 @@ const (
 	GEN_DECLARATIONS GenType = iota
 )
 
+// NewCodeGenerator returns generator that knows only built-in useful types,
+// use Compiler.NewCodeGenerator to resolve references to types of other modules
 func NewCodeGenerator(params GenParams) CodeGenerator {
-	switch params.Type {
-	case GEN_DECLARATIONS:
-		return &declCodeGen{params}
-	default:
-		return nil
-	}
+	return NewCompiler().NewCodeGenerator(params)
 }
 
 type declCodeGen struct {
-	Params GenParams
+	Params   GenParams
+	compiler *Compiler
 }
 
 type moduleContext struct {
+	compiler             *Compiler
+	module               *ModuleDefinition
 	extensibilityImplied bool
 	tagDefault           int
 	errors               []error
 	lookupContext        ModuleBody
 	requiredModules      []string
 	comments             []*goast.CommentGroup
+	types                *typeGraph                   // references between types of module to find ones that have to be pointers
+	owner                string                       // type whose declaration or value is generated, empty for inline types of values
+	scope                inlineScope                  // type which inline types generated now are named after
+	inlineNames          map[string]map[string]string // names of inline types by module, see inlineTypeNames
+	inlineDecls          []goast.Decl                 // declarations of inline types not added to file yet
+	declaredInline       map[string]bool
+	reportedCycles       map[string]bool // keys of value reference cycles reported, see valueCycleError
+	importPrefix         string          // see GenParams.ImportPrefix
 }
 
 func (ctx *moduleContext) appendError(err error) {
@@ -67,26 +81,40 @@ func (ctx *moduleContext) requireModule(module string) {
 	ctx.requiredModules = append(ctx.requiredModules, module)
 }
 
+// requireGeneratedModule imports package generated for ASN.1 module, its import path is module name prefixed with
+// GenParams.ImportPrefix
+func (ctx *moduleContext) requireGeneratedModule(module string) {
+	ctx.requireModule(path.Join(ctx.importPrefix, goifyName(module)))
+}
+
 /** Generate declarations from module
 
 Feature support status:
  - [x] ModuleIdentifier
- - [ ] TagDefault
+ - [x] TagDefault
  - [ ] ExtensibilityImplied
  - [.] ModuleBody -- see generateDeclarations
 */
 func (gen declCodeGen) Generate(module ModuleDefinition, writer io.Writer) error {
 	ctx := moduleContext{
+		compiler:             gen.compiler,
+		module:               &module,
 		extensibilityImplied: module.ExtensibilityImplied,
 		tagDefault:           module.TagDefault,
 		lookupContext:        module.ModuleBody,
 		comments:             make([]*goast.CommentGroup, 0),
+		types:                newTypeGraph(gen.compiler.registry, &module),
+		inlineNames:          map[string]map[string]string{},
+		declaredInline:       map[string]bool{},
+		reportedCycles:       map[string]bool{},
+		importPrefix:         gen.Params.ImportPrefix,
 	}
 	moduleName := goast.NewIdent(goifyName(module.ModuleIdentifier.Reference))
 	if len(gen.Params.Package) > 0 {
 		moduleName = goast.NewIdent(gen.Params.Package)
 	}
 	ast := &goast.File{
+		Doc:   docComment(module.Doc, nil),
 		Name:  moduleName,
 		Decls: ctx.generateDeclarations(module),
 	}
@@ -99,8 +127,7 @@ func (gen declCodeGen) Generate(module ModuleDefinition, writer io.Writer) error
 	}
 	for _, v := range module.ModuleBody.Imports {
 
-		mname := goifyName(v.Module.Reference)
-		ctx.requireModule(mname)
+		ctx.requireGeneratedModule(v.Module.Reference)
 		// for _, Symbol := range v.SymbolList {
 		// 	switch t := Symbol.(type) {
 		// 	case TypeReference:
@@ -132,7 +159,118 @@ func (gen declCodeGen) Generate(module ModuleDefinition, writer io.Writer) error
 
 	ast.Decls = append(importDecls, ast.Decls...)
 	ast.Comments = append(ast.Comments, ctx.comments...)
-	return goprint.Fprint(writer, gotoken.NewFileSet(), ast)
+	return printFile(writer, ast)
+}
+
+// printFile prints generated file. Printer misplaces doc comments of nodes without positions and separates
+// declarations without positions only if their kinds differ, so doc comments are taken out of the tree, the code is
+// printed and parsed back, comments and blank lines are inserted as text above lines of their nodes and the result
+// is parsed and printed again.
+func printFile(writer io.Writer, file *goast.File) error {
+	docs := takeDocComments(file)
+	var code bytes.Buffer
+	if err := goprint.Fprint(&code, gotoken.NewFileSet(), file); err != nil {
+		return err
+	}
+	fset := gotoken.NewFileSet()
+	parsed, err := goparser.ParseFile(fset, "", code.Bytes(), goparser.ParseComments)
+	if err != nil {
+		return err
+	}
+	nodes := docNodes(parsed)
+	if len(nodes) != len(docs) {
+		return fmt.Errorf("failed to place doc comments: %d nodes in printed code, %d expected", len(nodes), len(docs))
+	}
+	// declarations spanning several lines are separated from adjacent ones, one-line declarations stay together
+	separated := map[goast.Node]bool{}
+	lines := func(decl goast.Decl) int {
+		return fset.Position(decl.End()).Line - fset.Position(decl.Pos()).Line + 1
+	}
+	for i := 1; i < len(parsed.Decls); i++ {
+		if lines(parsed.Decls[i-1]) > 1 || lines(parsed.Decls[i]) > 1 {
+			separated[parsed.Decls[i]] = true
+		}
+	}
+	src := code.Bytes()
+	var res bytes.Buffer
+	last := 0
+	for i, node := range nodes {
+		if docs[i] == nil && !separated[node] {
+			continue
+		}
+		pos := fset.Position(node.Pos())
+		lineStart := pos.Offset - pos.Column + 1
+		res.Write(src[last:lineStart])
+		last = lineStart
+		indent := src[lineStart:pos.Offset]
+		if separated[node] && lineStart > 1 && src[lineStart-2] != '\n' {
+			res.WriteByte('\n')
+		}
+		if docs[i] == nil {
+			continue
+		}
+		for _, comment := range docs[i].List {
+			res.Write(indent)
+			res.WriteString(comment.Text)
+			res.WriteByte('\n')
+		}
+	}
+	res.Write(src[last:])
+	fset = gotoken.NewFileSet()
+	parsed, err = goparser.ParseFile(fset, "", res.Bytes(), goparser.ParseComments)
+	if err != nil {
+		return err
+	}
+	return goprint.Fprint(writer, fset, parsed)
+}
+
+// docNodes lists nodes that can have doc comment in the order printer outputs them
+func docNodes(file *goast.File) []goast.Node {
+	var res []goast.Node
+	goast.Inspect(file, func(node goast.Node) bool {
+		switch node.(type) {
+		case *goast.File, *goast.GenDecl, *goast.FuncDecl, *goast.Field:
+			res = append(res, node)
+		}
+		return true
+	})
+	return res
+}
+
+// takeDocComments removes doc comments from nodes listed by docNodes and returns them in the same order
+func takeDocComments(file *goast.File) []*goast.CommentGroup {
+	var docs []*goast.CommentGroup
+	for _, node := range docNodes(file) {
+		var doc *goast.CommentGroup
+		switch x := node.(type) {
+		case *goast.File:
+			doc, x.Doc = x.Doc, nil
+		case *goast.GenDecl:
+			doc, x.Doc = x.Doc, nil
+		case *goast.FuncDecl:
+			doc, x.Doc = x.Doc, nil
+		case *goast.Field:
+			doc, x.Doc = x.Doc, nil
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// docComment converts ASN.1 comments preceding node and following it on the same line into Go doc comment
+func docComment(doc CommentGroup, line CommentGroup) *goast.CommentGroup {
+	lines := append(doc.Lines(), line.Lines()...)
+	if len(lines) == 0 {
+		return nil
+	}
+	res := &goast.CommentGroup{}
+	for _, text := range lines {
+		if len(text) > 0 {
+			text = " " + text
+		}
+		res.List = append(res.List, &goast.Comment{Text: "//" + text})
+	}
+	return res
 }
 func IsUpper(s string) bool {
 	for _, r := range s {
@@ -177,8 +315,8 @@ func goifyName(name string) string {
 /** generateDeclarations based on ModuleBody of module
 
 Feature support status:
- - [.] AssignmentList
-   - [ ] ValueAssignment
+ - [x] AssignmentList
+   - [x] ValueAssignment
    - [x] TypeAssignment
  - [ ] Imports
 */
@@ -187,51 +325,480 @@ func (ctx *moduleContext) generateDeclarations(module ModuleDefinition) []goast.
 	for _, assignment := range module.ModuleBody.AssignmentList {
 		switch a := assignment.(type) {
 		case TypeAssignment:
-			decls = append(decls, ctx.generateTypeDecl(a.TypeReference, a.Type))
+			decl := ctx.generateTypeDecl(a.TypeReference, a.Type)
+			decl.Doc = docComment(a.Doc, a.LineComment)
+			decls = append(decls, decl)
+			decls = append(decls, ctx.generateNamedValues(goifyName(a.TypeReference.Name()), a.TypeReference, a.Type)...)
+			decls = append(decls, ctx.generateNamedBits(a.TypeReference, a.Type)...)
+			leave := ctx.setOwner(&TypeAssignment{TypeReference: a.TypeReference})
+			decls = append(decls, ctx.generateCheck(goifyName(a.TypeReference.Name()), a.Type)...)
+			leave()
 		case ValueAssignment:
-			// decls = append(decls, ctx.generateValueCommentDecl(a.ValueReference, a.Type, a.Value))
-			decls = append(decls, ctx.generateValueDecl(a.ValueReference, a.Type, a.Value))
-			// fmt.Println("not support yet")
+			if decl := ctx.generateValueDecl(a.ValueReference, a.Type, a.Value); decl != nil {
+				decl.Doc = docComment(a.Doc, a.LineComment)
+				decls = append(decls, decl)
+			}
 		}
+		// inline types follow the declaration they are defined in
+		decls = append(decls, ctx.inlineDecls...)
+		ctx.inlineDecls = nil
 	}
 
 	return decls
 }
 
-// func (ctx *moduleContext) generateValueCommentDecl(reference ValueReference, typeDescr Type, value Value) goast.Decl {
-// 	return &goast.GenDecl{
-// 		Tok: gotoken.COMMENT,
-// 		Specs: []goast.Spec{
-// 			&goast.ValueSpec{
-// 				Comment: ctx.commentFromType(typeDescr, reference.Name()),
-// 			},
-// 		},
-// 	}
-// }
-func (ctx *moduleContext) generateValueDecl(reference ValueReference, typeDescr Type, value Value) goast.Decl {
+// generateValueDecl generates constant for value of type with basic Go type and variable for others,
+// Go type of value is the type generated for its declared type. It returns nil if references in value can't
+// be resolved.
+func (ctx *moduleContext) generateValueDecl(reference ValueReference, typeDescr Type, value Value) *goast.GenDecl {
+	value, err := ctx.compiler.registry.ResolveValueNotation(ctx.module, typeDescr, value)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("value %v: %v", reference.Name(), err))
+	}
+	resolved, err := ctx.compiler.registry.ResolveValue(ctx.module, value)
+	if err != nil {
+		ctx.appendValueError(reference, err)
+		return nil
+	}
+	namedType := ctx.namedValueType(typeDescr)
+	if _, ok := withoutTags(typeDescr).(ObjectIdentifierType); ok {
+		return ctx.generateObjectIdentifierDecl(reference, namedType, value)
+	}
+	if _, ok := resolved.(ObjectIdentifierValue); ok {
+		return ctx.generateObjectIdentifierDecl(reference, namedType, resolved)
+	}
+	defer ctx.enterScope("", ctx.valueName(reference))()
+	goType := namedType
+	if goType == nil {
+		goType = ctx.generateTypeBody(typeDescr, true)
+	}
+	expr, isConst, err := ctx.generateValueExpr(typeDescr, goType, "", resolved)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("value %v: %v", reference.Name(), err))
+	}
+	spec := &goast.ValueSpec{
+		Names:  []*goast.Ident{goast.NewIdent(ctx.valueName(reference))},
+		Values: []goast.Expr{expr},
+	}
+	if ident, ok := expr.(*goast.Ident); ok && ident.Name == "nil" {
+		spec.Type = goType
+	}
+	if !isConst {
+		// composite literals and function calls carry their type
+		return &goast.GenDecl{Tok: gotoken.VAR, Specs: []goast.Spec{spec}}
+	}
+	spec.Type = goType
+	return &goast.GenDecl{Tok: gotoken.CONST, Specs: []goast.Spec{spec}}
+}
+
+// appendValueError reports error resolving value of reference, cycle of value references is reported once for
+// all values in it
+func (ctx *moduleContext) appendValueError(reference ValueReference, err error) {
+	var cycle *valueCycleError
+	if !errors.As(err, &cycle) {
+		ctx.appendError(fmt.Errorf("value %v: %v", reference.Name(), err))
+		return
+	}
+	if !ctx.reportedCycles[cycle.key()] {
+		ctx.reportedCycles[cycle.key()] = true
+		ctx.appendError(err)
+	}
+}
+
+// namedValueType returns Go type declared for type t of value if t references type of module, which fields
+// use as encoding/asn1 type, like asn1.BitString, nil otherwise. Values of such types are declared with the
+// type they are defined with.
+func (ctx *moduleContext) namedValueType(t Type) goast.Expr {
+	reference, ok := withoutTags(t).(TypeReference)
+	if !ok || ctx.module.ModuleBody.AssignmentList.GetType(reference.Name()) == nil {
+		return nil
+	}
+	assignment := ctx.resolveTypeReference(reference)
+	if assignment == nil || ctx.generateSpecialCase(*assignment, "") == nil {
+		return nil
+	}
+	return goast.NewIdent(goifyName(reference.Name()))
+}
 
-	names, values := ctx.generateValueBody(value)
+func (ctx *moduleContext) valueName(reference ValueReference) string {
+	return valueName(ctx.module, reference)
+}
+
+// valueName returns Go name of value, it gets suffix Value if type with the same Go name is defined in module
+func valueName(module *ModuleDefinition, reference ValueReference) string {
+	name := goifyName(reference.Name())
+	for _, assignment := range module.ModuleBody.AssignmentList {
+		if a, ok := assignment.(TypeAssignment); ok && goifyName(a.TypeReference.Name()) == name {
+			return name + "Value"
+		}
+	}
+	return name
+}
+
+// generateValueExpr generates expression for resolved value of type t, which is generated as goType. Expression
+// is constant if isConst is true, otherwise its type is goType. Identifiers of named numbers are given as constants
+// prefixed with constPrefix if it is not empty.
+func (ctx *moduleContext) generateValueExpr(t Type, goType goast.Expr, constPrefix string, value Value) (expr goast.Expr, isConst bool, err error) {
+	if _, ok := value.(ContainingValue); ok {
+		return nil, false, errors.New("CONTAINING values are not supported")
+	}
+	switch tt := withoutTags(t).(type) {
+	case TypeReference:
+		if tt.Name() == GeneralizedTimeName || tt.Name() == UTCTimeName {
+			return nil, false, fmt.Errorf("values of %v are not supported", tt.Name())
+		}
+		assignment := ctx.resolveTypeReference(tt)
+		if assignment == nil {
+			return nil, false, fmt.Errorf("can not resolve type %v", tt.Name())
+		}
+		if ident, ok := goType.(*goast.Ident); ok && assignment.Module == "" && assignment.TypeReference == tt && (ident.Name == goifyName(tt.Name()) || ident.Name == "asn1.Enumerated") {
+			// constants of named values are generated only for the type itself, fields of ENUMERATED types are
+			// asn1.Enumerated
+			constPrefix = goifyName(tt.Name())
+		}
+		defer ctx.setOwner(assignment)()
+		return ctx.generateValueExpr(assignment.Type, goType, constPrefix, value)
+	case ExternalTypeReference:
+		assignment := ctx.resolveExternalTypeReference(tt)
+		if assignment == nil {
+			return nil, false, fmt.Errorf("can not resolve type %v", tt)
+		}
+		defer ctx.setOwner(assignment)()
+		return ctx.generateValueExpr(assignment.Type, goType, "", value)
+	case BooleanType:
+		if v, ok := value.(Boolean); ok {
+			return goast.NewIdent(strconv.FormatBool(bool(v))), true, nil
+		}
+	case IntegerType, EnumeratedType:
+		switch v := value.(type) {
+		case Number:
+			return intLit(v.IntValue()), true, nil
+		case IdentifiedIntegerValue:
+			var values []namedValue
+			if integer, ok := tt.(IntegerType); ok {
+				values = ctx.integerValues(TypeReference(constPrefix), integer)
+			} else {
+				values = ctx.enumeratedValues(TypeReference(constPrefix), tt.(EnumeratedType))
+			}
+			for _, named := range values {
+				if named.name.Name() != v.Name {
+					continue
+				} else if ident, ok := goType.(*goast.Ident); ok && constPrefix != "" && ident.Name == "asn1.Enumerated" {
+					return call(goType, goast.NewIdent(constPrefix+goifyName(v.Name))), true, nil
+				} else if constPrefix != "" {
+					return goast.NewIdent(constPrefix + goifyName(v.Name)), true, nil
+				}
+				return intLit(named.number), true, nil
+			}
+			return nil, false, fmt.Errorf("%v is neither named number nor value", v.Name)
+		}
+	case RealType:
+		switch v := value.(type) {
+		case Number:
+			return intLit(v.IntValue()), true, nil
+		case Real:
+			f := float64(v)
+			if !math.IsInf(f, 0) && !math.IsNaN(f) {
+				return &goast.BasicLit{Kind: gotoken.FLOAT, Value: strconv.FormatFloat(f, 'g', -1, 64)}, true, nil
+			}
+			ctx.requireModule("math")
+			expr = call(selector("math", "NaN"))
+			if math.IsInf(f, 1) {
+				expr = call(selector("math", "Inf"), intLit(1))
+			} else if math.IsInf(f, -1) {
+				expr = call(selector("math", "Inf"), intLit(-1))
+			}
+			if ident, ok := goType.(*goast.Ident); ok && ident.Name == "float64" {
+				return expr, false, nil
+			}
+			return call(goType, expr), false, nil
+		}
+	case CharacterStringType, RestrictedStringType, StringType:
+		switch v := value.(type) {
+		case String:
+			return stringLit(string(v)), true, nil
+		case CharacterStringList:
+			var concatenated strings.Builder
+			for _, element := range v {
+				str, ok := ctx.lookupValue(element).(String)
+				if !ok {
+					return nil, false, fmt.Errorf("%v in %v is not a string", element, value)
+				}
+				concatenated.WriteString(string(str))
+			}
+			return stringLit(concatenated.String()), true, nil
+		}
+	case NullType:
+		if _, ok := value.(NullValue); ok {
+			return goast.NewIdent("nil"), false, nil
+		}
+	case SequenceType, SetType:
+		components, ok := value.(SequenceValue)
+		if !ok {
+			break
+		}
+		expanded, err := ctx.compiler.registry.ExpandComponentsOf(ctx.module, tt)
+		if err != nil {
+			return nil, false, err
+		}
+		sequence, isSequence := expanded.(SequenceType)
+		kind := "SEQUENCE"
+		if !isSequence {
+			sequence = SequenceType(expanded.(SetType))
+			kind = "SET"
+		}
+		var named []NamedComponentType
+		for _, c := range sequence.AllComponents() {
+			if n, ok := c.(NamedComponentType); ok {
+				named = append(named, n)
+			}
+		}
+		// components of SEQUENCE value come in order of the type, components of SET value in any order
+		given := make(map[Identifier]Value, len(components))
+		last := -1
+		for _, component := range components {
+			index := -1
+			for i, c := range named {
+				if c.NamedType.Identifier == component.Name {
+					index = i
+				}
+			}
+			if index < 0 {
+				return nil, false, fmt.Errorf("%v has no component %v", kind, component.Name)
+			} else if _, ok := given[component.Name]; ok {
+				return nil, false, fmt.Errorf("component %v of %v is given more than once", component.Name, kind)
+			} else if isSequence && index < last {
+				return nil, false, fmt.Errorf("component %v of %v is out of order", component.Name, kind)
+			}
+			given[component.Name] = component.Value
+			last = index
+		}
+		elts := make([]goast.Expr, 0, len(named))
+		for _, c := range named {
+			value, ok := given[c.NamedType.Identifier]
+			if !ok && c.Default != nil {
+				// omitted component takes its default value
+				value, err = ctx.compiler.registry.ResolveValueNotation(ctx.module, c.NamedType.Type, c.Default)
+				if err != nil {
+					return nil, false, fmt.Errorf("default of component %v: %v", c.NamedType.Identifier, err)
+				}
+			} else if !ok && c.IsOptional {
+				continue
+			} else if !ok {
+				return nil, false, fmt.Errorf("%v value lacks component %v", kind, c.NamedType.Identifier)
+			}
+			field, err := ctx.generateFieldValue(c.NamedType.Identifier, c.NamedType.Type, value)
+			if err != nil {
+				return nil, false, err
+			}
+			elts = append(elts, field)
+		}
+		return &goast.CompositeLit{Type: goType, Elts: elts}, false, nil
+	case ChoiceType:
+		choice, ok := value.(ChoiceValue)
+		if !ok {
+			break
+		}
+		for _, alternative := range tt.Alternatives() {
+			if alternative.Identifier == choice.Identifier {
+				field, err := ctx.generateFieldValue(choice.Identifier, alternative.Type, choice.Value)
+				if err != nil {
+					return nil, false, err
+				}
+				return &goast.CompositeLit{Type: goType, Elts: []goast.Expr{field}}, false, nil
+			}
+		}
+		return nil, false, fmt.Errorf("CHOICE has no alternative %v", choice.Identifier)
+	case SequenceOfType, SetOfType:
+		elements, ok := value.(SequenceOfValue)
+		if !ok {
+			break
+		}
+		var elementType Type
+		if sequenceOf, isSequenceOf := tt.(SequenceOfType); isSequenceOf {
+			elementType = sequenceOf.Type
+		} else {
+			elementType = tt.(SetOfType).Type
+		}
+		var elementGoType goast.Expr
+		if array, ok := goType.(*goast.ArrayType); ok {
+			// elements of SEQUENCE OF defined inline in component are named after the component
+			elementGoType = array.Elt
+		} else {
+			elementGoType = ctx.generateInlineType(inlineItem, elementType, true)
+		}
+		elts := make([]goast.Expr, 0, len(elements))
+		for _, element := range elements {
+			expr, _, err := ctx.generateValueExpr(elementType, elementGoType, "", ctx.lookupValue(element))
+			if err != nil {
+				return nil, false, err
+			}
+			elts = append(elts, expr)
+		}
+		return &goast.CompositeLit{Type: goType, Elts: elts}, false, nil
+	case BitStringType:
+		var bits BitString
+		switch v := value.(type) {
+		case BitString:
+			bits = v
+		case OctetString:
+			bits = BitString{Bytes: v, BitLength: len(v) * 8}
+		case NamedBitListValue:
+			// the highest bit set is the last one, as DER requires for named bit lists
+			for _, name := range v {
+				index := -1
+				for _, bit := range tt.NamedBits {
+					if bit.Name != name {
+						continue
+					} else if number, ok := ctx.namedNumber(TypeReference(constPrefix), bit.Name, bit.Index); ok {
+						index = number
+					}
+				}
+				if index < 0 {
+					return nil, false, fmt.Errorf("%v is not a named bit", name)
+				}
+				if index >= bits.BitLength {
+					bits.BitLength = index + 1
+					bits.Bytes = append(bits.Bytes, make([]byte, (bits.BitLength+7)/8-len(bits.Bytes))...)
+				}
+				bits.Bytes[index/8] |= 0x80 >> uint(index%8)
+			}
+		default:
+			return nil, false, fmt.Errorf("expected BIT STRING value, got %v", value)
+		}
+		return &goast.CompositeLit{Type: goType, Elts: []goast.Expr{
+			&goast.KeyValueExpr{Key: goast.NewIdent("Bytes"), Value: bytesLit(&goast.ArrayType{Elt: goast.NewIdent("byte")}, bits.Bytes)},
+			&goast.KeyValueExpr{Key: goast.NewIdent("BitLength"), Value: intLit(bits.BitLength)},
+		}}, false, nil
+	case OctetStringType:
+		switch v := value.(type) {
+		case OctetString:
+			return bytesLit(goType, v), false, nil
+		case BitString:
+			// bits are padded with zeros to the octet boundary
+			return bytesLit(goType, v.Bytes), false, nil
+		}
+	default:
+		return nil, false, fmt.Errorf("values of type %v are not supported", t)
+	}
+	return nil, false, fmt.Errorf("value %v does not match type %v", value, t)
+}
+
+// setOwner makes fields generated until returned function is called belong to type of assignment and inline
+// types be named after it, assignment of other module is owner of no type of this module
+func (ctx *moduleContext) setOwner(assignment *TypeAssignment) func() {
+	previous := ctx.owner
+	ctx.owner = ""
+	if assignment.Module == "" {
+		ctx.owner = assignment.TypeReference.Name()
+	}
+	leave := ctx.enterScope(assignment.Module, goifyName(assignment.TypeReference.Name()))
+	return func() {
+		ctx.owner = previous
+		leave()
+	}
+}
+
+// enterScope makes inline types generated until returned function is called be named after Go type name of
+// module, empty module is the one being generated
+func (ctx *moduleContext) enterScope(module, name string) func() {
+	previous := ctx.scope
+	ctx.scope = inlineScope{module: module, name: name}
+	return func() {
+		ctx.scope = previous
+	}
+}
+
+// generateFieldValue generates element of struct literal for value of component or alternative, Go type of the
+// field is the same as generateStructField gives
+func (ctx *moduleContext) generateFieldValue(name Identifier, t Type, value Value) (goast.Expr, error) {
+	goType := ctx.generateInlineType(name.Name(), t, false)
+	constPrefix := ""
+	if ctx.hasNamedValues(t) {
+		// named values of type defined inline are its constants
+		constPrefix = ctx.qualifiedInlineName(name.Name())
+	}
+	if inlineType(t) != nil {
+		defer ctx.enterScope(ctx.scope.module, ctx.inlineName(name.Name()))()
+	}
+	// references are generated as identifiers with star
+	ident, isPointer := goType.(*goast.Ident)
+	isPointer = isPointer && strings.HasPrefix(ident.Name, "*")
+	if isPointer {
+		goType = goast.NewIdent(strings.TrimPrefix(ident.Name, "*"))
+	}
+	expr, _, err := ctx.generateValueExpr(t, goType, constPrefix, ctx.lookupValue(value))
+	if err != nil {
+		return nil, err
+	}
+	if isPointer {
+		if _, ok := expr.(*goast.CompositeLit); ok {
+			expr = &goast.UnaryExpr{Op: gotoken.AND, X: expr}
+		} else {
+			// address of constant can't be taken, so value is copied to variable
+			v := goast.NewIdent("v")
+			expr = call(&goast.FuncLit{
+				Type: &goast.FuncType{Params: &goast.FieldList{}, Results: fieldList(nil, ident)},
+				Body: &goast.BlockStmt{List: []goast.Stmt{
+					&goast.DeclStmt{Decl: &goast.GenDecl{Tok: gotoken.VAR, Specs: []goast.Spec{
+						&goast.ValueSpec{Names: []*goast.Ident{v}, Type: goType, Values: []goast.Expr{expr}},
+					}}},
+					returnStmt(&goast.UnaryExpr{Op: gotoken.AND, X: v}),
+				}},
+			})
+		}
+	}
+	return &goast.KeyValueExpr{Key: goast.NewIdent(goifyName(name.Name())), Value: expr}, nil
+}
+
+func bytesLit(sliceType goast.Expr, bytes []byte) *goast.CompositeLit {
+	elts := make([]goast.Expr, 0, len(bytes))
+	for _, b := range bytes {
+		elts = append(elts, &goast.BasicLit{Kind: gotoken.INT, Value: fmt.Sprintf("0x%02x", b)})
+	}
+	return &goast.CompositeLit{Type: sliceType, Elts: elts}
+}
+
+// generateObjectIdentifierDecl generates variable holding OBJECT IDENTIFIER value with all references resolved, goType
+// is Go type of variable, asn1.ObjectIdentifier if it is nil
+func (ctx *moduleContext) generateObjectIdentifierDecl(reference ValueReference, goType goast.Expr, value Value) *goast.GenDecl {
+	if goType == nil {
+		ctx.requireModule("encoding/asn1")
+		goType = selector("asn1", "ObjectIdentifier")
+	}
+	arcs, err := ctx.compiler.registry.ObjectIdentifierArcs(ctx.module, value)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("value %v: %v", reference.Name(), err))
+	}
+	elts := make([]goast.Expr, 0, len(arcs))
+	for _, arc := range arcs {
+		elts = append(elts, intLit(arc))
+	}
 	return &goast.GenDecl{
-		Tok: gotoken.CONST,
+		Tok: gotoken.VAR,
 		Specs: []goast.Spec{
 			&goast.ValueSpec{
-				Names:  names,
-				Type:   ctx.generateTypeBody(typeDescr, true),
-				Doc:    ctx.commentFromType(typeDescr, reference.Name(), nil),
-				Values: values,
+				Names:  []*goast.Ident{goast.NewIdent(ctx.valueName(reference))},
+				Values: []goast.Expr{&goast.CompositeLit{Type: goType, Elts: elts}},
 			},
 		},
 	}
 }
-func (ctx *moduleContext) generateTypeDecl(reference TypeReference, typeDescr Type) goast.Decl {
+
+func (ctx *moduleContext) generateTypeDecl(reference TypeReference, typeDescr Type) *goast.GenDecl {
 	name := goast.NewIdent(goifyName(reference.Name()))
 	// var pos token.Pos
 	var type1 goast.Expr
 	comment_group := ctx.commentFromType(typeDescr, reference.Name(), nil)
+	if ctx.hasNamedValues(typeDescr) {
+		// named values are generated as constants
+		comment_group = nil
+	}
 	// if comment_group != nil {
 	// 	pos = comment_group.End()
 	// 	// ctx.appendComment(comment_group)
 	// }
+	defer ctx.setOwner(&TypeAssignment{TypeReference: reference})()
 	type1 = ctx.generateTypeBody(typeDescr, true)
 	decl := goast.GenDecl{
 		Tok: gotoken.TYPE,
@@ -249,28 +816,396 @@ func (ctx *moduleContext) generateTypeDecl(reference TypeReference, typeDescr Ty
 	// }
 	return &decl
 }
-func (ctx *moduleContext) generateValueBody(value Value) ([]*goast.Ident, []goast.Expr) {
-	names := make([]*goast.Ident, 0)
-	exprs := make([]goast.Expr, 0)
-	switch tt := value.(type) {
-	case ObjectIdentifierValue:
-		{
-			for _, v := range tt {
-				switch vl := v.(type) {
-				case ObjectIdElement:
-					{
-						names = append(names, goast.NewIdent(goifyName(vl.Name)))
-						exprs = append(exprs, goast.NewIdent(fmt.Sprint(vl.Id)))
-					}
-				}
+// namedValue is identifier of INTEGER or ENUMERATED value with its number
+type namedValue struct {
+	name   Identifier
+	number int
+}
+
+// hasNamedValues returns true if t, possibly tagged or constrained, is INTEGER with named numbers or ENUMERATED
+func (ctx *moduleContext) hasNamedValues(t Type) bool {
+	switch tt := withoutTags(t).(type) {
+	case IntegerType:
+		return len(tt.NamedNumberList) > 0
+	case EnumeratedType:
+		return true
+	}
+	return false
+}
+
+// namedNumber resolves number of named value, reporting error if it's not a Number
+func (ctx *moduleContext) namedNumber(reference TypeReference, name Identifier, value Value) (int, bool) {
+	number, ok := ctx.lookupValue(value).(Number)
+	if !ok {
+		ctx.appendError(fmt.Errorf("Number of %v in %v should be Number, got %v", name, reference.Name(), value))
+		return 0, false
+	}
+	return number.IntValue(), true
+}
+
+func (ctx *moduleContext) integerValues(reference TypeReference, t IntegerType) []namedValue {
+	res := make([]namedValue, 0, len(t.NamedNumberList))
+	for _, named := range t.NamedNumberList {
+		if number, ok := ctx.namedNumber(reference, named.Name, named.Value); ok {
+			res = append(res, namedValue{name: named.Name, number: number})
+		}
+	}
+	return res
+}
+
+// enumeratedValues numbers items of ENUMERATED as X.680 19.3 and 19.4 define: root items without number get the
+// smallest numbers not used by root items, additional items get the smallest number not used by root items and
+// greater than number of preceding additional item
+func (ctx *moduleContext) enumeratedValues(reference TypeReference, t EnumeratedType) []namedValue {
+	used := map[int]bool{}
+	res := make([]namedValue, 0, len(t.Enums)+len(t.AdditionalEnums))
+	for _, item := range t.Enums {
+		value := namedValue{name: item.Name, number: -1}
+		if item.Index != nil {
+			if number, ok := ctx.namedNumber(reference, item.Name, item.Index); ok {
+				value.number = number
+				used[number] = true
+			}
+		}
+		res = append(res, value)
+	}
+	next := 0
+	for i, item := range t.Enums {
+		if item.Index == nil {
+			for used[next] {
+				next++
+			}
+			res[i].number = next
+			used[next] = true
+		}
+	}
+	next = 0
+	for _, item := range t.AdditionalEnums {
+		value := namedValue{name: item.Name, number: next}
+		if item.Index != nil {
+			if number, ok := ctx.namedNumber(reference, item.Name, item.Index); ok {
+				value.number = number
 			}
+		} else {
+			for used[value.number] {
+				value.number++
+			}
+		}
+		used[value.number] = true
+		next = value.number + 1
+		res = append(res, value)
+	}
+	return res
+}
+
+// namedValueNumber returns number of named value name of INTEGER or ENUMERATED t, references are followed
+func (ctx *moduleContext) namedValueNumber(t Type, name string) (int, bool) {
+	var values []namedValue
+	switch tt := withoutTags(t).(type) {
+	case TypeReference:
+		if assignment := ctx.resolveTypeReference(tt); assignment != nil && assignment.Type != nil {
+			return ctx.namedValueNumber(assignment.Type, name)
+		}
+	case ExternalTypeReference:
+		if assignment := ctx.resolveExternalTypeReference(tt); assignment != nil {
+			return ctx.namedValueNumber(assignment.Type, name)
+		}
+	case IntegerType:
+		values = ctx.integerValues(TypeReference(name), tt)
+	case EnumeratedType:
+		values = ctx.enumeratedValues(TypeReference(name), tt)
+	}
+	for _, value := range values {
+		if value.name.Name() == name {
+			return value.number, true
+		}
+	}
+	return 0, false
+}
+
+// defaultNumber returns number of DEFAULT value which is either a named number of type t or a reference to value.
+// Other defaults can't be given in encoding/asn1 tag and are left out.
+func (ctx *moduleContext) defaultNumber(t Type, value Value) (int, bool) {
+	if identified, ok := value.(IdentifiedIntegerValue); ok {
+		if number, ok := ctx.namedValueNumber(t, identified.Name); ok {
+			return number, true
 		}
 	}
-	if len(exprs) > 0 {
-		return names, exprs
+	resolved, err := ctx.compiler.registry.ResolveValue(ctx.module, value)
+	if err != nil {
+		return 0, false
+	}
+	if number, ok := resolved.(Number); ok {
+		return number.IntValue(), true
+	}
+	return 0, false
+}
+
+// generateNamedValues generates constants for named numbers of INTEGER or items of ENUMERATED and String method
+// returning their identifiers, numbers without identifier are formatted as decimals. ENUMERATED also gets function
+// parsing identifiers and text marshalling, which is used for JSON and XML too. Values which are not listed are
+// rejected by them unless ENUMERATED is extensible, otherwise it gets Valid method telling if value is listed.
+// typeName is Go name of the type defined as reference.
+func (ctx *moduleContext) generateNamedValues(typeName string, reference TypeReference, t Type) []goast.Decl {
+	var values []namedValue
+	enumerated, isEnumerated := withoutTags(t).(EnumeratedType)
+	switch tt := withoutTags(t).(type) {
+	case IntegerType:
+		values = ctx.integerValues(reference, tt)
+	case EnumeratedType:
+		values = ctx.enumeratedValues(reference, tt)
+	}
+	if len(values) == 0 && !isEnumerated {
+		return nil
+	}
+	specs := make([]goast.Spec, 0)
+	constNames := make([]goast.Expr, 0)
+	stringCases := make([]goast.Stmt, 0)
+	parseCases := make([]goast.Stmt, 0)
+	for _, value := range values {
+		constName := goast.NewIdent(typeName + goifyName(value.name.Name()))
+		specs = append(specs, &goast.ValueSpec{
+			Names:  []*goast.Ident{constName},
+			Type:   goast.NewIdent(typeName),
+			Values: []goast.Expr{intLit(value.number)},
+		})
+		constNames = append(constNames, constName)
+		stringCases = append(stringCases, caseClause([]goast.Expr{constName}, returnStmt(stringLit(value.name.Name()))))
+		parseCases = append(parseCases, caseClause([]goast.Expr{stringLit(value.name.Name())}, returnStmt(constName, goast.NewIdent("nil"))))
+	}
+	ctx.requireModule("strconv")
+	receiver := goast.NewIdent("v")
+	decls := []goast.Decl{
+		&goast.GenDecl{Tok: gotoken.CONST, Lparen: 1, Specs: specs},
+		&goast.FuncDecl{
+			Recv: fieldList(receiver, goast.NewIdent(typeName)),
+			Name: goast.NewIdent("String"),
+			Type: &goast.FuncType{Params: &goast.FieldList{}, Results: fieldList(nil, goast.NewIdent("string"))},
+			Body: &goast.BlockStmt{List: []goast.Stmt{
+				&goast.SwitchStmt{Tag: receiver, Body: &goast.BlockStmt{List: stringCases}},
+				returnStmt(call(selector("strconv", "FormatInt"), call(goast.NewIdent("int64"), receiver), intLit(10))),
+			}},
+		},
+	}
+	if !isEnumerated {
+		return decls
+	}
+
+	ctx.requireModule("fmt")
+	parseName := goast.NewIdent("Parse" + typeName)
+	text, parsed, err := goast.NewIdent("text"), goast.NewIdent("parsed"), goast.NewIdent("err")
+	unknown := func(format string, arg goast.Expr, zero goast.Expr) goast.Stmt {
+		return returnStmt(zero, call(selector("fmt", "Errorf"), stringLit(format), arg))
+	}
+	// Parse function accepts identifiers, and decimal numbers of values added in later versions if extensible
+	parseBody := []goast.Stmt{&goast.SwitchStmt{Tag: goast.NewIdent("s"), Body: &goast.BlockStmt{List: parseCases}}}
+	if enumerated.Extensible {
+		number := goast.NewIdent("n")
+		parseBody = append(parseBody, &goast.IfStmt{
+			Init: &goast.AssignStmt{
+				Lhs: []goast.Expr{number, err},
+				Tok: gotoken.DEFINE,
+				Rhs: []goast.Expr{call(selector("strconv", "Atoi"), goast.NewIdent("s"))},
+			},
+			Cond: &goast.BinaryExpr{X: err, Op: gotoken.EQL, Y: goast.NewIdent("nil")},
+			Body: &goast.BlockStmt{List: []goast.Stmt{returnStmt(call(goast.NewIdent(typeName), number), goast.NewIdent("nil"))}},
+		})
+	}
+	parseBody = append(parseBody, unknown("unknown "+typeName+" %q", goast.NewIdent("s"), intLit(0)))
+	marshalBody := []goast.Stmt{}
+	if !enumerated.Extensible && len(constNames) > 0 {
+		marshalBody = append(marshalBody, &goast.SwitchStmt{Tag: receiver, Body: &goast.BlockStmt{List: []goast.Stmt{
+			caseClause(constNames, returnStmt(call(&goast.ArrayType{Elt: goast.NewIdent("byte")}, call(&goast.SelectorExpr{X: receiver, Sel: goast.NewIdent("String")})), goast.NewIdent("nil"))),
+		}}})
 	}
-	return nil, nil
+	if enumerated.Extensible {
+		marshalBody = append(marshalBody, returnStmt(call(&goast.ArrayType{Elt: goast.NewIdent("byte")}, call(&goast.SelectorExpr{X: receiver, Sel: goast.NewIdent("String")})), goast.NewIdent("nil")))
+	} else {
+		marshalBody = append(marshalBody, unknown("unknown "+typeName+" %d", receiver, goast.NewIdent("nil")))
+	}
+	if !enumerated.Extensible {
+		// encoding/asn1 decodes any number as ENUMERATED, see generateCheck
+		validBody := []goast.Stmt{returnStmt(goast.NewIdent("false"))}
+		if len(constNames) > 0 {
+			validBody = append([]goast.Stmt{&goast.SwitchStmt{Tag: receiver, Body: &goast.BlockStmt{List: []goast.Stmt{
+				caseClause(constNames, returnStmt(goast.NewIdent("true"))),
+			}}}}, validBody...)
+		}
+		decls = append(decls, &goast.FuncDecl{
+			Recv: fieldList(receiver, goast.NewIdent(typeName)),
+			Name: goast.NewIdent("Valid"),
+			Type: &goast.FuncType{Params: &goast.FieldList{}, Results: fieldList(nil, goast.NewIdent("bool"))},
+			Body: &goast.BlockStmt{List: validBody},
+		})
+	}
+	decls = append(decls,
+		&goast.FuncDecl{
+			Name: parseName,
+			Type: &goast.FuncType{
+				Params:  fieldList(goast.NewIdent("s"), goast.NewIdent("string")),
+				Results: &goast.FieldList{List: []*goast.Field{{Type: goast.NewIdent(typeName)}, {Type: goast.NewIdent("error")}}},
+			},
+			Body: &goast.BlockStmt{List: parseBody},
+		},
+		&goast.FuncDecl{
+			Recv: fieldList(receiver, goast.NewIdent(typeName)),
+			Name: goast.NewIdent("MarshalText"),
+			Type: &goast.FuncType{
+				Params:  &goast.FieldList{},
+				Results: &goast.FieldList{List: []*goast.Field{{Type: &goast.ArrayType{Elt: goast.NewIdent("byte")}}, {Type: goast.NewIdent("error")}}},
+			},
+			Body: &goast.BlockStmt{List: marshalBody},
+		},
+		&goast.FuncDecl{
+			Recv: fieldList(receiver, &goast.StarExpr{X: goast.NewIdent(typeName)}),
+			Name: goast.NewIdent("UnmarshalText"),
+			Type: &goast.FuncType{
+				Params:  fieldList(text, &goast.ArrayType{Elt: goast.NewIdent("byte")}),
+				Results: fieldList(nil, goast.NewIdent("error")),
+			},
+			Body: &goast.BlockStmt{List: []goast.Stmt{
+				&goast.AssignStmt{
+					Lhs: []goast.Expr{parsed, err},
+					Tok: gotoken.DEFINE,
+					Rhs: []goast.Expr{call(parseName, call(goast.NewIdent("string"), text))},
+				},
+				&goast.IfStmt{
+					Cond: &goast.BinaryExpr{X: err, Op: gotoken.NEQ, Y: goast.NewIdent("nil")},
+					Body: &goast.BlockStmt{List: []goast.Stmt{returnStmt(err)}},
+				},
+				&goast.AssignStmt{Lhs: []goast.Expr{&goast.StarExpr{X: receiver}}, Tok: gotoken.ASSIGN, Rhs: []goast.Expr{parsed}},
+				returnStmt(goast.NewIdent("nil")),
+			}},
+		},
+	)
+	return decls
 }
+
+// generateNamedBits generates constants for positions of named bits of BIT STRING type and methods to test, set
+// and clear bits. Fields of BIT STRING types are generated as asn1.BitString, which encoding/asn1 recognizes, so
+// methods are available after conversion to named type.
+func (ctx *moduleContext) generateNamedBits(reference TypeReference, t Type) []goast.Decl {
+	bitString, ok := withoutTags(t).(BitStringType)
+	if !ok || len(bitString.NamedBits) == 0 {
+		return nil
+	}
+	typeName := goifyName(reference.Name())
+	specs := make([]goast.Spec, 0)
+	for _, named := range bitString.NamedBits {
+		if number, ok := ctx.namedNumber(reference, named.Name, named.Index); ok {
+			specs = append(specs, &goast.ValueSpec{
+				Names:  []*goast.Ident{goast.NewIdent(typeName + goifyName(named.Name.Name()))},
+				Values: []goast.Expr{intLit(number)},
+			})
+		}
+	}
+	ctx.requireModule("encoding/asn1")
+	receiver, bit := goast.NewIdent("b"), goast.NewIdent("bit")
+	bitLength, bytes := &goast.SelectorExpr{X: receiver, Sel: goast.NewIdent("BitLength")}, &goast.SelectorExpr{X: receiver, Sel: goast.NewIdent("Bytes")}
+	// b.Bytes[bit/8] and 0x80 >> uint(bit%8)
+	bitByte := &goast.IndexExpr{X: bytes, Index: &goast.BinaryExpr{X: bit, Op: gotoken.QUO, Y: intLit(8)}}
+	bitMask := &goast.BinaryExpr{
+		X:  &goast.BasicLit{Kind: gotoken.INT, Value: "0x80"},
+		Op: gotoken.SHR,
+		Y:  call(goast.NewIdent("uint"), &goast.BinaryExpr{X: bit, Op: gotoken.REM, Y: intLit(8)}),
+	}
+	// (b.BitLength + 7) / 8
+	byteLength := &goast.BinaryExpr{X: &goast.ParenExpr{X: &goast.BinaryExpr{X: bitLength, Op: gotoken.ADD, Y: intLit(7)}}, Op: gotoken.QUO, Y: intLit(8)}
+	bitParam := fieldList(bit, goast.NewIdent("int"))
+	pointerReceiver := fieldList(receiver, &goast.StarExpr{X: goast.NewIdent(typeName)})
+	return []goast.Decl{
+		&goast.GenDecl{Tok: gotoken.CONST, Lparen: 1, Specs: specs},
+		&goast.FuncDecl{
+			Recv: fieldList(receiver, goast.NewIdent(typeName)),
+			Name: goast.NewIdent("Has"),
+			Type: &goast.FuncType{Params: bitParam, Results: fieldList(nil, goast.NewIdent("bool"))},
+			Body: &goast.BlockStmt{List: []goast.Stmt{returnStmt(&goast.BinaryExpr{
+				X:  call(&goast.SelectorExpr{X: call(selector("asn1", "BitString"), receiver), Sel: goast.NewIdent("At")}, bit),
+				Op: gotoken.EQL,
+				Y:  intLit(1),
+			})}},
+		},
+		// Set extends bit string if bit is beyond its length
+		&goast.FuncDecl{
+			Recv: pointerReceiver,
+			Name: goast.NewIdent("Set"),
+			Type: &goast.FuncType{Params: bitParam},
+			Body: &goast.BlockStmt{List: []goast.Stmt{
+				&goast.IfStmt{
+					Cond: &goast.BinaryExpr{X: bit, Op: gotoken.GEQ, Y: bitLength},
+					Body: &goast.BlockStmt{List: []goast.Stmt{
+						&goast.AssignStmt{Lhs: []goast.Expr{bitLength}, Tok: gotoken.ASSIGN, Rhs: []goast.Expr{&goast.BinaryExpr{X: bit, Op: gotoken.ADD, Y: intLit(1)}}},
+						&goast.ForStmt{
+							Cond: &goast.BinaryExpr{X: call(goast.NewIdent("len"), bytes), Op: gotoken.LSS, Y: byteLength},
+							Body: &goast.BlockStmt{List: []goast.Stmt{
+								&goast.AssignStmt{Lhs: []goast.Expr{bytes}, Tok: gotoken.ASSIGN, Rhs: []goast.Expr{call(goast.NewIdent("append"), bytes, intLit(0))}},
+							}},
+						},
+					}},
+				},
+				&goast.AssignStmt{Lhs: []goast.Expr{bitByte}, Tok: gotoken.OR_ASSIGN, Rhs: []goast.Expr{bitMask}},
+			}},
+		},
+		// Clear removes trailing zero bits, as DER requires for BIT STRING with named bits (X.690 11.2.2)
+		&goast.FuncDecl{
+			Recv: pointerReceiver,
+			Name: goast.NewIdent("Clear"),
+			Type: &goast.FuncType{Params: bitParam},
+			Body: &goast.BlockStmt{List: []goast.Stmt{
+				&goast.IfStmt{
+					Cond: &goast.BinaryExpr{X: bit, Op: gotoken.LSS, Y: bitLength},
+					Body: &goast.BlockStmt{List: []goast.Stmt{
+						&goast.AssignStmt{Lhs: []goast.Expr{bitByte}, Tok: gotoken.AND_NOT_ASSIGN, Rhs: []goast.Expr{bitMask}},
+					}},
+				},
+				&goast.ForStmt{
+					Cond: &goast.BinaryExpr{
+						X:  &goast.BinaryExpr{X: bitLength, Op: gotoken.GTR, Y: intLit(0)},
+						Op: gotoken.LAND,
+						Y: &goast.UnaryExpr{Op: gotoken.NOT, X: call(&goast.SelectorExpr{X: receiver, Sel: goast.NewIdent("Has")},
+							&goast.BinaryExpr{X: bitLength, Op: gotoken.SUB, Y: intLit(1)})},
+					},
+					Body: &goast.BlockStmt{List: []goast.Stmt{&goast.IncDecStmt{X: bitLength, Tok: gotoken.DEC}}},
+				},
+				&goast.AssignStmt{Lhs: []goast.Expr{bytes}, Tok: gotoken.ASSIGN, Rhs: []goast.Expr{&goast.SliceExpr{X: bytes, High: byteLength}}},
+			}},
+		},
+	}
+}
+
+func intLit(value int) *goast.BasicLit {
+	return &goast.BasicLit{Kind: gotoken.INT, Value: strconv.Itoa(value)}
+}
+
+func stringLit(value string) *goast.BasicLit {
+	return &goast.BasicLit{Kind: gotoken.STRING, Value: strconv.Quote(value)}
+}
+
+func call(fun goast.Expr, args ...goast.Expr) *goast.CallExpr {
+	return &goast.CallExpr{Fun: fun, Args: args}
+}
+
+func selector(pkg, name string) *goast.SelectorExpr {
+	return &goast.SelectorExpr{X: goast.NewIdent(pkg), Sel: goast.NewIdent(name)}
+}
+
+func returnStmt(results ...goast.Expr) *goast.ReturnStmt {
+	return &goast.ReturnStmt{Results: results}
+}
+
+func caseClause(list []goast.Expr, body ...goast.Stmt) *goast.CaseClause {
+	return &goast.CaseClause{List: list, Body: body}
+}
+
+// fieldList returns list of single field, name may be nil
+func fieldList(name *goast.Ident, fieldType goast.Expr) *goast.FieldList {
+	field := &goast.Field{Type: fieldType}
+	if name != nil {
+		field.Names = []*goast.Ident{name}
+	}
+	return &goast.FieldList{List: []*goast.Field{field}}
+}
+
 func (ctx *moduleContext) generateTypeBody(typeDescr Type, noStar Boolean) goast.Expr {
 	switch t := typeDescr.(type) {
 	case BooleanType:
@@ -284,103 +1219,63 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, noStar Boolean) goast
 	case OctetStringType:
 		return &goast.ArrayType{Elt: goast.NewIdent("byte")}
 	case ChoiceType:
+		// value of extensible CHOICE may hold alternative unknown to this version, so none of the
+		// alternatives is required when decoding and unknown one is kept as raw value
 		fields := &goast.FieldList{}
-		for _, f := range t.AlternativeTypeList {
-			fields.List = append(fields.List, ctx.generateStructField(NamedComponentType{NamedType: f}, &typeDescr))
+		names := map[string]bool{}
+		for _, f := range t.Alternatives() {
+			fields.List = append(fields.List, ctx.generateStructField(NamedComponentType{NamedType: f, IsOptional: t.Extensible}, &typeDescr))
+			names[goifyName(f.Identifier.Name())] = true
+		}
+		if t.Extensible {
+			name := "Unknown"
+			for names[name] {
+				name += "_"
+			}
+			ctx.requireModule("encoding/asn1")
+			fields.List = append(fields.List, &goast.Field{
+				Names: []*goast.Ident{goast.NewIdent(name)},
+				Type:  goast.NewIdent("asn1.RawValue"),
+				Tag:   &goast.BasicLit{Kind: gotoken.STRING, Value: "`xml:\"-\" json:\"-\" asn1:\"optional\"`"},
+			})
 		}
 		return &goast.StructType{
 			Fields: fields,
 		}
 	case SequenceType:
-		fields := &goast.FieldList{}
-		for _, field := range t.Components {
-			switch f := field.(type) {
-			case NamedComponentType:
-				fields.List = append(fields.List, ctx.generateStructField(f, &typeDescr))
-			case ComponentsOfComponentType: // TODO
-			}
-		}
 		return &goast.StructType{
-			Fields: fields,
+			Fields: ctx.generateComponentFields(ctx.expandComponentsOf(t).(SequenceType), &typeDescr),
 		}
 	case SetType:
-		fields := &goast.FieldList{}
-		for _, field := range t.Components {
-			switch f := field.(type) {
-			case NamedComponentType:
-				fields.List = append(fields.List, ctx.generateStructField(f, &typeDescr))
-			case ComponentsOfComponentType: // TODO
-			}
-		}
 		return &goast.StructType{
 			// Struct: pos + 1,
-			Fields: fields,
+			Fields: ctx.generateComponentFields(SequenceType(ctx.expandComponentsOf(t).(SetType)), &typeDescr),
 		}
 	case SetOfType:
-		return &goast.ArrayType{Elt: ctx.generateTypeBody(t.Type, true)}
+		return &goast.ArrayType{Elt: ctx.generateInlineType(inlineItem, t.Type, true)}
 	case SequenceOfType:
-		return &goast.ArrayType{Elt: ctx.generateTypeBody(t.Type, true)}
+		return &goast.ArrayType{Elt: ctx.generateInlineType(inlineItem, t.Type, true)}
 	case TaggedType: // TODO should put tags in go code?
 		return ctx.generateTypeBody(t.Type, noStar)
 	case ConstraintedType: // TODO should generate checking code?
 		return ctx.generateTypeBody(t.Type, noStar)
 	case TypeReference: // TODO should useful types be separate type by itself?
-
-		prefix := "*"
-		if noStar {
-			prefix = ""
+		return ctx.generateReference(t, ctx.resolveTypeReference(t), noStar)
+	case ExternalTypeReference:
+		nameAndType := ctx.resolveExternalTypeReference(t)
+		if nameAndType != nil && nameAndType.Module != "" {
+			ctx.requireGeneratedModule(nameAndType.Module)
 		}
-		// if t.Name() == "KDC-REP" {
-		// 	prefix = ""
-		// }
-		nameAndType := ctx.resolveTypeReference(t)
-
-		if nameAndType != nil {
-			switch nameAndType.Type.(type) {
-			case RestrictedStringType, ConstraintedType, SequenceOfType, SetOfType:
-				{
-					prefix = ""
-				}
-			default:
-				if tt := ctx.lookupUsefulType(nameAndType.TypeReference); tt != nil {
-					switch tt.(type) {
-					case RestrictedStringType:
-						{
-							prefix = ""
-						}
-					}
-				}
-			}
-		}
-		if prefix == "*" && IsPrimvateType(nameAndType.TypeReference.Name()) {
-			prefix = ""
-		}
-		if nameAndType != nil {
-			specialCase := ctx.generateSpecialCase(*nameAndType, prefix)
-			if specialCase != nil {
-				return specialCase
-			}
-			if nameAndType.Module != "" {
-				return goast.NewIdent(prefix + goifyName(nameAndType.Module) + "." + goifyName(t.Name()))
-			}
-		}
-
-		if IsPrimvateType(t.Name()) {
-			prefix = ""
-		}
-		// if prefix == "*" {
-		// 	fmt.Println("has star")
-		// }
-		return goast.NewIdent(prefix + goifyName(t.Name()))
+		return ctx.generateReference(t.TypeReference, nameAndType, noStar)
 	case RestrictedStringType: // TODO should generate checking code?
 		return goast.NewIdent("string")
 	case BitStringType:
 		ctx.requireModule("encoding/asn1")
 		return goast.NewIdent("asn1.BitString")
 	case EnumeratedType:
-		return goast.NewIdent("string") //在json文件中是int,虽然是int类型，但是在xml文件里是文本
-	case IntegerEnumType:
-		return goast.NewIdent("int") //在json文件中是int,虽然是int类型，但是在xml文件里是文本
+		// encoding/asn1 tags only asn1.Enumerated as ENUMERATED, named types get methods of generateNamedValues
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.Enumerated")
 	case StringType:
 		return goast.NewIdent("string")
 	case BigInt:
@@ -388,10 +1283,9 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, noStar Boolean) goast
 	case NullType:
 		return goast.NewIdent("interface{}")
 	case ObjectIdentifierType:
-		return goast.NewIdent("int")
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.ObjectIdentifier")
 	default:
-		// NullType
-		// ObjectIdentifierType
 		// ChoiceType
 		// RestrictedStringType
 		ctx.appendError(errors.New(fmt.Sprintf("Ignoring unsupported type %#v", typeDescr)))
@@ -399,6 +1293,82 @@ func (ctx *moduleContext) generateTypeBody(typeDescr Type, noStar Boolean) goast
 	}
 }
 
+// generateInlineType generates Go type for type t of component, alternative or item identifier of type in scope.
+// SEQUENCE, SET, CHOICE, INTEGER with named numbers and ENUMERATED defined inline are generated as named types
+// declared once, see inlineTypeNames. Fields of ENUMERATED are asn1.Enumerated as for referenced types.
+func (ctx *moduleContext) generateInlineType(identifier string, t Type, noStar Boolean) goast.Expr {
+	switch tt := t.(type) {
+	case TaggedType:
+		return ctx.generateInlineType(identifier, tt.Type, noStar)
+	case ConstraintedType:
+		return ctx.generateInlineType(identifier, tt.Type, noStar)
+	case SequenceOfType:
+		return &goast.ArrayType{Elt: ctx.generateInlineType(identifier, tt.Type, true)}
+	case SetOfType:
+		return &goast.ArrayType{Elt: ctx.generateInlineType(identifier, tt.Type, true)}
+	case SequenceType, SetType, ChoiceType, IntegerType, EnumeratedType:
+		if inlineType(tt) == nil {
+			// INTEGER without named numbers
+			break
+		}
+		name := ctx.inlineName(identifier)
+		if ctx.scope.module != "" {
+			if _, ok := tt.(EnumeratedType); ok {
+				return ctx.generateTypeBody(tt, noStar)
+			}
+			return goast.NewIdent(ctx.qualifiedInlineName(identifier))
+		}
+		if !ctx.declaredInline[name] {
+			ctx.declaredInline[name] = true
+			// declaration precedes declarations of types defined inline in it
+			spec := &goast.TypeSpec{Name: goast.NewIdent(name)}
+			ctx.inlineDecls = append(ctx.inlineDecls, &goast.GenDecl{Tok: gotoken.TYPE, Specs: []goast.Spec{spec}})
+			leave := ctx.enterScope("", name)
+			spec.Type = ctx.generateTypeBody(tt, true)
+			check := ctx.generateCheck(name, tt)
+			leave()
+			ctx.inlineDecls = append(ctx.inlineDecls, ctx.generateNamedValues(name, TypeReference(name), tt)...)
+			ctx.inlineDecls = append(ctx.inlineDecls, check...)
+		}
+		if _, ok := tt.(EnumeratedType); ok {
+			// named type only holds constants, see generateSpecialCase
+			return ctx.generateTypeBody(tt, noStar)
+		}
+		return goast.NewIdent(name)
+	}
+	return ctx.generateTypeBody(t, noStar)
+}
+
+// qualifiedInlineName returns Go name of type defined inline at identifier of type in scope, qualified with package
+// if the type is defined in other module
+func (ctx *moduleContext) qualifiedInlineName(identifier string) string {
+	if ctx.scope.module == "" {
+		return ctx.inlineName(identifier)
+	}
+	ctx.requireGeneratedModule(ctx.scope.module)
+	return goifyName(ctx.scope.module) + "." + ctx.inlineName(identifier)
+}
+
+// inlineName returns Go name of type defined inline at identifier of type in scope
+func (ctx *moduleContext) inlineName(identifier string) string {
+	names, ok := ctx.inlineNames[ctx.scope.module]
+	if !ok {
+		module := ctx.module
+		if ctx.scope.module != "" {
+			module = ctx.compiler.registry.Module(ctx.scope.module)
+		}
+		if module != nil {
+			names = inlineTypeNames(ctx.compiler.registry, module)
+		}
+		ctx.inlineNames[ctx.scope.module] = names
+	}
+	if name, ok := names[ctx.scope.name+"."+identifier]; ok {
+		return name
+	}
+	// scope isn't known for types of values of other types
+	return ctx.scope.name + "_" + goifyName(identifier)
+}
+
 func IsPrimvateType(typeName string) Boolean {
 
 	switch typeName {
@@ -439,16 +1409,58 @@ func IsPrimvateType(typeName string) Boolean {
 
 	return false
 }
+// expandComponentsOf includes components referenced by COMPONENTS OF in SEQUENCE or SET t, errors are reported
+// and t is returned as is
+func (ctx *moduleContext) expandComponentsOf(t Type) Type {
+	expanded, err := ctx.compiler.registry.ExpandComponentsOf(ctx.module, t)
+	if err != nil {
+		ctx.appendError(err)
+		return t
+	}
+	return expanded
+}
+
+// generateComponentFields generates struct fields for components of SEQUENCE or SET in order of definition.
+// Extension additions may be missing in encoding of earlier version, so their fields are optional.
+func (ctx *moduleContext) generateComponentFields(t SequenceType, parent *Type) *goast.FieldList {
+	fields := &goast.FieldList{}
+	addComponents := func(components ComponentTypeList, optional bool) {
+		for _, field := range components {
+			switch f := field.(type) {
+			case NamedComponentType:
+				f.IsOptional = f.IsOptional || (optional && f.Default == nil)
+				fields.List = append(fields.List, ctx.generateStructField(f, parent))
+			}
+		}
+	}
+	addComponents(t.Components, false)
+	for _, addition := range t.ExtensionAdditions {
+		switch a := addition.(type) {
+		case ExtensionAdditionGroup:
+			addComponents(a.Components, true)
+		case ComponentType:
+			addComponents(ComponentTypeList{a}, true)
+		}
+	}
+	addComponents(t.TrailingRootComponents, false)
+	return fields
+}
+
 func (ctx *moduleContext) generateStructField(f NamedComponentType, parent *Type) *goast.Field {
 	return &goast.Field{
 		Names:   append(make([]*goast.Ident, 0), goast.NewIdent(goifyName(f.NamedType.Identifier.Name()))),
-		Type:    ctx.generateTypeBody(f.NamedType.Type, false),
+		Type:    ctx.generateInlineType(f.NamedType.Identifier.Name(), f.NamedType.Type, false),
 		Tag:     ctx.asn1TagFromType(f, parent),
+		Doc:     docComment(f.NamedType.Doc, f.NamedType.LineComment),
 		Comment: ctx.commentFromComponentType(f, parent),
 	}
 }
 func (ctx *moduleContext) commentFromComponentType(nt NamedComponentType, parent *Type) *goast.CommentGroup {
 	t := nt.NamedType.Type
+	if inlineType(t) != nil && ctx.hasNamedValues(t) {
+		// named values are constants of type defined inline
+		return nil
+	}
 	return ctx.commentFromType(t, nt.NamedType.Identifier.Name(), parent)
 }
 
@@ -467,11 +1479,11 @@ func (ctx *moduleContext) commentFromType(t1 Type, typeName string, parent *Type
 		{
 			return &goast.CommentGroup{List: append(make([]*goast.Comment, 0), &goast.Comment{Slash: 0, Text: fmt.Sprintf("//%s,ChoiceOption\n", goifyName(typeName))})}
 		}
-	case IntegerEnumType:
-		{
+	case IntegerType:
+		if len(tt.NamedNumberList) > 0 {
 			comments := make([]string, 0)
-			for _, enum := range tt.Enums {
-				comments = append(comments, fmt.Sprintf("%s(%d)", enum.Name, enum.Index))
+			for _, named := range tt.NamedNumberList {
+				comments = append(comments, fmt.Sprintf("%s(%v)", named.Name, named.Value))
 			}
 			commentline := strings.Join(comments, ",")
 			return &goast.CommentGroup{List: append(make([]*goast.Comment, 0), &goast.Comment{Slash: 0, Text: fmt.Sprintf("//%s,IntegerEnum:%s\n", goifyName(typeName), commentline)})}
@@ -479,8 +1491,8 @@ func (ctx *moduleContext) commentFromType(t1 Type, typeName string, parent *Type
 	case EnumeratedType:
 		{
 			comments := make([]string, 0)
-			for _, enum := range tt.Enums {
-				comments = append(comments, fmt.Sprintf("%s(%d)", enum.Name, enum.Index))
+			for _, enum := range ctx.enumeratedValues(TypeReference(typeName), tt) {
+				comments = append(comments, fmt.Sprintf("%s(%d)", enum.name, enum.number))
 			}
 			commentline := strings.Join(comments, ",")
 			return &goast.CommentGroup{List: append(make([]*goast.Comment, 0), &goast.Comment{Slash: 0, Text: fmt.Sprintf("//%s,EnumList:%s\n", goifyName(typeName), commentline)})}
@@ -499,32 +1511,44 @@ func (ctx *moduleContext) asn1TagFromType(nt NamedComponentType, parent *Type) *
 			components = append(components, fmt.Sprintf("default:%v", defaultNumber.IntValue()))
 		} else if defaultString, ok := (nt.Default).(String); ok {
 			components = append(components, fmt.Sprintf("default:%s", defaultString.StringValue()))
+		} else if number, ok := ctx.defaultNumber(t, nt.Default); ok {
+			components = append(components, fmt.Sprintf("default:%v", number))
 		}
 	}
-	// unwrap type
-unwrap:
-	for {
-		switch tt := t.(type) {
-		case TaggedType:
-			if tt.Tag.Class == CLASS_APPLICATION {
-				components = append(components, "application")
-			}
-			if tt.TagType == TAGS_EXPLICIT {
-				components = append(components, "explicit")
-			}
-			switch cn := ctx.lookupValue(tt.Tag.ClassNumber).(type) {
-			case Number:
-				components = append(components, fmt.Sprintf("tag:%v", cn.IntValue()))
-			default:
-				ctx.appendError(errors.New(fmt.Sprintf("Tag value should be Number, got %#v", cn)))
-			}
-			t = tt.Type
-		case ConstraintedType:
-			t = tt.Type
-		default:
-			break unwrap
+	// tag written at component or, if component is untagged reference, tag of referenced type, which Go type
+	// doesn't carry
+	var tags []EffectiveTag
+	switch withoutConstraints(t).(type) {
+	case TaggedType:
+		var err error
+		if tags, err = ctx.compiler.registry.EffectiveTags(ctx.module, t); err != nil {
+			ctx.appendError(fmt.Errorf("tag of %v: %v", nt.NamedType.Identifier, err))
+		}
+	case TypeReference, ExternalTypeReference:
+		// only tag written at referenced type is on the wire, tags of alternatives of untagged CHOICE belong to
+		// its fields, unresolved references are reported when resolved
+		if _, resolved, _, err := ctx.compiler.registry.resolveUntagged(ctx.module, t, nil); err != nil {
+			break
+		} else if _, ok := resolved.(TaggedType); !ok {
+			break
+		}
+		if referenced, err := ctx.compiler.registry.EffectiveTags(ctx.module, t); err == nil && referenced[0].Class != CLASS_UNIVERSAL {
+			tags = referenced
+		}
+	}
+	if len(tags) > 0 {
+		switch tags[0].Class {
+		case CLASS_APPLICATION:
+			components = append(components, "application")
+		case CLASS_PRIVATE:
+			components = append(components, "private")
+		}
+		if tags[0].TagType == TAGS_EXPLICIT {
+			components = append(components, "explicit")
 		}
+		components = append(components, fmt.Sprintf("tag:%v", tags[0].Number))
 	}
+	t = withoutTags(t)
 	isReference := false
 	isArray := false
 	isNull := false
@@ -546,6 +1570,8 @@ unwrap:
 		case PrintableString:
 			components = append(components, "printable")
 		}
+	case ExternalTypeReference:
+		isReference = true
 	case TypeReference:
 		isReference = true
 		switch ctx.unwrapToLeafType(tt).TypeReference.Name() {
@@ -593,19 +1619,54 @@ unwrap:
 
 func (ctx *moduleContext) generateSpecialCase(resolved TypeAssignment, prefix string) goast.Expr {
 	if resolved.TypeReference.Name() == GeneralizedTimeName || resolved.TypeReference.Name() == UTCTimeName {
-		// time types in encoding/asn1go don't support wrapping of time.Time
+		// time types in encoding/asn1 don't support wrapping of time.Time
 		ctx.requireModule("time")
 		return goast.NewIdent(prefix + "time.Time")
-	} else if _, ok := ctx.removeWrapperTypes(resolved.Type).(BitStringType); ok {
+	} else if _, ok := withoutTags(resolved.Type).(BitStringType); ok {
 		ctx.requireModule("encoding/asn1")
 		return goast.NewIdent("asn1.BitString")
+	} else if _, ok := withoutTags(resolved.Type).(ObjectIdentifierType); ok {
+		// encoding/asn1 encodes only asn1.ObjectIdentifier itself as OBJECT IDENTIFIER
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.ObjectIdentifier")
+	} else if _, ok := withoutTags(resolved.Type).(EnumeratedType); ok {
+		// encoding/asn1 encodes only asn1.Enumerated itself as ENUMERATED, constants of the named type are converted
+		ctx.requireModule("encoding/asn1")
+		return goast.NewIdent("asn1.Enumerated")
 	}
 	return nil
 }
 
-// TODO really lookup values from module and imports
+// lookupValue resolves references to values defined in module or imported from modules known to compiler,
+// unresolved references are reported and returned as is
 func (ctx *moduleContext) lookupValue(val Value) Value {
-	return val
+	resolved, err := ctx.compiler.registry.ResolveValue(ctx.module, val)
+	if err != nil {
+		ctx.appendError(err)
+		return val
+	}
+	return resolved
+}
+
+// generateReference generates Go type for reference t resolved to nameAndType, which is nil if it can't be resolved.
+// Reference is a pointer only if noStar is false and it closes a cycle of types containing each other, which Go
+// doesn't allow for values.
+func (ctx *moduleContext) generateReference(t TypeReference, nameAndType *TypeAssignment, noStar Boolean) goast.Expr {
+	prefix := ""
+	if !bool(noStar) && (nameAndType == nil || nameAndType.Module == "") && ctx.types.breaksCycle(ctx.owner, t.Name()) {
+		prefix = "*"
+	}
+	if nameAndType != nil {
+		specialCase := ctx.generateSpecialCase(*nameAndType, prefix)
+		if specialCase != nil {
+			return specialCase
+		}
+		if nameAndType.Module != "" {
+			return goast.NewIdent(prefix + goifyName(nameAndType.Module) + "." + goifyName(t.Name()))
+		}
+	}
+
+	return goast.NewIdent(prefix + goifyName(t.Name()))
 }
 
 // resolveTypeReference resolves references until reaches unresolved type, useful type, or declared type
@@ -615,51 +1676,62 @@ func (ctx *moduleContext) resolveTypeReference(reference TypeReference) *TypeAss
 	unwrapped := ctx.unwrapToLeafType(reference)
 	if unwrapped.Type != nil {
 		return &unwrapped
+	} else if imported := ctx.lookupImportedType(unwrapped.TypeReference); imported != nil {
+		return imported
 	} else if tt := ctx.lookupUsefulType(unwrapped.TypeReference); tt != nil {
 		module := ctx.lookupUsefulTypeModule(unwrapped.TypeReference)
-		return &TypeAssignment{unwrapped.TypeReference, tt, module}
+		return &TypeAssignment{TypeReference: unwrapped.TypeReference, Type: tt, Module: module}
 	} else {
 		ctx.appendError(errors.New(fmt.Sprintf("Can not resolve TypeReference %v", reference.Name())))
 		return nil
 	}
 }
 
-func (ctx *moduleContext) lookupUsefulType(reference TypeReference) Type {
-	if usefulType, ok := USEFUL_TYPES[reference.Name()]; ok {
-		return usefulType
-	} else {
+// resolveExternalTypeReference resolves type referenced as Module.Type, Module of result is empty if it is
+// the module being generated
+func (ctx *moduleContext) resolveExternalTypeReference(reference ExternalTypeReference) *TypeAssignment {
+	module, assignment, err := ctx.compiler.registry.ResolveExternalType(ctx.module, reference)
+	if err != nil {
+		ctx.appendError(fmt.Errorf("Can not resolve TypeReference %v: %v", reference, err))
 		return nil
 	}
-}
-func (ctx *moduleContext) lookupUsefulTypeModule(reference TypeReference) string {
-	if usefulTypeModule, ok := USEFUL_TYPES_MODULE[reference.Name()]; ok {
-		return usefulTypeModule
-	} else {
-		return ""
+	res := &TypeAssignment{TypeReference: reference.TypeReference, Type: assignment.Type}
+	if module.ModuleIdentifier.Reference != ctx.module.ModuleIdentifier.Reference {
+		res.Module = module.ModuleIdentifier.Reference
 	}
+	return res
 }
-func (ctx *moduleContext) removeWrapperTypes(t Type) Type {
-	for {
-		switch tt := t.(type) {
-		case TaggedType:
-			t = tt.Type
-		case ConstraintedType:
-			t = tt.Type
-		default:
-			return t
-		}
+
+// lookupImportedType resolves type imported from module known to compiler, nil if reference is not imported
+// or import can't be resolved
+func (ctx *moduleContext) lookupImportedType(reference TypeReference) *TypeAssignment {
+	imported, err := ctx.compiler.registry.ResolveSymbol(ctx.module, reference.Name())
+	if err != nil || imported == nil {
+		return nil
+	}
+	if assignment, ok := imported.Assignment.(TypeAssignment); ok {
+		return &TypeAssignment{TypeReference: reference, Type: assignment.Type, Module: imported.Module.ModuleIdentifier.Reference}
 	}
+	return nil
 }
 
+func (ctx *moduleContext) lookupUsefulType(reference TypeReference) Type {
+	usefulType, _ := ctx.compiler.lookupUsefulType(reference.Name())
+	return usefulType
+}
+func (ctx *moduleContext) lookupUsefulTypeModule(reference TypeReference) string {
+	_, module := ctx.compiler.lookupUsefulType(reference.Name())
+	return module
+}
 // unwrapToLeafType walks over transitive type references, tags and constraints and yields "root" type reference
 func (ctx *moduleContext) unwrapToLeafType(reference TypeReference) TypeAssignment {
 	if assignment := ctx.lookupContext.AssignmentList.GetType(reference.Name()); assignment != nil {
 		t := assignment.Type
-		if tt, ok := ctx.removeWrapperTypes(t).(TypeReference); ok {
+		if tt, ok := withoutTags(t).(TypeReference); ok {
 			return ctx.unwrapToLeafType(tt)
 		} else {
 			return *assignment
 		}
 	}
-	return TypeAssignment{reference, nil, ""}
+	return TypeAssignment{TypeReference: reference}
 }
@@ -198,3 +198,240 @@ func TestKerberosRuns(t *testing.T) {
 	}
 
 }
+
+// runWithModule generates first module of source into package main and runs program with it
+func runWithModule(source, program string) error {
+	modules, err := ParseString(source)
+	if err != nil {
+		return err
+	}
+	tempPath, err := utils.CreateTestTemp()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempPath)
+	module, err := os.Create(filepath.Join(tempPath, "module.go"))
+	if err != nil {
+		return err
+	}
+	defer module.Close()
+	if err := NewCodeGenerator(GenParams{Package: "main"}).Generate(modules[0], module); err != nil {
+		return err
+	}
+	driverPath := filepath.Join(tempPath, "main.go")
+	if err := ioutil.WriteFile(driverPath, []byte(program), 0644); err != nil {
+		return err
+	}
+	return utils.RunCommandForResult("go", "run", driverPath, module.Name())
+}
+
+// roundTripDriver is run with generated module, followed by main function of test case
+var roundTripDriver = `
+package main
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+var _, _, _, _ = bytes.Equal, json.Marshal, reflect.DeepEqual, strings.Contains
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+	os.Exit(1)
+}
+
+// roundTrip marshals x, unmarshals the result into y and returns it
+func roundTrip(x, y interface{}) []byte {
+	data, err := asn1.Marshal(x)
+	if err != nil {
+		fail("Marshal error: %v", err)
+	}
+	if _, err := asn1.Unmarshal(data, y); err != nil {
+		fail("Unmarshal error: %v", err)
+	}
+	return data
+}
+`
+
+// roundTripTests check that values of generated types are marshalled and unmarshalled with encoding/asn1 as
+// modules define them, main is body of main function of the program run with generated module
+var roundTripTests = []struct {
+	name   string
+	source string
+	main   string
+}{
+	{
+		name: "CheckRejectsUnknownEnumerated",
+		source: `Test DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+			Color ::= ENUMERATED { red(1), green(5) }
+			Inner ::= SEQUENCE { color Color }
+			Pick ::= CHOICE { inner Inner, n INTEGER }
+			Msg ::= SEQUENCE { color Color, colors SEQUENCE OF Color, inner Inner OPTIONAL, pick Pick }
+		END`,
+		main: `
+	for _, x := range []Msg{
+		{Color: 7, Colors: []asn1.Enumerated{1}},
+		{Color: 1, Colors: []asn1.Enumerated{5, 2}},
+		{Color: 1, Inner: Inner{Color: 7}},
+		{Color: 1, Pick: Pick{Inner: Inner{Color: 7}}},
+	} {
+		var y Msg
+		roundTrip(x, &y)
+		if err := y.Check(); err == nil {
+			fail("Expected %v to be rejected", y)
+		}
+	}
+	var y Msg
+	roundTrip(Msg{Color: asn1.Enumerated(ColorGreen), Colors: []asn1.Enumerated{asn1.Enumerated(ColorRed)}}, &y)
+	if err := y.Check(); err != nil {
+		fail("Unexpected error: %v", err)
+	}
+`,
+	},
+	{
+		name: "ChoiceExtension",
+		source: `Test DEFINITIONS ::= BEGIN
+			Old ::= CHOICE { root [0] INTEGER, ... }
+			New ::= CHOICE { root [0] INTEGER, ..., added [1] BOOLEAN }
+		END`,
+		main: `
+	var old Old
+	data := roundTrip(New{Added: true}, &old)
+	if old.Root != 0 || old.Unknown.Class != asn1.ClassContextSpecific || old.Unknown.Tag != 1 {
+		fail("Expected unknown alternative [1], got %+v", old)
+	}
+	if again, err := asn1.Marshal(old); err != nil || !bytes.Equal(data, again) {
+		fail("Expected %x after round-trip, got %x: %v", data, again, err)
+	}
+`,
+	},
+	{
+		name: "IntegerNamedNumbers",
+		source: `Test DEFINITIONS ::= BEGIN
+			Status ::= INTEGER { ok(0), failed(-1) }
+			Msg ::= SEQUENCE { s INTEGER { a(1), b(2) }, n INTEGER }
+		END`,
+		main: `
+	var y Msg
+	roundTrip(Msg{S: Msg_SB, N: 3}, &y)
+	if y.S != Msg_SB || y.S.String() != "b" || Status(-1).String() != "failed" || Status(3).String() != "3" {
+		fail("Unexpected named numbers of %+v", y)
+	}
+`,
+	},
+	{
+		name: "NamedBits",
+		source: `Test DEFINITIONS ::= BEGIN
+			KDCOptions ::= BIT STRING { reserved(0), forwardable(1), renew(30) }
+			Request ::= SEQUENCE { options KDCOptions }
+		END`,
+		main: `
+	var options KDCOptions
+	options.Set(KDCOptionsRenew)
+	options.Set(KDCOptionsForwardable)
+	options.Clear(KDCOptionsRenew)
+	var y Request
+	roundTrip(Request{Options: asn1.BitString(options)}, &y)
+	got := KDCOptions(y.Options)
+	if !got.Has(KDCOptionsForwardable) || got.Has(KDCOptionsRenew) || got.Has(KDCOptionsReserved) || got.BitLength != 2 {
+		fail("Unexpected bits %+v", got)
+	}
+`,
+	},
+	{
+		name: "ValueNotation",
+		source: `Test DEFINITIONS ::= BEGIN
+			Color ::= ENUMERATED { red, green }
+			Point ::= SEQUENCE { x INTEGER, color Color OPTIONAL, count INTEGER OPTIONAL, tags SEQUENCE OF UTF8String }
+			Points ::= SEQUENCE OF Point
+			origin Point ::= { x 0, color green, tags { "a", "b" } }
+			counted Point ::= { x 1, count 3, tags { "c" } }
+			points Points ::= { { x 1, tags { "y" } }, { x 2, tags { "z" } } }
+		END`,
+		main: `
+	for _, x := range []Point{Origin, Counted, PointsValue[1]} {
+		var y Point
+		roundTrip(x, &y)
+		if !reflect.DeepEqual(x, y) {
+			fail("Expected %+v after round-trip, got %+v", x, y)
+		}
+	}
+	if Origin.Color != asn1.Enumerated(ColorGreen) || len(PointsValue) != 2 || PointsValue[1].Tags[0] != "z" {
+		fail("Unexpected values %+v %+v", Origin, PointsValue)
+	}
+`,
+	},
+	{
+		name: "EnumeratedMarshalling",
+		source: `Test DEFINITIONS ::= BEGIN
+			Color ::= ENUMERATED { red, green(5) }
+			Msg ::= SEQUENCE { color Color }
+		END`,
+		main: `
+	var y Msg
+	data := roundTrip(Msg{Color: asn1.Enumerated(ColorGreen)}, &y)
+	if !bytes.Equal(data, []byte{0x30, 0x03, 0x0a, 0x01, 0x05}) || Color(y.Color) != ColorGreen {
+		fail("Expected ENUMERATED field green, got %x decoded as %v", data, y)
+	}
+	// limitations documented in README
+	if data, err := asn1.Marshal(ColorGreen); err != nil || !bytes.Equal(data, []byte{0x02, 0x01, 0x05}) {
+		fail("Expected Color to be encoded as INTEGER, got %x: %v", data, err)
+	}
+	if data, err := json.Marshal(y); err != nil || string(data) != "{\"color\":5}" {
+		fail("Expected number in JSON of field, got %s: %v", data, err)
+	}
+	data, err := json.Marshal(Color(y.Color))
+	if err != nil || string(data) != "\"green\"" {
+		fail("Expected identifier in JSON, got %s: %v", data, err)
+	}
+	var color Color
+	if err := json.Unmarshal(data, &color); err != nil || color != ColorGreen {
+		fail("Expected green from JSON, got %v: %v", color, err)
+	}
+	if err := json.Unmarshal([]byte("\"blue\""), &color); err == nil {
+		fail("Expected unknown identifier to be rejected")
+	}
+`,
+	},
+	{
+		name: "RecursiveType",
+		source: `Test DEFINITIONS ::= BEGIN
+			Node ::= SEQUENCE { value INTEGER, next Node OPTIONAL }
+			Tree ::= SEQUENCE { value INTEGER, children SEQUENCE OF Tree OPTIONAL }
+		END`,
+		main: `
+	for _, x := range []Tree{{Value: 1}, {Value: 1, Children: []Tree{{Value: 2}, {Value: 3, Children: []Tree{{Value: 4}}}}}} {
+		var y Tree
+		roundTrip(x, &y)
+		if !reflect.DeepEqual(x, y) {
+			fail("Expected %+v after round-trip, got %+v", x, y)
+		}
+	}
+	var y Node
+	roundTrip(Node{Value: 1}, &y)
+	if y.Value != 1 || y.Next != nil {
+		fail("Expected node without next, got %+v", y)
+	}
+	// limitation documented in README
+	if _, err := asn1.Marshal(Node{Value: 1, Next: &Node{Value: 2}}); err == nil || !strings.Contains(err.Error(), "unknown Go type") {
+		fail("Expected pointer to be rejected, got %v", err)
+	}
+`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range roundTripTests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := runWithModule(tc.source, roundTripDriver+"\nfunc main() {"+tc.main+"}\n"); err != nil {
+				t.Fatal(err.Error())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package asn1go
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -25,6 +26,30 @@ func testModule(assignments AssignmentList) ModuleDefinition {
 	}
 }
 
+// generateString parses source and generates declarations of its first module, failing test on errors
+func generateString(t *testing.T, source string) string {
+	t.Helper()
+	modules, err := ParseString(source)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	got, err := generateDeclarationsString(modules[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	return got
+}
+
+// assertContains reports each of fragments missing from got
+func assertContains(t *testing.T, got string, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("Expected %q in output, got:\n%v", fragment, got)
+		}
+	}
+}
+
 func TestDeclMinSynax(t *testing.T) {
 	m := ModuleDefinition{
 		ModuleIdentifier: ModuleIdentifier{Reference: "My-ASN1-ModuleName"},
@@ -45,11 +70,11 @@ func TestDeclPrimitiveTypesSyntax(t *testing.T) {
 		ModuleIdentifier: ModuleIdentifier{Reference: "My-ASN1-ModuleName"},
 		ModuleBody: ModuleBody{
 			AssignmentList: AssignmentList{
-				TypeAssignment{TypeReference("MyBool"), BooleanType{}, ""},
-				TypeAssignment{TypeReference("MyInt"), IntegerType{}, ""},
-				TypeAssignment{TypeReference("MyString"), CharacterStringType{}, ""},
-				TypeAssignment{TypeReference("MyOctetString"), OctetStringType{}, ""},
-				TypeAssignment{TypeReference("MyReal"), RealType{}, ""},
+				TypeAssignment{TypeReference: TypeReference("MyBool"), Type: BooleanType{}},
+				TypeAssignment{TypeReference: TypeReference("MyInt"), Type: IntegerType{}},
+				TypeAssignment{TypeReference: TypeReference("MyString"), Type: CharacterStringType{}},
+				TypeAssignment{TypeReference: TypeReference("MyOctetString"), Type: OctetStringType{}},
+				TypeAssignment{TypeReference: TypeReference("MyReal"), Type: RealType{}},
 			},
 		},
 	}
@@ -72,7 +97,7 @@ type MyReal float64
 
 func TestDeclSequenceTypeSyntax(t *testing.T) {
 	m := testModule(AssignmentList{
-		TypeAssignment{TypeReference("MySequence"), SequenceType{Components: ComponentTypeList{
+		TypeAssignment{TypeReference: TypeReference("MySequence"), Type: SequenceType{Components: ComponentTypeList{
 			NamedComponentType{NamedType: NamedType{
 				Identifier: Identifier("myIntField"),
 				Type:       IntegerType{},
@@ -86,7 +111,7 @@ func TestDeclSequenceTypeSyntax(t *testing.T) {
 					}},
 				}},
 			}},
-		}}, ""},
+		}}},
 	})
 	expected := `package My_ASN1_ModuleName
 
@@ -108,13 +133,13 @@ type MySequence struct {
 
 func TestDeclSequenceOFTypeSyntax(t *testing.T) {
 	m := testModule(AssignmentList{
-		TypeAssignment{TypeReference("MySequenceOfInt"), SequenceOfType{IntegerType{}}, ""},
-		TypeAssignment{TypeReference("MySequenceOfSequence"), SequenceOfType{SequenceType{Components: ComponentTypeList{
+		TypeAssignment{TypeReference: TypeReference("MySequenceOfInt"), Type: SequenceOfType{Type: IntegerType{}}},
+		TypeAssignment{TypeReference: TypeReference("MySequenceOfSequence"), Type: SequenceOfType{Type: SequenceType{Components: ComponentTypeList{
 			NamedComponentType{NamedType: NamedType{
 				Identifier: Identifier("myIntField"),
 				Type:       IntegerType{},
 			}}},
-		}}, ""},
+		}}},
 	})
 	expected := `package My_ASN1_ModuleName
 
@@ -134,12 +159,12 @@ type MySequenceOfSequence []struct {
 
 func TestTags(t *testing.T) {
 	m := testModule(AssignmentList{
-		TypeAssignment{TypeReference("MySequence"), SequenceType{Components: ComponentTypeList{
+		TypeAssignment{TypeReference: TypeReference("MySequence"), Type: SequenceType{Components: ComponentTypeList{
 			NamedComponentType{NamedType: NamedType{
 				Identifier: Identifier("myStringField"),
 				Type:       RestrictedStringType{IA5String},
 			}},
-		}}, ""},
+		}}},
 	})
 	expected := `package My_ASN1_ModuleName
 
@@ -158,13 +183,13 @@ type MySequence struct {
 
 func TestTime(t *testing.T) {
 	m := testModule(AssignmentList{
-		TypeAssignment{TypeReference("MyTimeType"), TypeReference("GeneralizedTime"), ""},
-		TypeAssignment{TypeReference("MySequence"), SequenceType{Components: ComponentTypeList{
+		TypeAssignment{TypeReference: TypeReference("MyTimeType"), Type: TypeReference("GeneralizedTime")},
+		TypeAssignment{TypeReference: TypeReference("MySequence"), Type: SequenceType{Components: ComponentTypeList{
 			NamedComponentType{NamedType: NamedType{
 				Identifier: Identifier("myTimeField"),
 				Type:       TypeReference("MyTimeType"),
 			}},
-		}}, ""},
+		}}},
 	})
 	expected := `package My_ASN1_ModuleName
 
@@ -186,7 +211,7 @@ type MySequence struct {
 
 func TestBitString(t *testing.T) {
 	m := testModule(AssignmentList{
-		TypeAssignment{TypeReference("MyBitStringType"), ConstraintedType{
+		TypeAssignment{TypeReference: TypeReference("MyBitStringType"), Type: ConstraintedType{
 			Type: BitStringType{},
 			Constraint: Constraint{ConstraintSpec: SubtypeConstraint{
 				Unions{Intersections{IntersectionElements{Elements: SizeConstraint{Constraint: Constraint{ConstraintSpec: SubtypeConstraint{
@@ -199,9 +224,9 @@ func TestBitString(t *testing.T) {
 				},
 				}}},
 			}},
-		}, ""},
-		TypeAssignment{TypeReference("MyNestedBitStringType"), TypeReference("MyBitStringType"), ""},
-		TypeAssignment{TypeReference("MySequence"), SequenceType{Components: ComponentTypeList{
+		}},
+		TypeAssignment{TypeReference: TypeReference("MyNestedBitStringType"), Type: TypeReference("MyBitStringType")},
+		TypeAssignment{TypeReference: TypeReference("MySequence"), Type: SequenceType{Components: ComponentTypeList{
 			NamedComponentType{NamedType: NamedType{
 				Identifier: Identifier("myNestedBitStringField"),
 				Type:       TypeReference("MyNestedBitStringType"),
@@ -214,7 +239,7 @@ func TestBitString(t *testing.T) {
 				Identifier: Identifier("bitStringField"),
 				Type:       BitStringType{},
 			}},
-		}}, ""},
+		}}},
 	})
 	expected := `package My_ASN1_ModuleName
 
@@ -236,3 +261,497 @@ type MySequence struct {
 		t.Errorf("Output did not match\n\nExp:\n`%v`\n\nGot:\n`%v`", expected, got)
 	}
 }
+
+func TestDocComments(t *testing.T) {
+	comment := func(text string) CommentGroup { return CommentGroup{{Text: text}} }
+	m := testModule(AssignmentList{
+		TypeAssignment{TypeReference: TypeReference("MyInt"), Type: IntegerType{}, Doc: comment("Small number")},
+		TypeAssignment{
+			TypeReference: TypeReference("MySequence"),
+			Doc:           CommentGroup{{Text: "Sequence doc"}, {Text: "-----"}, {Text: "spans\n   lines"}},
+			LineComment:   comment("line comment"),
+			Type: SequenceType{Components: ComponentTypeList{
+				NamedComponentType{NamedType: NamedType{
+					Identifier: Identifier("first"),
+					Type:       IntegerType{},
+					Doc:        comment("First field"),
+				}},
+				NamedComponentType{NamedType: NamedType{
+					Identifier:  Identifier("second"),
+					Type:        BooleanType{},
+					LineComment: comment("Second field"),
+				}},
+			}},
+		},
+	})
+	m.Doc = comment("Module doc")
+	expected := `// Module doc
+package MyASN1ModuleName
+
+// Small number
+type MyInt int64
+
+// Sequence doc
+//
+// spans
+// lines
+// line comment
+type MySequence struct {
+	// First field
+	First	int64	` + "`" + `xml:"first" json:"first"` + "`" + `
+	// Second field
+	Second	bool	` + "`" + `xml:"second" json:"second"` + "`" + `
+}
+`
+	got, err := generateDeclarationsString(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	if got != expected {
+		t.Errorf("Output did not match\n\nExp:\n`%v`\n\nGot:\n`%v`", expected, got)
+	}
+}
+
+func TestTagDefinedValue(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		id-tag INTEGER ::= 5
+		MySequence ::= SEQUENCE { field [id-tag] INTEGER }
+	END`)
+	assertContains(t, got, `asn1:"explicit,tag:5"`)
+}
+
+func TestSequenceExtensionFields(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		MySequence ::= SEQUENCE { root INTEGER, ..., [[ 2: added INTEGER ]], ..., last INTEGER }
+	END`)
+	root, added, last := strings.Index(got, "Root\t"), strings.Index(got, "Added\t"), strings.Index(got, "Last\t")
+	if root < 0 || !(root < added && added < last) {
+		t.Errorf("Expected fields Root, Added and Last in order, got:\n%v", got)
+	}
+	assertContains(t, got, `xml:"added,omitempty" json:"added,omitempty" asn1:"optional"`)
+}
+
+func TestChoiceExtensionFields(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Closed ::= CHOICE { root INTEGER }
+		Open ::= CHOICE { root INTEGER, ..., [[ 2: added INTEGER ]], extra BOOLEAN }
+		Named ::= CHOICE { unknown INTEGER, ... }
+	END`)
+	closed, open := got[strings.Index(got, "type Closed"):strings.Index(got, "type Open")], got[strings.Index(got, "type Open"):strings.Index(got, "type Named")]
+	if strings.Contains(closed, "optional") || strings.Contains(closed, "RawValue") {
+		t.Errorf("Expected required alternatives of closed CHOICE, got:\n%v", closed)
+	}
+	assertContains(t, open,
+		`Root	int64		`+"`"+`xml:"root,omitempty" json:"root,omitempty" asn1:"optional"`,
+		`Added	int64		`+"`"+`xml:"added,omitempty" json:"added,omitempty" asn1:"optional"`,
+		`Extra	bool		`+"`"+`xml:"extra,omitempty" json:"extra,omitempty" asn1:"optional"`,
+		`Unknown	asn1.RawValue	`+"`"+`xml:"-" json:"-" asn1:"optional"`,
+	)
+	assertContains(t, got, "Unknown_\tasn1.RawValue\t")
+}
+
+func TestGenerateIntegerNamedNumbers(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		max INTEGER ::= 10
+		Status ::= INTEGER { ok(0), failed(-1), limit(max) }
+		Msg ::= SEQUENCE { s INTEGER { a(1), b(2) }, n INTEGER }
+		msg Msg ::= { s b, n 3 }
+	END`)
+	assertContains(t, got,
+		"type Status int64\n",
+		"StatusOk\tStatus\t= 0\n",
+		"StatusFailed\tStatus\t= -1\n",
+		"StatusLimit\tStatus\t= 10\n",
+		"func (v Status) String() string {",
+		"case StatusFailed:\n\t\treturn \"failed\"\n",
+		"return strconv.FormatInt(int64(v), 10)",
+		"S\tMsg_S\t",
+		"N\tint64\t",
+		"type Msg_S int64\n",
+		"Msg_SB\tMsg_S\t= 2\n",
+		"func (v Msg_S) String() string {",
+		"var MsgValue = Msg{S: Msg_SB, N: 3}",
+	)
+}
+
+func TestGenerateEnumerated(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Color ::= ENUMERATED { red, green(5), blue }
+		Open ::= ENUMERATED { a, b(3), ..., c, d(7), e }
+		Msg ::= SEQUENCE { mode ENUMERATED { on, off } DEFAULT off, color Color DEFAULT green }
+	END`)
+	closed, open := got[strings.Index(got, "type Color"):strings.Index(got, "type Open")], got[strings.Index(got, "type Open"):]
+	assertContains(t, closed,
+		"type Color asn1.Enumerated\n",
+		"ColorRed\tColor\t= 0\n",
+		"ColorGreen\tColor\t= 5\n",
+		"ColorBlue\tColor\t= 1\n",
+		"func (v Color) String() string {",
+		"func ParseColor(s string) (Color, error) {",
+		"case \"blue\":\n\t\treturn ColorBlue, nil\n",
+		"func (v Color) MarshalText() ([]byte, error) {",
+		"case ColorRed, ColorGreen, ColorBlue:\n\t\treturn []byte(v.String()), nil\n\t}\n\treturn nil, fmt.Errorf(\"unknown Color %d\", v)",
+		"func (v *Color) UnmarshalText(text []byte) error {",
+		"func (v Color) Valid() bool {\n\tswitch v {\n\tcase ColorRed, ColorGreen, ColorBlue:\n\t\treturn true\n\t}\n\treturn false\n}",
+		// methods are separated by blank lines
+		"return strconv.FormatInt(int64(v), 10)\n}\n\nfunc (v Color) Valid() bool {",
+	)
+	if strings.Contains(closed, "strconv.Atoi") {
+		t.Errorf("Expected numbers to be rejected by ParseColor, got:\n%v", closed)
+	}
+	assertContains(t, open,
+		"OpenC\tOpen\t= 1\n",
+		"OpenD\tOpen\t= 7\n",
+		"OpenE\tOpen\t= 8\n",
+		"if n, err := strconv.Atoi(s); err == nil {\n\t\treturn Open(n), nil\n\t}",
+		"func (v Open) MarshalText() ([]byte, error) {\n\treturn []byte(v.String()), nil\n}",
+		// encoding/asn1 encodes only asn1.Enumerated as ENUMERATED
+		"Mode\tasn1.Enumerated\t`xml:\"mode\" json:\"mode\" asn1:\"default:1\"`",
+		"Color\tasn1.Enumerated\t`xml:\"color,omitempty\" json:\"color,omitempty\" asn1:\"default:5\"`",
+		"type Msg_Mode asn1.Enumerated\n",
+		"Msg_ModeOff\tMsg_Mode\t= 1\n",
+		"func ParseMsg_Mode(s string) (Msg_Mode, error) {",
+		"func (v Msg_Mode) Valid() bool {",
+		"func (v *Msg) Check() error {",
+		"if !Msg_Mode(v.Mode).Valid() {\n\t\treturn fmt.Errorf(\"mode: unknown Msg_Mode %d\", v.Mode)\n\t}",
+		"if !Color(v.Color).Valid() {\n\t\treturn fmt.Errorf(\"color: unknown Color %d\", v.Color)\n\t}",
+	)
+	if strings.Contains(open, "func (v Open) Valid") {
+		t.Errorf("Expected numbers of extensible Open to be valid, got:\n%v", open)
+	}
+}
+
+func TestGenerateDefaultValueReference(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		ub2 INTEGER ::= 5
+		ub INTEGER ::= ub2
+		on BOOLEAN ::= TRUE
+		Msg ::= SEQUENCE { y INTEGER DEFAULT ub2, z INTEGER DEFAULT ub, b BOOLEAN DEFAULT on }
+	END`)
+	assertContains(t, got,
+		"asn1:\"default:5\"`",
+		"json:\"z\" asn1:\"default:5\"`",
+		"json:\"b\"`",
+	)
+}
+
+func TestGenerateCheck(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Color ::= ENUMERATED { red(1), green(5) }
+		Open ::= ENUMERATED { a, ... }
+		Colors ::= SEQUENCE OF [1] Color
+		Other ::= Msg
+		Node ::= SEQUENCE { next Node OPTIONAL, color Color }
+		Msg ::= SEQUENCE {
+			kind ENUMERATED { a, b } OPTIONAL,
+			list SEQUENCE OF SEQUENCE { color Color },
+			choice CHOICE { color Color, n INTEGER },
+			colors Colors,
+			node Node,
+			open Open
+		}
+		Plain ::= SEQUENCE { open Open, n INTEGER }
+		Holder ::= SEQUENCE { node Node OPTIONAL, n INTEGER }
+		Pick ::= CHOICE { node Node, n INTEGER }
+	END`)
+	assertContains(t, got,
+		"func (v *Colors) Check() error {\n\tif v == nil {\n\t\treturn nil\n\t}\n\tfor i := range *v {\n\t\tif !Color((*v)[i]).Valid() {\n\t\t\treturn fmt.Errorf(\"item: unknown Color %d\", (*v)[i])\n\t\t}\n\t}\n\treturn nil\n}",
+		"func (v *Other) Check() error {\n\tif v == nil {\n\t\treturn nil\n\t}\n\treturn (*Msg)(v).Check()\n}",
+		// absent OPTIONAL values and alternatives not chosen are nil pointers or zero structs
+		"if v.Next != nil {\n\t\tif err := v.Next.Check(); err != nil {\n\t\t\treturn fmt.Errorf(\"next: %w\", err)\n\t\t}\n\t}",
+		"func (v *Holder) Check() error {\n\tif v == nil {\n\t\treturn nil\n\t}\n\tif !reflect.ValueOf(v.Node).IsZero() {\n\t\tif err := v.Node.Check(); err != nil {",
+		"func (v *Pick) Check() error {\n\tif v == nil {\n\t\treturn nil\n\t}\n\tif !reflect.ValueOf(v.Node).IsZero() {\n\t\tif err := v.Node.Check(); err != nil {",
+		"if v.Kind != 0 && !Msg_Kind(v.Kind).Valid() {",
+		"for i := range v.List {\n\t\tif err := v.List[i].Check(); err != nil {\n\t\t\treturn fmt.Errorf(\"list: %w\", err)\n\t\t}\n\t}",
+		"func (v *Msg_List) Check() error {",
+		"if err := v.Choice.Check(); err != nil {",
+		"func (v *Msg_Choice) Check() error {\n\tif v == nil {\n\t\treturn nil\n\t}\n\tif v.Color != 0 && !Color(v.Color).Valid() {",
+		"if err := v.Colors.Check(); err != nil {",
+		"if err := v.Node.Check(); err != nil {",
+	)
+	for _, unexpected := range []string{"v.Open", "func (v *Plain) Check", "func (v *Color) Check"} {
+		if strings.Contains(got, unexpected) {
+			t.Errorf("Unexpected %q in output, got:\n%v", unexpected, got)
+		}
+	}
+}
+
+func TestGenerateNamedBits(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		id-renew INTEGER ::= 30
+		KDCOptions ::= BIT STRING { reserved(0), forwardable(1), renew(id-renew) }
+		Request ::= SEQUENCE { options KDCOptions }
+	END`)
+	assertContains(t, got,
+		"type KDCOptions asn1.BitString\n",
+		"KDCOptionsReserved\t= 0\n",
+		"KDCOptionsForwardable\t= 1\n",
+		"KDCOptionsRenew\t\t= 30\n",
+		"func (b KDCOptions) Has(bit int) bool {\n\treturn asn1.BitString(b).At(bit) == 1\n}",
+		"func (b *KDCOptions) Set(bit int) {",
+		"b.Bytes[bit/8] |= 0x80 >> uint(bit%8)",
+		"func (b *KDCOptions) Clear(bit int) {",
+		"for b.BitLength > 0 && !b.Has(b.BitLength-1) {\n\t\tb.BitLength--\n\t}\n\tb.Bytes = b.Bytes[:(b.BitLength+7)/8]",
+		"Options asn1.BitString ",
+	)
+}
+
+func TestGenerateValues(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Color ::= INTEGER { red(0), green(1) }
+		Flags ::= BIT STRING { a(0), b(1) }
+		Bytes ::= OCTET STRING
+		Ratio ::= REAL
+		ub-name INTEGER ::= 32768
+		max INTEGER ::= ub-name
+		flag BOOLEAN ::= TRUE
+		pi REAL ::= 3.14
+		one REAL ::= 1
+		inf Ratio ::= PLUS-INFINITY
+		greeting UTF8String ::= "hello"
+		bits BIT STRING ::= '1010'B
+		flags Flags ::= '0A'H
+		bytes Bytes ::= '0FA'H
+		color Color ::= green
+		inline INTEGER { x(7) } ::= x
+	END`)
+	assertContains(t, got,
+		"const UbName int64 = 32768\n",
+		"const Max int64 = 32768\n",
+		"const Flag bool = true\n",
+		"const Pi float64 = 3.14\n",
+		"const One float64 = 1\n",
+		"var Inf = Ratio(math.Inf(1))\n",
+		"const Greeting string = \"hello\"\n",
+		"var Bits = asn1.BitString{Bytes: []byte{0xa0}, BitLength: 4}\n",
+		"var FlagsValue = Flags{Bytes: []byte{0x0a}, BitLength: 8}\n",
+		"var BytesValue = Bytes{0x0f, 0xa0}\n",
+		"const ColorValue Color = ColorGreen\n",
+		"const Inline int64 = 7\n",
+	)
+
+	modules, err := ParseString(`Test DEFINITIONS ::= BEGIN
+		flag BOOLEAN ::= 1
+		color INTEGER { red(0) } ::= blue
+	END`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	_, err = generateDeclarationsString(modules[0])
+	if err == nil {
+		t.Fatalf("Expected errors for values not matching their types")
+	}
+	for _, exp := range []string{"value flag: value 1 does not match type", "value color: blue is neither named number nor value"} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("Expected %q in error, got: %v", exp, err)
+		}
+	}
+}
+
+func TestGenerateValueCycle(t *testing.T) {
+	modules, err := ParseString(`Test DEFINITIONS ::= BEGIN
+		a INTEGER ::= b
+		b INTEGER ::= a
+		c INTEGER ::= 1
+	END`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	_, err = generateDeclarationsString(modules[0])
+	if err == nil {
+		t.Fatalf("Expected error for cycle of values")
+	}
+	if exp := "Errors generating Go AST from module: \n  value reference cycle: b -> a -> b\n"; err.Error() != exp {
+		t.Errorf("Expected single error %q, got: %v", exp, err)
+	}
+}
+
+func TestGenerateCompositeValues(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Color ::= ENUMERATED { red, green }
+		Flags ::= BIT STRING { a(0), b(1), c(9) }
+		Count ::= INTEGER
+		Point ::= SEQUENCE { x INTEGER, color Color OPTIONAL, count Count OPTIONAL, tags SEQUENCE OF UTF8String }
+		Shape ::= CHOICE { point Point, none NULL }
+		Points ::= SEQUENCE OF Point
+		origin Point ::= { x 0, color green, tags { "a", "b" } }
+		counted Point ::= { x 1, count 3, tags { } }
+		shape Shape ::= point : { x 1, tags {} }
+		nothing Shape ::= none : NULL
+		points Points ::= { { x 1, tags {} }, { x 2, tags { "z" } } }
+		flags Flags ::= { a, c }
+		color Color ::= red
+		Id ::= OBJECT IDENTIFIER
+		id Id ::= { iso 3 }
+		ints SET OF INTEGER ::= { 1, 2, 3 }
+		greeting UTF8String ::= { "hello ", name }
+		name IA5String ::= "world"
+	END`)
+	assertContains(t, got,
+		"var Origin = Point{X: 0, Color: asn1.Enumerated(ColorGreen), Tags: []string{\"a\", \"b\"}}\n",
+		"var Counted = Point{X: 1, Count: 3, Tags: []string{}}\n",
+		"var ShapeValue = Shape{Point: Point{X: 1, Tags: []string{}}}\n",
+		"var Nothing = Shape{None: nil}\n",
+		"var PointsValue = Points{Point{X: 1, Tags: []string{}}, Point{X: 2, Tags: []string{\"z\"}}}\n",
+		"var FlagsValue = Flags{Bytes: []byte{0x80, 0x40}, BitLength: 10}\n",
+		"const ColorValue Color = ColorRed\n",
+		"var IdValue = Id{1, 3}\n",
+		"var Ints = []int64{1, 2, 3}\n",
+		"const Greeting string = \"hello world\"\n",
+	)
+}
+
+func TestGenerateSequenceValueComponents(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Color ::= ENUMERATED { red, green }
+		Pt ::= SEQUENCE { x INTEGER, y INTEGER OPTIONAL, c Color DEFAULT green, on BOOLEAN DEFAULT TRUE }
+		Unordered ::= SET { a INTEGER, b INTEGER }
+		pt Pt ::= { x 2 }
+		unordered Unordered ::= { b 1, a 2 }
+	END`)
+	assertContains(t, got,
+		"var PtValue = Pt{X: 2, C: asn1.Enumerated(ColorGreen), On: true}\n",
+		"var UnorderedValue = Unordered{A: 2, B: 1}\n",
+	)
+
+	modules, err := ParseString(`Test DEFINITIONS ::= BEGIN
+		Pt ::= SEQUENCE { x INTEGER, y INTEGER }
+		dup Pt ::= { x 1, x 2, y 3 }
+		bad Pt ::= { y 1 }
+		swapped Pt ::= { y 1, x 2 }
+	END`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	_, err = generateDeclarationsString(modules[0])
+	if err == nil {
+		t.Fatalf("Expected errors for invalid components")
+	}
+	for _, exp := range []string{
+		"value dup: component x of SEQUENCE is given more than once",
+		"value bad: SEQUENCE value lacks component x",
+		"value swapped: component x of SEQUENCE is out of order",
+	} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("Expected %q in error, got: %v", exp, err)
+		}
+	}
+}
+
+func TestGenerateTagDefault(t *testing.T) {
+	modules, err := ParseString(`Implicit DEFINITIONS IMPLICIT TAGS ::= BEGIN
+		Choice ::= CHOICE { a INTEGER, b BOOLEAN }
+		TaggedChoice ::= [5] Choice
+		Counter ::= [APPLICATION 1] IMPLICIT INTEGER
+		IpAddress ::= [APPLICATION 0] IMPLICIT OCTET STRING
+		Address ::= CHOICE { internet IpAddress }
+		Message ::= SEQUENCE {
+			implicit [0] INTEGER,
+			explicit [1] EXPLICIT INTEGER,
+			keyword [2] IMPLICIT INTEGER,
+			choice [3] Choice,
+			inline [4] CHOICE { c INTEGER },
+			tagged [6] TaggedChoice,
+			private [PRIVATE 7] INTEGER,
+			counter Counter,
+			taggedRef TaggedChoice,
+			choiceRef Choice,
+			address Address
+		}
+	END
+	Auto DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Message ::= SEQUENCE { manual [0] INTEGER }
+	END
+	Default DEFINITIONS ::= BEGIN
+		R ::= [APPLICATION 1] INTEGER
+		Message ::= SEQUENCE { default [0] INTEGER, r R }
+	END`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	for i, expected := range [][]string{
+		{
+			"Implicit\tint64\t\t`xml:\"implicit\" json:\"implicit\" asn1:\"tag:0\"`",
+			"Explicit\tint64\t\t`xml:\"explicit\" json:\"explicit\" asn1:\"explicit,tag:1\"`",
+			"Keyword\t\tint64\t\t`xml:\"keyword\" json:\"keyword\" asn1:\"tag:2\"`",
+			"asn1:\"explicit,tag:3\"",
+			"asn1:\"explicit,tag:4\"",
+			"asn1:\"tag:6\"",
+			"asn1:\"private,tag:7\"",
+			"json:\"counter,omitempty\" asn1:\"application,tag:1\"",
+			"json:\"taggedRef,omitempty\" asn1:\"explicit,tag:5\"",
+			"json:\"choiceRef,omitempty\"`",
+			"json:\"address,omitempty\"`",
+			"json:\"internet,omitempty\" asn1:\"application,tag:0\"`",
+		},
+		{"asn1:\"tag:0\""},
+		{"asn1:\"explicit,tag:0\"", "json:\"r,omitempty\" asn1:\"application,explicit,tag:1\""},
+	} {
+		got, err := generateDeclarationsString(modules[i])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		assertContains(t, got, expected...)
+	}
+}
+
+func TestGenerateAutomaticTags(t *testing.T) {
+	got := generateString(t, `Auto DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Choice ::= CHOICE { a INTEGER, b BOOLEAN }
+		Message ::= SEQUENCE { number INTEGER, choice Choice, flag BOOLEAN OPTIONAL }
+		Base ::= SEQUENCE { x INTEGER }
+		Extended ::= SEQUENCE { COMPONENTS OF Base, y INTEGER, ..., added [7] INTEGER, last INTEGER }
+	END`)
+	assertContains(t, got,
+		"asn1:\"tag:0\"`",
+		"asn1:\"explicit,tag:1\"`",
+		"asn1:\"optional,tag:2\"`",
+		"json:\"y\" asn1:\"tag:1\"`",
+		"json:\"added,omitempty\" asn1:\"optional,tag:7\"`",
+		"json:\"last,omitempty\" asn1:\"optional,tag:3\"`",
+	)
+}
+
+func TestGenerateRecursiveTypes(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS ::= BEGIN
+		Node ::= SEQUENCE { value INTEGER, next Node OPTIONAL }
+		Tree ::= SEQUENCE { children SEQUENCE OF Tree }
+		Holder ::= SEQUENCE { node Node, name UTF8String }
+		node Node ::= { value 1, next { value 2 } }
+	END`)
+	assertContains(t, got,
+		"Next\t*Node\t",
+		"Children []Tree ",
+		"Node\tNode\t",
+		"var NodeValue = Node{Value: 1, Next: &Node{Value: 2}}",
+	)
+}
+
+func TestGenerateInlineTypes(t *testing.T) {
+	got := generateString(t, `Test DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Request ::= SEQUENCE {
+			req-body SEQUENCE { id INTEGER, kind CHOICE { a INTEGER, b BOOLEAN } },
+			items SEQUENCE OF SEQUENCE { name UTF8String }
+		}
+		List ::= SET OF CHOICE { a INTEGER, b BOOLEAN }
+		A ::= SEQUENCE { b SEQUENCE { c INTEGER } }
+		A-B ::= INTEGER
+		request Request ::= { req-body { id 1, kind b : TRUE }, items { { name "x" } } }
+	END`)
+	assertContains(t, got,
+		"ReqBody\tRequest_ReqBody\t",
+		"Items\t[]Request_Items\t",
+		"type Request_ReqBody struct {\n\tId\tint64\t",
+		"Kind\tRequest_ReqBody_Kind\t",
+		"type Request_ReqBody_Kind struct {",
+		"type Request_Items struct {\n\tName string ",
+		"type List []List_Item\n\ntype List_Item struct {",
+		"B A_B2 ",
+		"type A_B2 struct {",
+		"type A_B int64",
+		"var RequestValue = Request{ReqBody: Request_ReqBody{Id: 1, Kind: Request_ReqBody_Kind{B: true}}, Items: []Request_Items{Request_Items{Name: \"x\"}}}",
+	)
+	if strings.Contains(got, "\tstruct {") {
+		t.Errorf("Expected no anonymous structs, got:\n%v", got)
+	}
+}
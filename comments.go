@@ -0,0 +1,178 @@
+package asn1go
+
+// commentGroup is a run of comments between the same pair of tokens, see CommentGroup
+type commentGroup []lexedComment
+
+func (g commentGroup) comments() CommentGroup {
+	res := make(CommentGroup, len(g))
+	for i, c := range g {
+		res[i] = c.Comment
+	}
+	return res
+}
+
+// groupComments splits comments into groups. Comment that follows a token on the same line starts a new group
+// which holds only comments on that line.
+func groupComments(comments []lexedComment) []commentGroup {
+	var groups []commentGroup
+	for _, c := range comments {
+		if n := len(groups); n > 0 {
+			group := groups[n-1]
+			first, last := group[0], group[len(group)-1]
+			if c.next == last.next && c.Span.Start.Line <= last.Span.End.Line+1 &&
+				(!first.trailing || c.Span.Start.Line == first.Span.Start.Line) {
+				groups[n-1] = append(group, c)
+				continue
+			}
+		}
+		groups = append(groups, commentGroup{c})
+	}
+	return groups
+}
+
+// commentAttacher indexes comment groups by tokens around them
+type commentAttacher struct {
+	docs  map[Position]commentGroup // leading groups by start of the following token
+	lines map[Position]commentGroup // trailing groups by end of the preceding node
+}
+
+func newCommentAttacher(comments []lexedComment) *commentAttacher {
+	a := &commentAttacher{docs: map[Position]commentGroup{}, lines: map[Position]commentGroup{}}
+	for _, group := range groupComments(comments) {
+		first := group[0]
+		if !first.trailing {
+			a.docs[first.next] = group
+			continue
+		}
+		a.lines[first.prevEnd] = group
+		if first.prevComma {
+			// comment after comma separating components belongs to the component before comma
+			a.lines[first.commaEnd] = group
+		}
+	}
+	return a
+}
+
+// doc returns comments right before node, the last of them ending on the line before the node or on its first line
+func (a *commentAttacher) doc(span Span) CommentGroup {
+	group, ok := a.docs[span.Start]
+	if !ok || group[len(group)-1].Span.End.Line < span.Start.Line-1 {
+		return nil
+	}
+	return group.comments()
+}
+
+// lineComment returns comments following node on its last line
+func (a *commentAttacher) lineComment(span Span) CommentGroup {
+	group, ok := a.lines[span.End]
+	if !ok || group[0].Span.Start.Line != span.End.Line {
+		return nil
+	}
+	return group.comments()
+}
+
+// attachComments fills Doc and LineComment of modules, assignments and named types with comments found by lexer
+func attachComments(modules []ModuleDefinition, comments []lexedComment) {
+	if len(comments) == 0 {
+		return
+	}
+	a := newCommentAttacher(comments)
+	for i := range modules {
+		module := &modules[i]
+		module.Doc = a.doc(module.Span)
+		for j, assignment := range module.ModuleBody.AssignmentList {
+			module.ModuleBody.AssignmentList[j] = a.attachAssignment(assignment)
+		}
+	}
+}
+
+func (a *commentAttacher) attachAssignment(assignment Assignment) Assignment {
+	switch x := assignment.(type) {
+	case TypeAssignment:
+		x.Doc = a.doc(x.Span)
+		x.LineComment = a.lineComment(x.Span)
+		x.Type = a.attachType(x.Type)
+		return x
+	case ValueAssignment:
+		x.Doc = a.doc(x.Span)
+		x.LineComment = a.lineComment(x.Span)
+		x.Type = a.attachType(x.Type)
+		return x
+	}
+	return assignment
+}
+
+func (a *commentAttacher) attachType(t Type) Type {
+	switch x := t.(type) {
+	case SequenceType:
+		x.Components = a.attachComponents(x.Components)
+		x.ExtensionAdditions = a.attachExtensionAdditions(x.ExtensionAdditions)
+		x.TrailingRootComponents = a.attachComponents(x.TrailingRootComponents)
+		return x
+	case SetType:
+		x.Components = a.attachComponents(x.Components)
+		x.ExtensionAdditions = a.attachExtensionAdditions(x.ExtensionAdditions)
+		x.TrailingRootComponents = a.attachComponents(x.TrailingRootComponents)
+		return x
+	case ChoiceType:
+		for i, alternative := range x.AlternativeTypeList {
+			x.AlternativeTypeList[i] = a.attachNamedType(alternative, alternative.Span)
+		}
+		for i, extension := range x.ExtensionTypes {
+			switch e := extension.(type) {
+			case NamedType:
+				x.ExtensionTypes[i] = a.attachNamedType(e, e.Span)
+			case ExtensionAdditionAlternativesGroup:
+				for j, alternative := range e.AlternativeTypeList {
+					e.AlternativeTypeList[j] = a.attachNamedType(alternative, alternative.Span)
+				}
+				x.ExtensionTypes[i] = e
+			}
+		}
+		return x
+	case TaggedType:
+		x.Type = a.attachType(x.Type)
+		return x
+	case ConstraintedType:
+		x.Type = a.attachType(x.Type)
+		return x
+	case SequenceOfType:
+		x.Type = a.attachType(x.Type)
+		return x
+	case SetOfType:
+		x.Type = a.attachType(x.Type)
+		return x
+	}
+	return t
+}
+
+func (a *commentAttacher) attachComponents(components ComponentTypeList) ComponentTypeList {
+	for i, component := range components {
+		if c, ok := component.(NamedComponentType); ok {
+			// comments of component span OPTIONAL and DEFAULT, but are kept with its NamedType
+			c.NamedType = a.attachNamedType(c.NamedType, c.Span)
+			components[i] = c
+		}
+	}
+	return components
+}
+
+func (a *commentAttacher) attachExtensionAdditions(additions []ExtensionAddition) []ExtensionAddition {
+	for i, addition := range additions {
+		switch x := addition.(type) {
+		case NamedComponentType:
+			additions[i] = a.attachComponents(ComponentTypeList{x})[0].(NamedComponentType)
+		case ExtensionAdditionGroup:
+			x.Components = a.attachComponents(x.Components)
+			additions[i] = x
+		}
+	}
+	return additions
+}
+
+func (a *commentAttacher) attachNamedType(t NamedType, span Span) NamedType {
+	t.Doc = a.doc(span)
+	t.LineComment = a.lineComment(span)
+	t.Type = a.attachType(t.Type)
+	return t
+}
@@ -0,0 +1,84 @@
+package asn1go
+
+import (
+	"io"
+	"strings"
+)
+
+// Compiler holds state of compiling a set of ASN.1 modules: registry of modules parsed so far and types
+// visible across them, including built-in useful types. Compilers don't share any state, so separate schemas
+// can be compiled concurrently, each with its own Compiler. A single Compiler is not safe for concurrent use.
+type Compiler struct {
+	registry          *Registry
+	usefulTypes       map[string]Type   // built-in useful types and types registered by UpdateTypeList
+	usefulTypesModule map[string]string // name of module defining useful type, missing for built-in types
+}
+
+func NewCompiler() *Compiler {
+	return &Compiler{
+		registry:          NewRegistry(),
+		usefulTypes:       builtinUsefulTypes(),
+		usefulTypesModule: map[string]string{},
+	}
+}
+
+// Modules returns all modules parsed by compiler
+func (c *Compiler) Modules() []ModuleDefinition {
+	return c.registry.Modules()
+}
+
+// Registry returns registry of modules parsed by compiler, imports of generated modules are resolved with it
+func (c *Compiler) Registry() *Registry {
+	return c.registry
+}
+
+func (c *Compiler) ParseString(str string) ([]ModuleDefinition, error) {
+	return c.ParseStream(strings.NewReader(str))
+}
+
+func (c *Compiler) ParseStream(reader io.Reader) ([]ModuleDefinition, error) {
+	return c.parse("", reader)
+}
+
+func (c *Compiler) ParseFile(name string) ([]ModuleDefinition, error) {
+	modules, err := parseFile(name)
+	c.registry.Add(modules...)
+	return modules, err
+}
+
+// parse parses modules and adds them to compiler, modules are added even if there are errors
+func (c *Compiler) parse(file string, reader io.Reader) ([]ModuleDefinition, error) {
+	modules, err := parseStream(file, reader)
+	c.registry.Add(modules...)
+	return modules, err
+}
+
+// UpdateTypeList makes types defined in modules resolvable from any module generated by compiler
+func (c *Compiler) UpdateTypeList(modules []ModuleDefinition) {
+	for _, module := range modules {
+		for _, assignment := range module.ModuleBody.AssignmentList {
+			name := assignment.Reference().Name()
+			find := module.ModuleBody.AssignmentList.GetType(name)
+			if find == nil {
+				// values are resolved by registry
+				continue
+			}
+			c.usefulTypes[name] = find.Type
+			c.usefulTypesModule[name] = module.ModuleIdentifier.Reference
+		}
+	}
+}
+
+// NewCodeGenerator returns generator resolving type references against types known to compiler
+func (c *Compiler) NewCodeGenerator(params GenParams) CodeGenerator {
+	switch params.Type {
+	case GEN_DECLARATIONS:
+		return &declCodeGen{Params: params, compiler: c}
+	default:
+		return nil
+	}
+}
+
+func (c *Compiler) lookupUsefulType(name string) (Type, string) {
+	return c.usefulTypes[name], c.usefulTypesModule[name]
+}
@@ -0,0 +1,56 @@
+package asn1go
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCompilersAreIndependent(t *testing.T) {
+	generate := func(defs string) (string, error) {
+		compiler := NewCompiler()
+		modules, err := compiler.ParseString("Defs DEFINITIONS ::= BEGIN Foo ::= " + defs + " END\n" +
+			"Main DEFINITIONS ::= BEGIN IMPORTS Foo FROM Defs; Bar ::= SEQUENCE { foo Foo } END\n")
+		if err != nil {
+			return "", err
+		}
+		if len(compiler.Modules()) != 2 {
+			t.Errorf("Expected 2 modules in compiler, got %v", len(compiler.Modules()))
+		}
+		compiler.UpdateTypeList(modules)
+		buf := &bytes.Buffer{}
+		err = compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], buf)
+		return buf.String(), err
+	}
+	var wg sync.WaitGroup
+	// BIT STRING is generated as asn1.BitString, so the field shows which Foo generator resolved
+	cases := map[string]string{
+		"INTEGER":    "Foo Defs.Foo ",
+		"BIT STRING": "Foo asn1.BitString ",
+	}
+	for defs, exp := range cases {
+		defs, exp := defs, exp
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				got, err := generate(defs)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				if !strings.Contains(got, exp) {
+					t.Errorf("Expected field %q, got:\n%v", exp, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// types registered in one compiler are not visible to others
+	if foo, _ := NewCompiler().lookupUsefulType("Foo"); foo != nil {
+		t.Errorf("Expected Foo to be unknown to a new compiler, got %v", foo)
+	}
+}
@@ -0,0 +1,42 @@
+package asn1go
+
+import (
+	"fmt"
+	"sort"
+)
+
+// sourceError is an error found at position in ASN.1 source
+type sourceError interface {
+	error
+	position() Position
+}
+
+// errorList is a list of errors found in ASN.1 source which are reported at once, ErrorList, ImportErrorList and
+// CheckErrorList are errorList of their error types
+type errorList[E sourceError] []E
+
+func (l errorList[E]) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	case 2:
+		return fmt.Sprintf("%v (and 1 more error)", l[0])
+	}
+	return fmt.Sprintf("%v (and %d more errors)", l[0], len(l)-1)
+}
+
+// sort sorts errors by position in source, errors at the same position keep their order
+func (l errorList[E]) sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].position(), l[j].position()
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
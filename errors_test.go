@@ -0,0 +1,29 @@
+package asn1go
+
+import "testing"
+
+func TestErrorList(t *testing.T) {
+	at := func(line, column int) *CheckError {
+		return &CheckError{Pos: Position{File: "a.asn1", Line: line, Column: column}, Module: "M", Message: "bad"}
+	}
+	errs := errorList[*CheckError]{at(3, 1), at(1, 5), at(1, 2)}
+	errs.sort()
+	for i, exp := range []Position{at(1, 2).Pos, at(1, 5).Pos, at(3, 1).Pos} {
+		if errs[i].Pos != exp {
+			t.Errorf("Expected error %v at %v, got %v", i, exp, errs[i].Pos)
+		}
+	}
+	for _, tc := range []struct {
+		errs     CheckErrorList
+		expected string
+	}{
+		{nil, "no errors"},
+		{CheckErrorList{at(1, 2)}, "a.asn1:1:2: M: bad"},
+		{CheckErrorList{at(1, 2), at(3, 1)}, "a.asn1:1:2: M: bad (and 1 more error)"},
+		{CheckErrorList{at(1, 2), at(3, 1), at(4, 1)}, "a.asn1:1:2: M: bad (and 2 more errors)"},
+	} {
+		if got := tc.errs.Error(); got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, got)
+		}
+	}
+}
@@ -74,12 +74,12 @@ func TestKdcReq(t *testing.T) {
 		Padata: []PA_DATA{
 			{149, []byte{}},
 		},
-		ReqBody: &KDC_REQ_BODY{
+		ReqBody: KDC_REQ_BODY{
 			KdcOptions: asn1.BitString{[]byte{0x00, 0x00, 0x00, 0x10}, 32},
-			Cname:      &PrincipalName{1, []KerberosString{"chemikadze"}},
+			Cname:      PrincipalName{1, []KerberosString{"chemikadze"}},
 			Realm:      "ATHENA.MIT.EDU",
-			Sname:      &PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
-			Till:       utils.ParseWiresharkTime("2018-01-03 06:04:07"),
+			Sname:      PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
+			Till:       *utils.ParseWiresharkTime("2018-01-03 06:04:07"),
 			Nonce:      1679932297,
 			Etype:      []Int32{18, 17, 16, 23, 25, 26},
 		},
@@ -106,14 +106,14 @@ func TestKrbError(t *testing.T) {
 	expected := KRB_ERROR{
 		Pvno:      5,
 		MsgType:   30,
-		Ctime:     utils.ParseWiresharkTime("2023-03-27 15:51:37"),
-		Stime:     utils.ParseWiresharkTime("2018-01-02 06:04:07"),
+		Ctime:     *utils.ParseWiresharkTime("2023-03-27 15:51:37"),
+		Stime:     *utils.ParseWiresharkTime("2018-01-02 06:04:07"),
 		Susec:     297128,
 		ErrorCode: 6,
 		Crealm:    "ATHENA.MIT.EDU",
-		Cname:     &PrincipalName{1, []KerberosString{"chemikadze"}},
+		Cname:     PrincipalName{1, []KerberosString{"chemikadze"}},
 		Realm:     "ATHENA.MIT.EDU",
-		Sname:     &PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
+		Sname:     PrincipalName{2, []KerberosString{"krbtgt", "ATHENA.MIT.EDU"}},
 		EText:     "CLIENT_NOT_FOUND",
 	}
 
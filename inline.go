@@ -0,0 +1,100 @@
+package asn1go
+
+import (
+	"strconv"
+)
+
+// inlineScope is Go type, which types defined inline are named after
+type inlineScope struct {
+	module string // module defining the type, empty for module being generated
+	name   string // Go name of the type
+}
+
+// inlineTypeNames names SEQUENCE, SET, CHOICE, INTEGER with named numbers and ENUMERATED types defined inline in
+// types and values of module. Names are keyed by Go name of the type they are defined in and identifier of their
+// component or alternative joined with dot, elements of SEQUENCE OF and SET OF defined directly in a type or value
+// are identified as item. Name joins Go names of the enclosing type and of the identifier with underscore, for
+// example Message_Body for body of Message, and gets number suffix if other type has it already. Types are named
+// in order of assignments before values, so names of types don't depend on values and types of other modules can
+// be referenced by their names.
+func inlineTypeNames(registry *Registry, module *ModuleDefinition) map[string]string {
+	names := map[string]string{}
+	taken := map[string]bool{}
+	for _, assignment := range module.ModuleBody.AssignmentList {
+		if a, ok := assignment.(TypeAssignment); ok {
+			taken[goifyName(a.TypeReference.Name())] = true
+		}
+	}
+	var nameComponents func(scope string, t Type)
+	nameInline := func(scope, identifier string, t Type) {
+		inline := inlineType(t)
+		if inline == nil {
+			return
+		}
+		name := scope + "_" + goifyName(identifier)
+		for i := 2; taken[name]; i++ {
+			name = scope + "_" + goifyName(identifier) + strconv.Itoa(i)
+		}
+		taken[name] = true
+		names[scope+"."+identifier] = name
+		nameComponents(name, inline)
+	}
+	nameComponents = func(scope string, t Type) {
+		switch tt := withoutTags(t).(type) {
+		case SequenceType, SetType:
+			expanded, err := registry.ExpandComponentsOf(module, tt)
+			if err != nil {
+				expanded = tt
+			}
+			sequence, ok := expanded.(SequenceType)
+			if !ok {
+				sequence = SequenceType(expanded.(SetType))
+			}
+			for _, component := range sequence.AllComponents() {
+				if named, ok := component.(NamedComponentType); ok {
+					nameInline(scope, named.NamedType.Identifier.Name(), named.NamedType.Type)
+				}
+			}
+		case ChoiceType:
+			for _, alternative := range tt.Alternatives() {
+				nameInline(scope, alternative.Identifier.Name(), alternative.Type)
+			}
+		case SequenceOfType:
+			nameInline(scope, inlineItem, tt.Type)
+		case SetOfType:
+			nameInline(scope, inlineItem, tt.Type)
+		}
+	}
+	for _, assignment := range module.ModuleBody.AssignmentList {
+		if a, ok := assignment.(TypeAssignment); ok {
+			nameComponents(goifyName(a.TypeReference.Name()), a.Type)
+		}
+	}
+	for _, assignment := range module.ModuleBody.AssignmentList {
+		if a, ok := assignment.(ValueAssignment); ok {
+			nameComponents(valueName(module, a.ValueReference), a.Type)
+		}
+	}
+	return names
+}
+
+// inlineItem identifies elements of SEQUENCE OF and SET OF, which aren't components or alternatives
+const inlineItem = "item"
+
+// inlineType returns SEQUENCE, SET, CHOICE, INTEGER with named numbers or ENUMERATED t, possibly tagged,
+// constrained or being element of SEQUENCE OF or SET OF, nil if t is other type
+func inlineType(t Type) Type {
+	switch tt := withoutTags(t).(type) {
+	case SequenceType, SetType, ChoiceType, EnumeratedType:
+		return tt
+	case IntegerType:
+		if len(tt.NamedNumberList) > 0 {
+			return tt
+		}
+	case SequenceOfType:
+		return inlineType(tt.Type)
+	case SetOfType:
+		return inlineType(tt.Type)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -98,22 +99,182 @@ var (
 
 type MyLexer struct {
 	bufReader     *bufio.Reader
-	err           error
+	errors        ErrorList // all errors in order they were found
 	result        []ModuleDefinition
 	lastWasNumber bool
+
+	pos          Position // position of the next rune
+	prevPos      Position // position of the last read rune, restored by unreadRune
+	tokenStart   Position // position of the first rune of current token
+	tokenText    []rune   // runes of current token read so far
+	lastTokenEnd Position // end of the last token returned by Lex
+	prevTokenEnd Position // end of the token returned by Lex before the last one
+
+	source    *bytes.Buffer // source read so far, used to render error snippets
+	parser    *parserState  // mirror of parser state, used to list expected tokens on syntax error
+	lookahead int           // internal code of the last token returned by Lex, -1 if none
+
+	tokens     []lexedToken // recently returned tokens followed by tokens read ahead on resync
+	next       int          // index in tokens of the token Lex returns next
+	seq        int          // number of tokens read from input
+	lastResync int          // seq of the token parsing was last resumed from
+
+	comments     []lexedComment // all comments in order they appear in source
+	pending      int            // index of the first comment not followed by a token yet
+	lastRead     lexedToken     // the last token read from input
+	lastReadPrev Position       // end of the token read before the last one
+}
+
+// lexedToken is a token together with its semantic value, kept to be returned again after resync
+type lexedToken struct {
+	code int
+	lval yySymType
+	text string
+	seq  int
+}
+
+// lexedComment is a comment together with tokens around it, used to attach comments to AST nodes
+type lexedComment struct {
+	Comment
+	trailing  bool     // comment starts on the same line as the previous token ends
+	prevEnd   Position // end of the token before the comment
+	prevComma bool     // the token before the comment is a comma
+	commaEnd  Position // end of the token before the comma if prevComma is set
+	next      Position // start of the token after the comment
+}
+
+// maxHistory is number of returned tokens kept for resync to step back to the start of assignment
+const maxHistory = 32
+
+func NewLexer(file string, reader io.Reader) *MyLexer {
+	source := &bytes.Buffer{}
+	return &MyLexer{
+		bufReader: bufio.NewReader(io.TeeReader(reader, source)),
+		pos:       Position{File: file, Line: 1, Column: 1},
+		source:    source,
+		parser:    newParserState(),
+		lookahead: -1,
+	}
 }
 
 func (lex *MyLexer) Lex(lval *yySymType) int {
+	// parser asks for the next token only after the previous one is consumed
+	if lex.lookahead >= 0 {
+		lex.parser.feed(lex.lookahead)
+	}
+	if lex.next == len(lex.tokens) {
+		lex.tokens = append(lex.tokens, lex.readToken())
+	}
+	token := lex.tokens[lex.next]
+	lex.next++
+	if lex.next > 2*maxHistory {
+		n := lex.next - maxHistory
+		lex.tokens = append(lex.tokens[:0], lex.tokens[n:]...)
+		lex.next -= n
+	}
+	*lval = token.lval
+	lex.lookahead = internalToken(token.code)
+	lex.prevTokenEnd = lex.lastTokenEnd
+	lex.lastTokenEnd = token.lval.span.End
+	return token.code
+}
+
+// readToken reads next token from input
+func (lex *MyLexer) readToken() lexedToken {
+	var lval yySymType
+	code := lex.lexToken(&lval)
+	lval.span = Span{Start: lex.tokenStart, End: lex.pos}
+	lex.seq++
+	for ; lex.pending < len(lex.comments); lex.pending++ {
+		lex.comments[lex.pending].next = lex.tokenStart
+	}
+	lex.lastReadPrev = lex.lastRead.lval.span.End
+	lex.lastRead = lexedToken{code: code, lval: lval, text: string(lex.tokenText), seq: lex.seq}
+	return lex.lastRead
+}
+
+// resync is called by parser on syntax error in assignment list. It moves input to the start of the closest
+// assignment or to END of the module, so tokens already consumed by parser may be returned again.
+// Returns false if there is no such point in the rest of input.
+func (lex *MyLexer) resync() bool {
+	start := lex.next - 1
+	if start > 0 && lex.tokens[start-1].code == ASSIGNMENT {
+		// error right after ::= may be caused by junk before the assignment
+		start--
+	}
+	for i := start; ; i++ {
+		if i == len(lex.tokens) {
+			lex.tokens = append(lex.tokens, lex.readToken())
+		}
+		switch lex.tokens[i].code {
+		case END:
+			return lex.resumeAt(i)
+		case 0, -1:
+			return false
+		case ASSIGNMENT:
+			// resuming from the same assignment again won't make any progress
+			if start := lex.assignmentStart(i); start >= 0 && lex.tokens[start].seq > lex.lastResync {
+				return lex.resumeAt(start)
+			}
+		}
+	}
+}
+
+func (lex *MyLexer) resumeAt(i int) bool {
+	if lex.tokens[i].seq > lex.lastResync {
+		lex.lastResync = lex.tokens[i].seq
+	}
+	lex.next = i
+	lex.lookahead = -1
+	lex.parser.resynced()
+	return true
+}
+
+// assignmentStart returns index of the first token of assignment with ::= at index i, or -1 if it is not found
+func (lex *MyLexer) assignmentStart(i int) int {
+	j := i - 1
+	if j < 0 {
+		return -1
+	}
+	line := func(k int) int { return lex.tokens[k].lval.span.Start.Line }
+	if lex.tokens[j].code == TYPEORMODULEREFERENCE {
+		// value of defined type, e.g. myValue MyType ::=
+		if j > 0 && lex.tokens[j-1].code == VALUEIDENTIFIER && line(j-1) == line(j) {
+			return j - 1
+		}
+		return j
+	}
+	// value of builtin type, e.g. myOid OBJECT IDENTIFIER ::=
+	for k := j; k >= 0 && line(k) == line(i); k-- {
+		if lex.tokens[k].code == VALUEIDENTIFIER {
+			return k
+		}
+	}
+	return -1
+}
+
+// spanFrom returns span from start up to the end of the last token consumed by parser.
+// If parser already holds lookahead token, it is excluded from the span.
+func (lex *MyLexer) spanFrom(start Position, hasLookahead bool) Span {
+	if hasLookahead {
+		return Span{Start: start, End: lex.prevTokenEnd}
+	}
+	return Span{Start: start, End: lex.lastTokenEnd}
+}
+
+func (lex *MyLexer) lexToken(lval *yySymType) int {
 
 	lastWasNumber := lex.lastWasNumber
 	lex.lastWasNumber = false
 	for {
+		lex.tokenStart = lex.pos
+		lex.tokenText = lex.tokenText[:0]
 		r, _, err := lex.readRune()
 		if err == io.EOF {
 			return 0
 		}
 		if err != nil {
-			lex.Error(fmt.Sprintf("Failed to read: %v", err.Error()))
+			lex.lexError(fmt.Sprintf("Failed to read: %v", err.Error()))
 			return -1
 		}
 
@@ -125,6 +286,7 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 			r := lex.peekRune()
 			if r == '-' {
 				lex.skipLineComment()
+				lex.recordComment()
 				lastWasNumber = false
 				continue
 			} else if isNewline(r) {
@@ -133,6 +295,7 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 			}
 		} else if r == '/' && lex.peekRune() == '*' {
 			lex.skipBlockComment()
+			lex.recordComment()
 			lastWasNumber = false
 			continue
 		}
@@ -145,7 +308,7 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 			lex.unreadRune()
 			content, err := lex.consumeWord()
 			if err != nil {
-				lex.Error(err.Error())
+				lex.lexError(err.Error())
 				return -1
 			}
 			if unicode.IsUpper(r) {
@@ -154,7 +317,6 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 					return code
 				} else {
 					lval.name = content
-					fmt.Println("TYPEORMODULEREFERENCE:" + lval.name)
 					return TYPEORMODULEREFERENCE
 				}
 			} else {
@@ -165,6 +327,10 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 			lex.unreadRune()
 			lex.lastWasNumber = true
 			return lex.consumeNumber(lval)
+		} else if r == '"' {
+			return lex.consumeCString(lval)
+		} else if r == '\'' {
+			return lex.consumeBHString(lval)
 		} else if r == ':' && lex.peekRunes(2) == ":=" {
 			lex.discard(2)
 			return ASSIGNMENT
@@ -180,9 +346,11 @@ func (lex *MyLexer) Lex(lval *yySymType) int {
 		} else if r == ']' && lex.peekRune() == ']' {
 			lex.discard(1)
 			return RIGHT_VERSION_BRACKETS
-		} else {
-			return lex.consumeSingleSymbol(r)
+		} else if code := lex.consumeSingleSymbol(r); code != -1 {
+			return code
 		}
+		// unexpected character is reported and skipped
+		lastWasNumber = false
 	}
 }
 
@@ -231,7 +399,7 @@ func (lex *MyLexer) consumeSingleSymbol(r rune) int {
 	case '^':
 		return CARET
 	default:
-		lex.Error(fmt.Sprintf("Unexpected character: %c", r))
+		lex.lexError(fmt.Sprintf("Unexpected character: %c", r))
 		return -1
 	}
 }
@@ -241,11 +409,25 @@ func (lex *MyLexer) unreadRune() error {
 	if r != nil {
 		panic(r.Error())
 	}
+	lex.pos = lex.prevPos
+	if len(lex.tokenText) > 0 {
+		lex.tokenText = lex.tokenText[:len(lex.tokenText)-1]
+	}
 	return r
 }
 
 func (lex *MyLexer) readRune() (rune, int, error) {
 	r, n, err := lex.bufReader.ReadRune()
+	if err == nil {
+		lex.prevPos = lex.pos
+		if r == '\n' {
+			lex.pos.Line += 1
+			lex.pos.Column = 1
+		} else {
+			lex.pos.Column += 1
+		}
+		lex.tokenText = append(lex.tokenText, r)
+	}
 	return r, n, err
 }
 
@@ -254,8 +436,11 @@ func (lex *MyLexer) peekRune() rune {
 	return r
 }
 
+// discard skips n runes
 func (lex *MyLexer) discard(n int) {
-	lex.bufReader.Discard(n)
+	for i := 0; i < n; i++ {
+		lex.readRune()
+	}
 }
 
 func (lex *MyLexer) peekRunes(n int) string {
@@ -288,6 +473,32 @@ func (lex *MyLexer) peekRuneE() (rune, error) {
 	return r, err
 }
 
+// recordComment saves comment that was just skipped, current token text holds the comment with delimiters
+func (lex *MyLexer) recordComment() {
+	text := string(lex.tokenText)
+	end := lex.pos
+	if strings.HasPrefix(text, "--") {
+		text = strings.TrimPrefix(text, "--")
+		if strings.HasSuffix(text, "\n") {
+			// newline terminating comment is not a part of it
+			text = strings.TrimSuffix(text, "\n")
+			end = lex.prevPos
+		} else {
+			text = strings.TrimSuffix(text, "--")
+		}
+	} else {
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	}
+	prev := lex.lastRead.lval.span.End
+	lex.comments = append(lex.comments, lexedComment{
+		Comment:   Comment{Text: strings.TrimSpace(text), Span: Span{Start: lex.tokenStart, End: end}},
+		trailing:  lex.seq > 0 && prev.Line == lex.tokenStart.Line,
+		prevEnd:   prev,
+		prevComma: lex.seq > 0 && lex.lastRead.code == COMMA,
+		commaEnd:  lex.lastReadPrev,
+	})
+}
+
 func (lex *MyLexer) skipLineComment() {
 	lastIsHyphen := false
 	for {
@@ -334,7 +545,7 @@ func (lex *MyLexer) skipBlockComment() {
 }
 
 func (lex *MyLexer) consumeWord() (string, error) {
-	r, _, _ := lex.bufReader.ReadRune()
+	r, _, _ := lex.readRune()
 	acc := bytes.NewBufferString("")
 	acc.WriteRune(r)
 	lastR := r
@@ -366,9 +577,9 @@ func (lex *MyLexer) consumeWord() (string, error) {
 }
 
 func (lex *MyLexer) consumeNumber(lval *yySymType) int {
-	r, _, err := lex.bufReader.ReadRune()
+	r, _, err := lex.readRune()
 	if err != nil {
-		lex.Error(err.Error())
+		lex.lexError(err.Error())
 		return -1
 	}
 	acc := bytes.NewBufferString("")
@@ -382,7 +593,7 @@ func (lex *MyLexer) consumeNumber(lval *yySymType) int {
 			repr := acc.String()
 			i, err := strconv.Atoi(repr)
 			if err != nil {
-				lex.Error(fmt.Sprintf("Failed to parse number: %v", err.Error()))
+				lex.lexError(fmt.Sprintf("Failed to parse number: %v", err.Error()))
 				return -1
 			}
 			lval.numberRepr = repr
@@ -390,15 +601,149 @@ func (lex *MyLexer) consumeNumber(lval *yySymType) int {
 			return NUMBER
 		}
 		if err != nil {
-			lex.Error(fmt.Sprintf("Failed to read: %v", err.Error()))
+			lex.lexError(fmt.Sprintf("Failed to read: %v", err.Error()))
 			return -1
 		}
 		acc.WriteRune(r)
 	}
 }
 
+// consumeCString reads cstring lexem (X.680 12.14), opening quotation mark is expected to be consumed already
+func (lex *MyLexer) consumeCString(lval *yySymType) int {
+	acc := bytes.NewBufferString("")
+	for {
+		r, _, err := lex.readRune()
+		if err == io.EOF {
+			lex.lexError(fmt.Sprintf("Unterminated character string, got \"%v", acc.String()))
+			return -1
+		}
+		if err != nil {
+			lex.lexError(fmt.Sprintf("Failed to read: %v", err.Error()))
+			return -1
+		}
+		if r == '"' {
+			if lex.peekRune() == '"' { // "" stands for single quotation mark
+				lex.discard(1)
+				acc.WriteRune(r)
+				continue
+			}
+			lval.cstring = acc.String()
+			return CSTRING
+		}
+		if isNewline(r) {
+			// spacing around line breaks is not part of the string
+			trimmed := strings.TrimRightFunc(acc.String(), isWhitespace)
+			acc.Reset()
+			acc.WriteString(trimmed)
+			lex.skipWhitespace()
+			continue
+		}
+		acc.WriteRune(r)
+	}
+}
+
+// consumeBHString reads bstring or hstring lexem (X.680 12.10, 12.12), opening apostrophe is expected to be consumed already
+func (lex *MyLexer) consumeBHString(lval *yySymType) int {
+	acc := bytes.NewBufferString("")
+	for {
+		r, _, err := lex.readRune()
+		if err == io.EOF {
+			lex.lexError(fmt.Sprintf("Unterminated bstring or hstring, got '%v", acc.String()))
+			return -1
+		}
+		if err != nil {
+			lex.lexError(fmt.Sprintf("Failed to read: %v", err.Error()))
+			return -1
+		}
+		if r == '\'' {
+			break
+		}
+		if isWhitespace(r) { // whitespace and line breaks are ignored
+			continue
+		}
+		acc.WriteRune(r)
+	}
+	digits := acc.String()
+	r, _, err := lex.readRune()
+	if err != nil && err != io.EOF {
+		lex.lexError(fmt.Sprintf("Failed to read: %v", err.Error()))
+		return -1
+	}
+	switch {
+	case err == nil && r == 'B':
+		value, err := parseBString(digits)
+		if err != nil {
+			lex.lexError(err.Error())
+		}
+		lval.bstring = value
+		return BSTRING
+	case err == nil && r == 'H':
+		value, err := parseHString(digits)
+		if err != nil {
+			lex.lexError(err.Error())
+		}
+		lval.hstring = value
+		return HSTRING
+	default:
+		lex.lexError(fmt.Sprintf("Expected B or H after '%v'", digits))
+		return -1
+	}
+}
+
+func (lex *MyLexer) skipWhitespace() {
+	for {
+		r, err := lex.peekRuneE()
+		if err != nil || !isWhitespace(r) {
+			return
+		}
+		lex.readRune()
+	}
+}
+
+// Error is called by parser on syntax error, the offending token is the last one returned by Lex
 func (lex *MyLexer) Error(e string) {
-	lex.err = errors.New(e)
+	token := lex.tokens[lex.next-1]
+	expected := lex.parser.expected()
+	lex.parser.reject(lex.lookahead)
+	if token.code < 0 {
+		// lexer failed and error is already reported
+		return
+	}
+	unexpected := "end of input"
+	if token.code != 0 {
+		unexpected = fmt.Sprintf("%q", token.text)
+	}
+	lex.recordError(&ParseError{
+		Pos:      token.lval.span.Start,
+		Token:    token.text,
+		Message:  "syntax error: unexpected " + unexpected,
+		Expected: expected,
+	})
+}
+
+// lexError is called on lexical error in current token
+func (lex *MyLexer) lexError(e string) {
+	lex.recordError(&ParseError{Pos: lex.tokenStart, Token: string(lex.tokenText), Message: e})
+}
+
+// recordError adds error to the list, error at the same position as the previous one is dropped,
+// as it is usually caused by the previous one
+func (lex *MyLexer) recordError(err *ParseError) {
+	if n := len(lex.errors); n > 0 && lex.errors[n-1].Pos == err.Pos {
+		return
+	}
+	lex.errors = append(lex.errors, err)
+}
+
+// parseErrors returns recorded errors sorted by position and with source snippets
+func (lex *MyLexer) parseErrors() ErrorList {
+	// make sure the rest of the last offending line is read
+	lex.bufReader.Peek(lex.bufReader.Size())
+	for _, err := range lex.errors {
+		err.Snippet = sourceSnippet(lex.source.Bytes(), err.Pos)
+	}
+	lex.errors.Sort()
+	return lex.errors
 }
 
 func isWhitespace(r rune) bool {
@@ -448,3 +793,37 @@ func isNewline(r rune) bool {
 func isIdentifierChar(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-'
 }
+
+func parseBString(digits string) (BitString, error) {
+	value := BitString{Bytes: make([]byte, (len(digits)+7)/8), BitLength: len(digits)}
+	for i, r := range []byte(digits) {
+		switch r {
+		case '0':
+		case '1':
+			value.Bytes[i/8] |= 1 << (7 - uint(i%8))
+		default:
+			return BitString{}, errors.New(fmt.Sprintf("Expected binary digit in bstring, got '%c' in '%v'B", r, digits))
+		}
+	}
+	return value, nil
+}
+
+func parseHString(digits string) (OctetString, error) {
+	value := make(OctetString, (len(digits)+1)/2)
+	for i, r := range []byte(digits) {
+		var nibble byte
+		switch {
+		case '0' <= r && r <= '9':
+			nibble = r - '0'
+		case 'A' <= r && r <= 'F':
+			nibble = r - 'A' + 10
+		default:
+			return nil, errors.New(fmt.Sprintf("Expected hexadecimal digit in hstring, got '%c' in '%v'H", r, digits))
+		}
+		if i%2 == 0 {
+			nibble <<= 4
+		}
+		value[i/2] |= nibble
+	}
+	return value, nil
+}
@@ -1,14 +1,13 @@
 package asn1go
 
 import (
-	"bufio"
+	"fmt"
 	"strings"
 	"testing"
 )
 
 func lexForString(str string) *MyLexer {
-	reader := bufio.NewReader(strings.NewReader(str))
-	return &MyLexer{bufReader: reader}
+	return NewLexer("", strings.NewReader(str))
 }
 
 func testLexemType(t *testing.T, input string, expectedType int) {
@@ -21,8 +20,8 @@ func testLexem(t *testing.T, getter func(*yySymType) string, input string, expec
 	lex := lexForString(str)
 	symType := &yySymType{}
 	gotType := lex.Lex(symType)
-	if lex.err != nil {
-		t.Errorf("At %s: Expected nil error, got %v", input, lex.err)
+	if len(lex.errors) != 0 {
+		t.Errorf("At %s: Expected no errors, got %v", input, lex.errors)
 	}
 	if gotType != expectedType {
 		t.Errorf("At %s: Expected %v token, got %v", input, expectedType, gotType)
@@ -38,8 +37,8 @@ func testNumber(t *testing.T, input string, expectedValue Number) {
 	lex := lexForString(str)
 	symType := &yySymType{}
 	gotType := lex.Lex(symType)
-	if lex.err != nil {
-		t.Errorf("Expected nil error, got %v", lex.err)
+	if len(lex.errors) != 0 {
+		t.Errorf("Expected no errors, got %v", lex.errors)
 	}
 	expectedType := NUMBER
 	if gotType != expectedType {
@@ -55,8 +54,8 @@ func testError(t *testing.T, input string, expectedErr string) {
 	lex := lexForString(str)
 	symType := &yySymType{}
 	lex.Lex(symType)
-	if lex.err == nil || lex.err.Error() != expectedErr {
-		t.Errorf("Expected '%v' error, got '%v'", expectedErr, lex.err)
+	if len(lex.errors) == 0 || lex.errors[0].Message != expectedErr {
+		t.Errorf("Expected '%v' error, got '%v'", expectedErr, lex.errors)
 	}
 }
 
@@ -92,8 +91,8 @@ func TestNoSpacing(t *testing.T) {
 	if r := lex.Lex(symType); r != VALUEIDENTIFIER {
 		t.Errorf("Expected identifier (%v), got %v", VALUEIDENTIFIER, r)
 	}
-	if lex.err != nil {
-		t.Errorf("Got error: %v", lex.err)
+	if len(lex.errors) != 0 {
+		t.Errorf("Got errors: %v", lex.errors)
 	}
 	if symType.name != "myIdentifier" {
 		t.Errorf("Expected myIdentifier, got '%v'", symType.name)
@@ -195,8 +194,6 @@ func TestSingleSymbolTokens(t *testing.T) {
 	testLexemType(t, "-", MINUS)
 	testLexemType(t, ":", COLON)
 	testLexemType(t, "=", EQUALS)
-	testLexemType(t, "\"", QUOTATION_MARK)
-	testLexemType(t, "'", APOSTROPHE)
 	//testLexemType(t, " ", SPACE)  // TODO
 	testLexemType(t, ";", SEMICOLON)
 	testLexemType(t, "@", AT)
@@ -205,6 +202,71 @@ func TestSingleSymbolTokens(t *testing.T) {
 	testLexemType(t, "^", CARET)
 }
 
+func ucs(t *yySymType) string {
+	return t.cstring
+}
+
+func ubs(t *yySymType) string {
+	return fmt.Sprintf("%x/%v", t.bstring.Bytes, t.bstring.BitLength)
+}
+
+func uhs(t *yySymType) string {
+	return fmt.Sprintf("%x", []byte(t.hstring))
+}
+
+func TestCString(t *testing.T) {
+	testLexem(t, ucs, `"ENG"`, CSTRING, "ENG")
+	testLexem(t, ucs, `""`, CSTRING, "")
+	testLexem(t, ucs, `"say ""hello"""`, CSTRING, `say "hello"`)
+	testLexem(t, ucs, `"-- not a comment"`, CSTRING, "-- not a comment")
+	testLexem(t, ucs, "\"first line   \n     second line\"", CSTRING, "first linesecond line")
+	testLexem(t, ucs, "\"first \r\n\n second\"", CSTRING, "firstsecond")
+	testError(t, `"unterminated`, `Unterminated character string, got "unterminated`)
+}
+
+func TestBString(t *testing.T) {
+	testLexem(t, ubs, "'0101'B", BSTRING, "50/4")
+	testLexem(t, ubs, "''B", BSTRING, "/0")
+	testLexem(t, ubs, "'1111 0000\n  1'B", BSTRING, "f080/9")
+	testError(t, "'0121'B", "Expected binary digit in bstring, got '2' in '0121'B")
+	testError(t, "'0101'", "Expected B or H after '0101'")
+	testError(t, "'0101", "Unterminated bstring or hstring, got '0101")
+}
+
+func TestHString(t *testing.T) {
+	testLexem(t, uhs, "'1F'H", HSTRING, "1f")
+	testLexem(t, uhs, "'ABC'H", HSTRING, "abc0")
+	testLexem(t, uhs, "'DEAD\n BEEF'H", HSTRING, "deadbeef")
+	testError(t, "'1f'H", "Expected hexadecimal digit in hstring, got 'f' in '1f'H")
+	testError(t, "'1F'X", "Expected B or H after '1F'")
+}
+
+func TestTokenPositions(t *testing.T) {
+	lex := NewLexer("test.asn1", strings.NewReader("Foo ::= -- comment\n  INTEGER (1..10)"))
+	expected := []Span{
+		{Start: Position{"test.asn1", 1, 1}, End: Position{"test.asn1", 1, 4}},
+		{Start: Position{"test.asn1", 1, 5}, End: Position{"test.asn1", 1, 8}},
+		{Start: Position{"test.asn1", 2, 3}, End: Position{"test.asn1", 2, 10}},
+		{Start: Position{"test.asn1", 2, 11}, End: Position{"test.asn1", 2, 12}},
+		{Start: Position{"test.asn1", 2, 12}, End: Position{"test.asn1", 2, 13}},
+		{Start: Position{"test.asn1", 2, 13}, End: Position{"test.asn1", 2, 15}},
+		{Start: Position{"test.asn1", 2, 15}, End: Position{"test.asn1", 2, 17}},
+		{Start: Position{"test.asn1", 2, 17}, End: Position{"test.asn1", 2, 18}},
+	}
+	for i, span := range expected {
+		symType := &yySymType{}
+		if tok := lex.Lex(symType); tok <= 0 {
+			t.Fatalf("Token %v: expected token, got %v (%v)", i, tok, lex.errors)
+		}
+		if symType.span != span {
+			t.Errorf("Token %v: expected span %v-%v, got %v-%v", i, span.Start, span.End, symType.span.Start, symType.span.End)
+		}
+	}
+	if tok := lex.Lex(&yySymType{}); tok != 0 {
+		t.Errorf("Expected EOF, got %v", tokName(tok))
+	}
+}
+
 func TestReservedWords(t *testing.T) {
 	testLexemType(t, "ABSENT", ABSENT)
 	testLexemType(t, "ENCODED", ENCODED)
@@ -358,8 +420,8 @@ func TestRealSyntax(t *testing.T) {
 		sym := &yySymType{}
 		for _, expectedLexem := range test.lexems {
 			if l := lexer.Lex(sym); l != expectedLexem {
-				if lexer.err != nil {
-					t.Fatalf("Input: %v\nErr should be nil, got: %v", test.input, lexer.err.Error())
+				if len(lexer.errors) != 0 {
+					t.Fatalf("Input: %v\nErrors should be empty, got: %v", test.input, lexer.errors)
 				}
 				t.Errorf("Input: %v\nExpected lexem %v got %v", test.input, tokName(expectedLexem), tokName(l))
 			}
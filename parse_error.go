@@ -0,0 +1,314 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseError describes lexical or syntax error found in ASN.1 source
+type ParseError struct {
+	Pos      Position // position of the offending token
+	Token    string   // text of the offending token, empty at the end of input
+	Message  string
+	Expected []string // tokens acceptable at Pos in ASN.1 spelling, empty for lexical errors
+	Snippet  string   // source line containing Pos and a line with caret pointing at Pos
+}
+
+func (e *ParseError) position() Position {
+	return e.Pos
+}
+
+func (e *ParseError) Error() string {
+	res := fmt.Sprintf("%v: %v", e.Pos, e.Message)
+	if len(e.Expected) == 0 {
+		return res
+	}
+	res += ", expecting "
+	for i, name := range e.Expected {
+		if i > 0 && i == len(e.Expected)-1 {
+			res += " or "
+		} else if i > 0 {
+			res += ", "
+		}
+		res += quoteSymbol(name)
+	}
+	return res
+}
+
+// ErrorList is a list of errors found in ASN.1 source, parser keeps going after syntax error
+// and reports all errors at once
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	return errorList[*ParseError](l).Error()
+}
+
+// Sort sorts errors by position in source
+func (l ErrorList) Sort() {
+	errorList[*ParseError](l).sort()
+}
+
+// quoteSymbol quotes punctuation so that it can't be confused with the rest of the message
+func quoteSymbol(name string) string {
+	if r := []rune(name)[0]; unicode.IsLetter(r) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// tokenSpellings maps goyacc token names that differ from ASN.1 spelling
+var tokenSpellings = map[string]string{
+	"$end":                   "end of input",
+	"TYPEORMODULEREFERENCE":  "typereference",
+	"VALUEIDENTIFIER":        "identifier",
+	"NUMBER":                 "number",
+	"BSTRING":                "bstring",
+	"XMLBSTRING":             "xmlbstring",
+	"HSTRING":                "hstring",
+	"XMLHSTRING":             "xmlhstring",
+	"CSTRING":                "cstring",
+	"XMLCSTRING":             "xmlcstring",
+	"ASSIGNMENT":             "::=",
+	"RANGE_SEPARATOR":        "..",
+	"ELLIPSIS":               "...",
+	"LEFT_VERSION_BRACKETS":  "[[",
+	"RIGHT_VERSION_BRACKETS": "]]",
+	"XML_END_TAG_START":      "</",
+	"XML_SINGLE_START_END":   "/>",
+	"XML_BOOLEAN_TRUE":       "<true/>",
+	"XML_BOOLEAN_FALSE":      "<false/>",
+	"XMLASN1TYPENAME":        "xmlasn1typename",
+	"EXPONENT":               "e",
+	"OPEN_CURLY":             "{",
+	"CLOSE_CURLY":            "}",
+	"LESS":                   "<",
+	"GREATER":                ">",
+	"COMMA":                  ",",
+	"DOT":                    ".",
+	"OPEN_ROUND":             "(",
+	"CLOSE_ROUND":            ")",
+	"OPEN_SQUARE":            "[",
+	"CLOSE_SQUARE":           "]",
+	"MINUS":                  "-",
+	"COLON":                  ":",
+	"EQUALS":                 "=",
+	"QUOTATION_MARK":         "\"",
+	"APOSTROPHE":             "'",
+	"SPACE":                  " ",
+	"SEMICOLON":              ";",
+	"AT":                     "@",
+	"PIPE":                   "|",
+	"EXCLAMATION":            "!",
+	"CARET":                  "^",
+}
+
+func init() {
+	// reserved words are spelled as in the source, e.g. RELATIVE-OID
+	for word, code := range RESERVED_WORDS {
+		tokenSpellings[yyTokname(internalToken(code))] = word
+	}
+}
+
+// tokenSpelling returns human readable ASN.1 spelling of internal parser token
+func tokenSpelling(token int) string {
+	name := yyTokname(token)
+	if spelling, ok := tokenSpellings[name]; ok {
+		return spelling
+	}
+	return name
+}
+
+// internalToken translates token code returned by lexer into parser internal numbering, same as yylex1 does
+func internalToken(char int) int {
+	token := 0
+	if char <= 0 {
+		token = int(yyTok1[0])
+	} else if char < len(yyTok1) {
+		token = int(yyTok1[char])
+	} else if char >= yyPrivate && char < yyPrivate+len(yyTok2) {
+		token = int(yyTok2[char-yyPrivate])
+	} else {
+		for i := 0; i < len(yyTok3); i += 2 {
+			if int(yyTok3[i+0]) == char {
+				token = int(yyTok3[i+1])
+				break
+			}
+		}
+	}
+	if token == 0 {
+		token = int(yyTok2[1]) /* unknown char */
+	}
+	return token
+}
+
+const (
+	actionError = iota
+	actionShift
+	actionReduce
+	actionAccept
+)
+
+// parserAction looks up goyacc tables for the action of parser in state on token,
+// arg is new state for shift and rule number for reduce
+func parserAction(state, token int) (action int, arg int) {
+	if n := int(yyPact[state]); n > yyFlag {
+		n += token
+		if n >= 0 && n < yyLast {
+			if next := int(yyAct[n]); int(yyChk[next]) == token {
+				return actionShift, next
+			}
+		}
+	}
+	n := int(yyDef[state])
+	if n == -2 {
+		xi := 0
+		for int(yyExca[xi]) != -1 || int(yyExca[xi+1]) != state {
+			xi += 2
+		}
+		for xi += 2; ; xi += 2 {
+			if n = int(yyExca[xi]); n < 0 || n == token {
+				break
+			}
+		}
+		if n = int(yyExca[xi+1]); n < 0 {
+			return actionAccept, 0
+		}
+	}
+	if n == 0 {
+		return actionError, 0
+	}
+	return actionReduce, n
+}
+
+// parserState mirrors state stack of goyacc parser.
+// Lexer feeds it with tokens consumed by parser, so on syntax error acceptable tokens
+// can be computed for the exact parser configuration and not only for the state after default reductions.
+type parserState struct {
+	stack   []int
+	errflag int // same as Errflag of parser: number of tokens to shift before new error is reported
+	failed  bool
+}
+
+func newParserState() *parserState {
+	return &parserState{stack: []int{0}}
+}
+
+// reduce pops rule's right-hand side and pushes state from goto table
+func reduce(stack []int, rule int) []int {
+	stack = stack[:len(stack)-int(yyR2[rule])]
+	nonterminal := int(yyR1[rule])
+	g := int(yyPgo[nonterminal])
+	state := int(yyAct[g])
+	if j := g + stack[len(stack)-1] + 1; j < yyLast {
+		if next := int(yyAct[j]); int(yyChk[next]) == -nonterminal {
+			state = next
+		}
+	}
+	return append(stack, state)
+}
+
+// run performs reductions for token until it is shifted, accepted or rejected
+func run(stack []int, token int) ([]int, int) {
+	for {
+		action, arg := parserAction(stack[len(stack)-1], token)
+		switch action {
+		case actionShift:
+			return append(stack, arg), action
+		case actionReduce:
+			stack = reduce(stack, arg)
+		default:
+			return stack, action
+		}
+	}
+}
+
+// feed advances state with token consumed by parser, including error recovery done by parser silently
+func (s *parserState) feed(token int) {
+	for !s.failed {
+		stack, action := run(s.stack, token)
+		s.stack = stack
+		if action != actionError {
+			if s.errflag > 0 {
+				s.errflag--
+			}
+			return
+		}
+		if s.errflag == 3 {
+			// parser discards token while recovering, aborting on end of input
+			s.failed = token == yyEofCode
+			return
+		}
+		s.recover()
+	}
+}
+
+// reject mirrors parser reporting syntax error on token, token will be fed again after recovery
+func (s *parserState) reject(token int) {
+	if s.failed {
+		return
+	}
+	s.stack, _ = run(s.stack, token)
+	s.recover()
+}
+
+// recover pops states until error token can be shifted, same as parser does
+func (s *parserState) recover() {
+	for len(s.stack) > 0 {
+		if n := int(yyPact[s.stack[len(s.stack)-1]]) + yyErrCode; n >= 0 && n < yyLast {
+			if next := int(yyAct[n]); int(yyChk[next]) == yyErrCode {
+				s.stack = append(s.stack, next)
+				s.errflag = 3
+				return
+			}
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+	s.failed = true
+}
+
+// resynced mirrors parser action after lexer resync: lookahead is dropped and errors are reported again
+func (s *parserState) resynced() {
+	s.errflag = 0
+}
+
+// expected returns spellings of all tokens parser would accept in current state
+func (s *parserState) expected() []string {
+	if s.failed {
+		return nil
+	}
+	var res []string
+	for token := 1; token <= len(yyToknames); token++ {
+		// quoted character literals in grammar are never returned by lexer
+		if name := yyTokname(token); token == yyErrCode || name == "$unk" || strings.HasPrefix(name, "\"") {
+			continue
+		}
+		stack := append(make([]int, 0, len(s.stack)+8), s.stack...)
+		if _, action := run(stack, token); action != actionError {
+			res = append(res, tokenSpelling(token))
+		}
+	}
+	return res
+}
+
+// sourceSnippet renders line number pos.Line of source followed by a caret pointing at pos.Column
+func sourceSnippet(source []byte, pos Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	lines := bytes.Split(source, []byte("\n"))
+	if pos.Line > len(lines) {
+		return ""
+	}
+	line := []rune(strings.TrimRight(string(lines[pos.Line-1]), "\r"))
+	caret := make([]rune, 0, pos.Column)
+	for i := 0; i < pos.Column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			caret = append(caret, '\t')
+		} else {
+			caret = append(caret, ' ')
+		}
+	}
+	return string(line) + "\n" + string(append(caret, '^'))
+}
@@ -1,38 +1,47 @@
 package asn1go
 
 import (
-	"bufio"
 	"io"
 	"math"
 	"os"
-	"strings"
 )
 
-func init() {
-	yyErrorVerbose = true
-}
-
+// ParseString parses modules from str with a new Compiler, see Compiler.ParseString
 func ParseString(str string) ([]ModuleDefinition, error) {
-	return ParseStream(strings.NewReader(str))
+	return NewCompiler().ParseString(str)
 }
 
+// ParseStream parses modules from reader with a new Compiler, see Compiler.ParseStream
 func ParseStream(reader io.Reader) ([]ModuleDefinition, error) {
-	lex := &MyLexer{}
-	lex.bufReader = bufio.NewReader(reader)
-	yyParse(lex)
-	if lex.err != nil {
-		return nil, lex.err
-	}
-	return lex.result, nil
+	return NewCompiler().ParseStream(reader)
 }
 
+// ParseFile parses modules from file with a new Compiler, see Compiler.ParseFile
 func ParseFile(name string) ([]ModuleDefinition, error) {
+	return NewCompiler().ParseFile(name)
+}
+
+// parseFile parses modules from file, using its name for error positions
+func parseFile(name string) ([]ModuleDefinition, error) {
 	file, err := os.Open(name)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	return ParseStream(file)
+	return parseStream(name, file)
+}
+
+// parseStream parses reader contents, using file name for error positions.
+// On errors modules parsed so far are returned together with ErrorList.
+func parseStream(file string, reader io.Reader) ([]ModuleDefinition, error) {
+	lex := NewLexer(file, reader)
+	yyParse(lex)
+	attachComments(lex.result, lex.comments)
+	applyAutomaticTags(lex.result)
+	if len(lex.errors) > 0 {
+		return lex.result, lex.parseErrors()
+	}
+	return lex.result, nil
 }
 
 func parseRealNumber(integer Number, fraction Number, exponent Number) Real {
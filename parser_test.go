@@ -3,6 +3,7 @@ package asn1go
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,6 +15,68 @@ func testNotFails(t *testing.T, str string) *ModuleDefinition {
 	return &def[0]
 }
 
+// withoutSpans returns deep copy of v with all Span fields zeroed, so parsed nodes can be compared to hand-written ones
+func withoutSpans(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return clearSpans(reflect.ValueOf(v)).Interface()
+}
+
+func clearSpans(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(clearSpans(v.Elem()))
+		return res
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type().Elem())
+		res.Elem().Set(clearSpans(v.Elem()))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(clearSpans(v.Index(i)))
+		}
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			res.SetMapIndex(key, clearSpans(v.MapIndex(key)))
+		}
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if field.Type == reflect.TypeOf(Span{}) {
+				res.Field(i).Set(reflect.Zero(field.Type))
+			} else {
+				res.Field(i).Set(clearSpans(v.Field(i)))
+			}
+		}
+		return res
+	default:
+		return v
+	}
+}
+
 func TestParseMinimalModule(t *testing.T) {
 	var r *ModuleDefinition
 	testNotFails(t, "MyModule DEFINITIONS ::= BEGIN END")
@@ -48,11 +111,29 @@ func TestParseImports(t *testing.T) {
 		},
 	}
 	r := testNotFails(t, content)
-	if es, rs := fmt.Sprintf("%+v", expected), fmt.Sprintf("%+v", r.ModuleBody.Imports); es != rs {
+	if es, rs := fmt.Sprintf("%+v", expected), fmt.Sprintf("%+v", withoutSpans(r.ModuleBody.Imports)); es != rs {
 		t.Errorf("Imports did not match:\n exp: %v\n got: %v", es, rs)
 	}
 }
 
+func TestParseExports(t *testing.T) {
+	for _, tc := range []struct {
+		exports  string
+		expected Exports
+	}{
+		{"", Exports{Mode: EXPORTS_ABSENT}},
+		{"EXPORTS ALL;", Exports{Mode: EXPORTS_ALL}},
+		{"EXPORTS;", Exports{Mode: EXPORTS_SYMBOLS, SymbolList: []Symbol{}}},
+		{"EXPORTS MyString, myValue;", Exports{Mode: EXPORTS_SYMBOLS, SymbolList: []Symbol{TypeReference("MyString"), ValueReference("myValue")}}},
+	} {
+		content := "Test DEFINITIONS ::= BEGIN\n" + tc.exports + "\nMyString ::= CHARACTER STRING\nEND\n"
+		r := testNotFails(t, content)
+		if es, rs := fmt.Sprintf("%#v", tc.expected), fmt.Sprintf("%#v", r.ModuleBody.Exports); es != rs {
+			t.Errorf("Exports of %q did not match:\n exp: %v\n got: %v", tc.exports, es, rs)
+		}
+	}
+}
+
 func TestDefinitiveIdentifier(t *testing.T) {
 	content := `
 	KerberosV5Spec2 {
@@ -120,7 +201,7 @@ func TestValueAssignmentOID(t *testing.T) {
 			t.Errorf("Expected assignment value to have same type as assignment itself, got %v != %v", v.Type(), krb.Type)
 		}
 		expected := []ObjectIdElement{
-			{Name: "name-form"},
+			{Name: "name-form", NameForm: true},
 			{Id: 42},
 			{Name: "name-and-number-form", Id: 77},
 		}
@@ -136,7 +217,69 @@ func TestValueAssignmentOID(t *testing.T) {
 	default:
 		t.Errorf("Expected ObjectIdentifierValue, got %t", v)
 	}
-	// TODO test DefinedValue
+}
+
+func TestDefinedValue(t *testing.T) {
+	content := `
+	Test DEFINITIONS ::= BEGIN
+		Tagged ::= [APPLICATION id-tag] INTEGER
+		ExternalTagged ::= [Other.id-tag] INTEGER
+		Flags ::= BIT STRING { first(0), second(id-second) }
+		id-base OBJECT IDENTIFIER ::= { Other.id-arc 5 }
+		id-sub OBJECT IDENTIFIER ::= { id-base 1 named(id-number) }
+		Bounded ::= INTEGER (0..Other.ub)
+		Msg ::= SEQUENCE { x INTEGER DEFAULT Other.x }
+		secret INTEGER ::= Other.secret
+	END
+	`
+	r := testNotFails(t, content)
+	assignments := r.ModuleBody.AssignmentList
+	if tag := assignments.GetType("Tagged").Type.(TaggedType).Tag.ClassNumber; tag != (DefinedValue{ValueReference: "id-tag"}) {
+		t.Errorf("Expected reference to id-tag, got %#v", tag)
+	}
+	if tag := assignments.GetType("ExternalTagged").Type.(TaggedType).Tag.ClassNumber; tag != (DefinedValue{ModuleReference: "Other", ValueReference: "id-tag"}) {
+		t.Errorf("Expected reference to Other.id-tag, got %#v", tag)
+	}
+	if bit := assignments.GetType("Flags").Type.(BitStringType).NamedBits[1].Index; bit != (DefinedValue{ValueReference: "id-second"}) {
+		t.Errorf("Expected reference to id-second, got %#v", bit)
+	}
+	base := assignments.GetValue("id-base").Value.(ObjectIdentifierValue)
+	if exp := (DefinedValue{ModuleReference: "Other", ValueReference: "id-arc"}); base[0] != exp {
+		t.Errorf("Expected OID to start with %v, got %#v", exp, base[0])
+	}
+	sub := assignments.GetValue("id-sub").Value.(ObjectIdentifierValue)
+	if exp := (ObjectIdElement{Name: "named", Reference: &DefinedValue{ValueReference: "id-number"}}); fmt.Sprintf("%v", sub[2]) != fmt.Sprintf("%v", exp) {
+		t.Errorf("Expected %v, got %v", exp, sub[2])
+	}
+	bounded := firstConstraintElements(assignments.GetType("Bounded").Type.(ConstraintedType)).(ValueRange)
+	if exp := (DefinedValue{ModuleReference: "Other", ValueReference: "ub"}); bounded.UpperEndpoint.Value != exp {
+		t.Errorf("Expected upper bound %v, got %#v", exp, bounded.UpperEndpoint.Value)
+	}
+	component := assignments.GetType("Msg").Type.(SequenceType).Components[0].(NamedComponentType)
+	if exp := (DefinedValue{ModuleReference: "Other", ValueReference: "x"}); component.Default != exp {
+		t.Errorf("Expected default %v, got %#v", exp, component.Default)
+	}
+	if exp, got := (DefinedValue{ModuleReference: "Other", ValueReference: "secret"}), assignments.GetValue("secret").Value; got != exp {
+		t.Errorf("Expected value %v, got %#v", exp, got)
+	}
+}
+
+func TestExternalTypeReference(t *testing.T) {
+	content := `
+	Test DEFINITIONS ::= BEGIN
+		Cert ::= PKIX1Explicit88.Certificate
+		Certs ::= SEQUENCE { first PKIX1Explicit88.Certificate, rest SEQUENCE OF Other.Cert }
+	END
+	`
+	r := testNotFails(t, content)
+	assignments := r.ModuleBody.AssignmentList
+	if ref := assignments.GetType("Cert").Type; ref != (ExternalTypeReference{ModuleReference: "PKIX1Explicit88", TypeReference: "Certificate"}) {
+		t.Errorf("Expected reference to PKIX1Explicit88.Certificate, got %#v", ref)
+	}
+	components := assignments.GetType("Certs").Type.(SequenceType).Components
+	if ref := components[1].(NamedComponentType).NamedType.Type.(SequenceOfType).Type; ref != (ExternalTypeReference{ModuleReference: "Other", TypeReference: "Cert"}) {
+		t.Errorf("Expected reference to Other.Cert, got %#v", ref)
+	}
 }
 
 func testReal(t *testing.T, input Real, expectedValue Real) {
@@ -270,7 +413,7 @@ func TestSequenceWithTagsAndSequenceOf(t *testing.T) {
 		}},
 		NamedComponentType{NamedType: NamedType{
 			Identifier: Identifier("name-string"),
-			Type:       TaggedType{Tag: Tag{ClassNumber: Number(1)}, Type: SequenceOfType{TypeReference("KerberosString")}},
+			Type:       TaggedType{Tag: Tag{ClassNumber: Number(1)}, Type: SequenceOfType{Type: TypeReference("KerberosString")}},
 		}},
 	}}
 	r := testNotFails(t, content)
@@ -290,11 +433,122 @@ func TestSequenceWithTagsAndSequenceOf(t *testing.T) {
 		}
 	}
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", parsedType); es != ps {
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", withoutSpans(parsedType)); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
 
+func TestIntegerNamedNumbers(t *testing.T) {
+	content := `
+	Test DEFINITIONS ::= BEGIN
+		Status ::= INTEGER { ok(0), failed(-1), limit(max), other(Defs.other) }
+	END
+	`
+	r := testNotFails(t, content)
+	expectedType := IntegerType{NamedNumberList: NamedNumberList{
+		{Name: "ok", Value: Number(0)},
+		{Name: "failed", Value: Number(-1)},
+		{Name: "limit", Value: DefinedValue{ValueReference: "max"}},
+		{Name: "other", Value: DefinedValue{ModuleReference: "Defs", ValueReference: "other"}},
+	}}
+	parsedType := withoutSpans(r.ModuleBody.AssignmentList.GetType("Status").Type)
+	if !reflect.DeepEqual(expectedType, parsedType) {
+		t.Errorf("Expected %+v, got %+v", expectedType, parsedType)
+	}
+}
+
+func TestEnumeratedType(t *testing.T) {
+	content := `
+	Test DEFINITIONS ::= BEGIN
+		Color ::= ENUMERATED { red, green(-5), blue(Defs.blue), ... ! 1, pink, cyan(9) }
+	END
+	`
+	r := testNotFails(t, content)
+	parsedType := withoutSpans(r.ModuleBody.AssignmentList.GetType("Color").Type).(EnumeratedType)
+	if parsedType.ExceptionSpec == nil || parsedType.ExceptionSpec.Value != Number(1) {
+		t.Errorf("Expected exception 1, got %+v", parsedType.ExceptionSpec)
+	}
+	parsedType.ExceptionSpec = nil
+	expectedType := EnumeratedType{
+		Enums: EnumeratedItemList{
+			{Name: "red"},
+			{Name: "green", Index: Number(-5)},
+			{Name: "blue", Index: DefinedValue{ModuleReference: "Defs", ValueReference: "blue"}},
+		},
+		Extensible:      true,
+		AdditionalEnums: EnumeratedItemList{{Name: "pink"}, {Name: "cyan", Index: Number(9)}},
+	}
+	if !reflect.DeepEqual(expectedType, parsedType) {
+		t.Errorf("Expected %+v, got %+v", expectedType, parsedType)
+	}
+}
+
+func TestSequenceExtensions(t *testing.T) {
+	content := `
+	Test DEFINITIONS ::= BEGIN
+		Root ::= SEQUENCE { a INTEGER, ..., b BOOLEAN, [[ 2: c INTEGER, d INTEGER OPTIONAL ]], [[ e NULL ]], ..., z INTEGER }
+		Empty ::= SEQUENCE { ... }
+		Leading ::= SET { ... ! 7, x INTEGER }
+		Trailing ::= SET { ..., ..., y INTEGER }
+		Marker ::= SEQUENCE { a INTEGER, ... ! Error : ext-error, ... }
+	END
+	`
+	r := testNotFails(t, content)
+	assignments := r.ModuleBody.AssignmentList
+	component := func(name string) NamedComponentType {
+		return NamedComponentType{NamedType: NamedType{Identifier: Identifier(name), Type: IntegerType{}}}
+	}
+	for _, tc := range []struct {
+		name      string
+		expected  interface{}
+		exception *ExceptionSpec
+	}{
+		{"Root", SequenceType{
+			Components: ComponentTypeList{component("a")},
+			Extensible: true,
+			ExtensionAdditions: []ExtensionAddition{
+				NamedComponentType{NamedType: NamedType{Identifier: "b", Type: BooleanType{}}},
+				ExtensionAdditionGroup{Version: 2, Components: ComponentTypeList{
+					component("c"),
+					NamedComponentType{NamedType: NamedType{Identifier: "d", Type: IntegerType{}}, IsOptional: true},
+				}},
+				ExtensionAdditionGroup{Components: ComponentTypeList{
+					NamedComponentType{NamedType: NamedType{Identifier: "e", Type: NullType{}}},
+				}},
+			},
+			TrailingRootComponents: ComponentTypeList{component("z")},
+		}, nil},
+		{"Empty", SequenceType{Extensible: true}, nil},
+		{"Leading", SetType{
+			Extensible:         true,
+			ExtensionAdditions: []ExtensionAddition{component("x")},
+		}, &ExceptionSpec{Value: Number(7)}},
+		{"Trailing", SetType{Extensible: true, TrailingRootComponents: ComponentTypeList{component("y")}}, nil},
+		{"Marker", SequenceType{
+			Components: ComponentTypeList{component("a")},
+			Extensible: true,
+		}, &ExceptionSpec{Type: TypeReference("Error"), Value: IdentifiedIntegerValue{Name: "ext-error"}}},
+	} {
+		// exception is compared separately, repr of pointer is an address
+		var exception *ExceptionSpec
+		switch x := withoutSpans(assignments.GetType(tc.name).Type).(type) {
+		case SequenceType:
+			exception, x.ExceptionSpec = x.ExceptionSpec, nil
+			if es, ps := fmt.Sprintf("%+v", tc.expected), fmt.Sprintf("%+v", x); es != ps {
+				t.Errorf("%v: repr mismatch:\n exp: %v\n got: %v", tc.name, es, ps)
+			}
+		case SetType:
+			exception, x.ExceptionSpec = x.ExceptionSpec, nil
+			if es, ps := fmt.Sprintf("%+v", tc.expected), fmt.Sprintf("%+v", x); es != ps {
+				t.Errorf("%v: repr mismatch:\n exp: %v\n got: %v", tc.name, es, ps)
+			}
+		}
+		if (exception == nil) != (tc.exception == nil) || exception != nil && fmt.Sprintf("%+v", *exception) != fmt.Sprintf("%+v", *tc.exception) {
+			t.Errorf("%v: expected exception %+v, got %+v", tc.name, tc.exception, exception)
+		}
+	}
+}
+
 func TestBitStringWithSizeConstraint(t *testing.T) {
 	content := `
 	KerberosV5Spec2 DEFINITIONS ::= BEGIN
@@ -322,7 +576,7 @@ func TestBitStringWithSizeConstraint(t *testing.T) {
 	}
 	parsedType := parsedAssignment.Type
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", parsedType); es != ps {
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", withoutSpans(parsedType)); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
@@ -334,7 +588,7 @@ func TestConstrainedSequence(t *testing.T) {
 	END
 	`
 	expectedType := ConstraintedType{
-		Type: SequenceOfType{IntegerType{}},
+		Type: SequenceOfType{Type: IntegerType{}},
 		Constraint: SingleElementConstraint(SizeConstraint{
 			Constraint: SingleElementConstraint(ValueRange{
 				LowerEndpoint: RangeEndpoint{Value: Number(1)},
@@ -349,7 +603,7 @@ func TestConstrainedSequence(t *testing.T) {
 	}
 	parsedType := parsedAssignment.Type
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", parsedType); es != ps {
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", withoutSpans(parsedType)); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
@@ -377,11 +631,11 @@ func TestChoiceType(t *testing.T) {
 	END
 	`
 	expectedType := ChoiceType{AlternativeTypeList: []NamedType{
-		{Identifier("get-request"), TypeReference("GetRequest-PDU")},
-		{Identifier("get-next-request"), TypeReference("GetNextRequest-PDU")},
-		{Identifier("get-response"), TypeReference("GetResponse-PDU")},
-		{Identifier("set-request"), TypeReference("SetRequest-PDU")},
-		{Identifier("trap"), TypeReference("Trap-PDU")},
+		{Identifier: Identifier("get-request"), Type: TypeReference("GetRequest-PDU")},
+		{Identifier: Identifier("get-next-request"), Type: TypeReference("GetNextRequest-PDU")},
+		{Identifier: Identifier("get-response"), Type: TypeReference("GetResponse-PDU")},
+		{Identifier: Identifier("set-request"), Type: TypeReference("SetRequest-PDU")},
+		{Identifier: Identifier("trap"), Type: TypeReference("Trap-PDU")},
 	}}
 	r := testNotFails(t, content)
 	parsedAssignment := r.ModuleBody.AssignmentList.GetType("PDUs")
@@ -390,7 +644,7 @@ func TestChoiceType(t *testing.T) {
 	}
 	parsedType := parsedAssignment.Type
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", parsedType); es != ps {
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", withoutSpans(parsedType)); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
@@ -422,14 +676,15 @@ func TestChoiceTypeExtension(t *testing.T) {
 	`
 	expectedType := ChoiceType{
 		AlternativeTypeList: []NamedType{
-			{Identifier("get-request"), TypeReference("GetRequest-PDU")},
-			{Identifier("get-next-request"), TypeReference("GetNextRequest-PDU")},
-			{Identifier("get-response"), TypeReference("GetResponse-PDU")},
-			{Identifier("set-request"), TypeReference("SetRequest-PDU")},
-			{Identifier("trap"), TypeReference("Trap-PDU")},
+			{Identifier: Identifier("get-request"), Type: TypeReference("GetRequest-PDU")},
+			{Identifier: Identifier("get-next-request"), Type: TypeReference("GetNextRequest-PDU")},
+			{Identifier: Identifier("get-response"), Type: TypeReference("GetResponse-PDU")},
+			{Identifier: Identifier("set-request"), Type: TypeReference("SetRequest-PDU")},
+			{Identifier: Identifier("trap"), Type: TypeReference("Trap-PDU")},
 		},
+		Extensible: true,
 		ExtensionTypes: []ChoiceExtension{
-			NamedType{Identifier("extra-choice"), TypeReference("Extra-Type")},
+			NamedType{Identifier: Identifier("extra-choice"), Type: TypeReference("Extra-Type")},
 		},
 	}
 	r := testNotFails(t, content)
@@ -439,9 +694,50 @@ func TestChoiceTypeExtension(t *testing.T) {
 	}
 	parsedType := parsedAssignment.Type
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", parsedType); es != ps {
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", withoutSpans(parsedType)); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+func TestChoiceTypeExtensionGroups(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		Grouped ::= CHOICE { a INTEGER, ... ! 1, [[ 2: b INTEGER, c BOOLEAN ]], d NULL, [[ e INTEGER ]], ... }
+		Marker ::= CHOICE { a INTEGER, ..., ... }
+	END
+	`
+	r := testNotFails(t, content)
+	grouped := withoutSpans(r.ModuleBody.AssignmentList.GetType("Grouped").Type).(ChoiceType)
+	if grouped.ExceptionSpec == nil || grouped.ExceptionSpec.Value != Number(1) {
+		t.Errorf("Expected exception 1, got %+v", grouped.ExceptionSpec)
+	}
+	grouped.ExceptionSpec = nil
+	expectedType := ChoiceType{
+		AlternativeTypeList: []NamedType{{Identifier: "a", Type: IntegerType{}}},
+		Extensible:          true,
+		ExtensionTypes: []ChoiceExtension{
+			ExtensionAdditionAlternativesGroup{Version: 2, AlternativeTypeList: []NamedType{
+				{Identifier: "b", Type: IntegerType{}},
+				{Identifier: "c", Type: BooleanType{}},
+			}},
+			NamedType{Identifier: "d", Type: NullType{}},
+			ExtensionAdditionAlternativesGroup{AlternativeTypeList: []NamedType{{Identifier: "e", Type: IntegerType{}}}},
+		},
+	}
+	if es, ps := fmt.Sprintf("%+v", expectedType), fmt.Sprintf("%+v", grouped); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
+	var names []string
+	for _, alternative := range grouped.Alternatives() {
+		names = append(names, alternative.Identifier.Name())
+	}
+	if exp := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(exp, names) {
+		t.Errorf("Expected alternatives %v, got %v", exp, names)
+	}
+	marker := r.ModuleBody.AssignmentList.GetType("Marker").Type.(ChoiceType)
+	if !marker.Extensible || len(marker.ExtensionTypes) != 0 {
+		t.Errorf("Expected extensible CHOICE without additions, got %+v", marker)
+	}
 }
 
 func TestRealValues(t *testing.T) {
@@ -456,16 +752,16 @@ func TestRealValues(t *testing.T) {
 	END
 	`
 	expectedDecls := AssignmentList{
-		ValueAssignment{ValueReference("plusNum"), IntegerType{}, Number(123)},
-		ValueAssignment{ValueReference("minusNum"), IntegerType{}, Number(-123)},
-		ValueAssignment{ValueReference("plusReal"), RealType{}, Real(123.4)},
-		ValueAssignment{ValueReference("minusReal"), RealType{}, Real(-1.234)},
-		ValueAssignment{ValueReference("plusExp"), RealType{}, Real(1234.0)},
-		ValueAssignment{ValueReference("minusExp"), RealType{}, Real(1.234)},
+		ValueAssignment{ValueReference: ValueReference("plusNum"), Type: IntegerType{}, Value: Number(123)},
+		ValueAssignment{ValueReference: ValueReference("minusNum"), Type: IntegerType{}, Value: Number(-123)},
+		ValueAssignment{ValueReference: ValueReference("plusReal"), Type: RealType{}, Value: Real(123.4)},
+		ValueAssignment{ValueReference: ValueReference("minusReal"), Type: RealType{}, Value: Real(-1.234)},
+		ValueAssignment{ValueReference: ValueReference("plusExp"), Type: RealType{}, Value: Real(1234.0)},
+		ValueAssignment{ValueReference: ValueReference("minusExp"), Type: RealType{}, Value: Real(1.234)},
 	}
 	r := testNotFails(t, content)
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", r.ModuleBody.AssignmentList), fmt.Sprintf("%+v", expectedDecls); es != ps {
+	if es, ps := fmt.Sprintf("%+v", withoutSpans(r.ModuleBody.AssignmentList)), fmt.Sprintf("%+v", expectedDecls); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
@@ -478,12 +774,382 @@ func TestBooleanValues(t *testing.T) {
 	END
 	`
 	expectedDecls := AssignmentList{
-		ValueAssignment{ValueReference("true"), BooleanType{}, Boolean(true)},
-		ValueAssignment{ValueReference("false"), BooleanType{}, Boolean(false)},
+		ValueAssignment{ValueReference: ValueReference("true"), Type: BooleanType{}, Value: Boolean(true)},
+		ValueAssignment{ValueReference: ValueReference("false"), Type: BooleanType{}, Value: Boolean(false)},
 	}
 	r := testNotFails(t, content)
 	// quick and dirty
-	if es, ps := fmt.Sprintf("%+v", r.ModuleBody.AssignmentList), fmt.Sprintf("%+v", expectedDecls); es != ps {
+	if es, ps := fmt.Sprintf("%+v", withoutSpans(r.ModuleBody.AssignmentList)), fmt.Sprintf("%+v", expectedDecls); es != ps {
 		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
 	}
 }
+
+func TestStringValues(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		language VisibleString ::= "ENG"
+		version VisibleString ::= "1.2"
+		flags BIT STRING ::= '0101'B
+		octets OCTET STRING ::= '1F'H
+		Lang ::= SEQUENCE {
+			language VisibleString DEFAULT "ENG"
+		}
+	END
+	`
+	expectedDecls := AssignmentList{
+		ValueAssignment{ValueReference: ValueReference("language"), Type: RestrictedStringType{VisibleString}, Value: String("ENG")},
+		ValueAssignment{ValueReference: ValueReference("version"), Type: RestrictedStringType{VisibleString}, Value: String("1.2")},
+		ValueAssignment{ValueReference: ValueReference("flags"), Type: BitStringType{}, Value: BitString{Bytes: []byte{0x50}, BitLength: 4}},
+		ValueAssignment{ValueReference: ValueReference("octets"), Type: OctetStringType{}, Value: OctetString{0x1f}},
+		TypeAssignment{TypeReference: TypeReference("Lang"), Type: SequenceType{Components: ComponentTypeList{
+			NamedComponentType{
+				NamedType: NamedType{Identifier: Identifier("language"), Type: RestrictedStringType{VisibleString}},
+				Default:   String("ENG"),
+			},
+		}}},
+	}
+	r := testNotFails(t, content)
+	// quick and dirty
+	if es, ps := fmt.Sprintf("%+v", withoutSpans(r.ModuleBody.AssignmentList)), fmt.Sprintf("%+v", expectedDecls); es != ps {
+		t.Errorf("Repr mismatch:\n exp: %v\n got: %v", es, ps)
+	}
+}
+
+func TestValueNotation(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		point SEQUENCE { x INTEGER, y INTEGER DEFAULT 0 } ::= { x 1, y -2 }
+		empty SEQUENCE { x INTEGER OPTIONAL } ::= { }
+		list SEQUENCE OF INTEGER ::= { 1, 2, id-three }
+		named SET OF BOOLEAN ::= { first TRUE, second FALSE }
+		shape CHOICE { none NULL, size INTEGER } ::= size : 5
+		nothing CHOICE { none NULL, size INTEGER } ::= none : NULL
+		flags BIT STRING { a(0), b(1) } ::= { a, b }
+		no-flags BIT STRING ::= { }
+		half REAL ::= { mantissa 5, base 10, exponent -1 }
+		text UTF8String ::= { "a", {0, 0, 0, 66}, {4, 3}, other-text }
+		color ENUMERATED { red, green } ::= green
+		wrapped OCTET STRING ::= CONTAINING 5
+		referenced Point ::= { x 1, y 2 }
+		Defaults ::= SEQUENCE { list SEQUENCE OF INTEGER DEFAULT { 0 }, oid OBJECT IDENTIFIER DEFAULT { iso 3 } }
+	END
+	`
+	r := testNotFails(t, content)
+	assignments := r.ModuleBody.AssignmentList
+	for name, expected := range map[string]string{
+		"point":      "[{x 1} {y -2}]",
+		"empty":      "[]",
+		"list":       "[1 2 {<nil> id-three}]",
+		"named":      "[true false]",
+		"shape":      "{size 5}",
+		"nothing":    "{none {}}",
+		"flags":      "[a b]",
+		"no-flags":   "{[] 0}",
+		"half":       "0.5",
+		"text":       "[a B C {<nil> other-text}]",
+		"color":      "{<nil> green}",
+		"wrapped":    "{5}",
+		"referenced": "[[{<nil> x} 1] [{<nil> y} 2]]",
+	} {
+		if got := fmt.Sprint(assignments.GetValue(name).Value); got != expected {
+			t.Errorf("Expected %v to be %v, got %v", name, expected, got)
+		}
+	}
+	if _, ok := assignments.GetValue("referenced").Value.(BracedValue); !ok {
+		t.Errorf("Expected value of type reference to be left as BracedValue")
+	}
+	defaults := assignments.GetType("Defaults").Type.(SequenceType).Components
+	if got := fmt.Sprint(defaults[0].(NamedComponentType).Default); got != "[0]" {
+		t.Errorf("Expected SEQUENCE OF default [0], got %v", got)
+	}
+	if _, ok := defaults[1].(NamedComponentType).Default.(ObjectIdentifierValue); !ok {
+		t.Errorf("Expected OBJECT IDENTIFIER default, got %#v", defaults[1].(NamedComponentType).Default)
+	}
+}
+
+func TestAutomaticTags(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Seq ::= SEQUENCE { a INTEGER, ..., b BOOLEAN, [[ c INTEGER, d INTEGER ]], ..., e Choice }
+		Choice ::= CHOICE { x INTEGER, y SET { z INTEGER }, ..., w NULL }
+		Tagged ::= SEQUENCE { a [5] INTEGER, b INTEGER, c SEQUENCE OF SEQUENCE { d INTEGER } }
+		TaggedAddition ::= SEQUENCE { a INTEGER, ..., b [3] INTEGER, c INTEGER }
+		TaggedAlternative ::= CHOICE { a INTEGER, ..., b [5] INTEGER, c INTEGER }
+	END
+	`
+	r := testNotFails(t, content)
+	assignments := r.ModuleBody.AssignmentList
+	tagOf := func(t Type) string {
+		if tagged, ok := t.(TaggedType); ok {
+			return fmt.Sprintf("[%v]%v", tagged.Tag.ClassNumber, tagged.Automatic)
+		}
+		return "none"
+	}
+	seq := assignments.GetType("Seq").Type.(SequenceType)
+	group := seq.ExtensionAdditions[1].(ExtensionAdditionGroup)
+	choice := assignments.GetType("Choice").Type.(ChoiceType)
+	set := choice.AlternativeTypeList[1].Type.(TaggedType).Type.(SetType)
+	tagged := assignments.GetType("Tagged").Type.(SequenceType)
+	inner := tagged.Components[2].(NamedComponentType).NamedType.Type.(SequenceOfType).Type.(SequenceType)
+	taggedAddition := assignments.GetType("TaggedAddition").Type.(SequenceType)
+	taggedAlternative := assignments.GetType("TaggedAlternative").Type.(ChoiceType)
+	for _, tc := range []struct {
+		t        Type
+		expected string
+	}{
+		{seq.Components[0].(NamedComponentType).NamedType.Type, "[0]true"},
+		{seq.TrailingRootComponents[0].(NamedComponentType).NamedType.Type, "[1]true"},
+		{seq.ExtensionAdditions[0].(NamedComponentType).NamedType.Type, "[2]true"},
+		{group.Components[0].(NamedComponentType).NamedType.Type, "[3]true"},
+		{group.Components[1].(NamedComponentType).NamedType.Type, "[4]true"},
+		{choice.AlternativeTypeList[0].Type, "[0]true"},
+		{choice.AlternativeTypeList[1].Type, "[1]true"},
+		{choice.ExtensionTypes[0].(NamedType).Type, "[2]true"},
+		{set.Components[0].(NamedComponentType).NamedType.Type, "[0]true"},
+		{tagged.Components[0].(NamedComponentType).NamedType.Type, "[5]false"},
+		{tagged.Components[1].(NamedComponentType).NamedType.Type, "none"},
+		{inner.Components[0].(NamedComponentType).NamedType.Type, "[0]true"},
+		{taggedAddition.Components[0].(NamedComponentType).NamedType.Type, "[0]true"},
+		{taggedAddition.ExtensionAdditions[0].(NamedComponentType).NamedType.Type, "[3]false"},
+		{taggedAddition.ExtensionAdditions[1].(NamedComponentType).NamedType.Type, "[2]true"},
+		{taggedAlternative.ExtensionTypes[0].(NamedType).Type, "[5]false"},
+		{taggedAlternative.ExtensionTypes[1].(NamedType).Type, "[2]true"},
+	} {
+		if got := tagOf(tc.t); got != tc.expected {
+			t.Errorf("Expected tag %v, got %v of %#v", tc.expected, got, tc.t)
+		}
+	}
+}
+
+func TestNodeSpans(t *testing.T) {
+	content := "TestSpec DEFINITIONS ::= BEGIN\n" +
+		"\tMySeq ::= SEQUENCE {\n" +
+		"\t\tnum [0] INTEGER,\n" +
+		"\t\tstr OCTET STRING\n" +
+		"\t}\n" +
+		"END\n"
+	r := testNotFails(t, content)
+	pos := func(line, col int) Position { return Position{Line: line, Column: col} }
+	if exp := (Span{pos(1, 1), pos(6, 4)}); r.Span != exp {
+		t.Errorf("Module span: expected %v-%v, got %v-%v", exp.Start, exp.End, r.Span.Start, r.Span.End)
+	}
+	assignment := r.ModuleBody.AssignmentList.GetType("MySeq")
+	if assignment == nil {
+		t.Fatal("Expected MySeq in assignments")
+	}
+	if exp := (Span{pos(2, 2), pos(5, 3)}); assignment.Span != exp {
+		t.Errorf("Assignment span: expected %v-%v, got %v-%v", exp.Start, exp.End, assignment.Span.Start, assignment.Span.End)
+	}
+	components := assignment.Type.(SequenceType).Components
+	first := components[0].(NamedComponentType).NamedType
+	if exp := (Span{pos(3, 3), pos(3, 18)}); first.Span != exp {
+		t.Errorf("NamedType span: expected %v-%v, got %v-%v", exp.Start, exp.End, first.Span.Start, first.Span.End)
+	}
+	if tagged, exp := first.Type.(TaggedType), (Span{pos(3, 7), pos(3, 18)}); tagged.Span != exp {
+		t.Errorf("TaggedType span: expected %v-%v, got %v-%v", exp.Start, exp.End, tagged.Span.Start, tagged.Span.End)
+	}
+	second := components[1].(NamedComponentType).NamedType
+	if exp := (Span{pos(4, 3), pos(4, 19)}); second.Span != exp {
+		t.Errorf("NamedType span: expected %v-%v, got %v-%v", exp.Start, exp.End, second.Span.Start, second.Span.End)
+	}
+}
+
+func TestCommentAttachment(t *testing.T) {
+	content := "-- Module doc\n" +
+		"TestSpec DEFINITIONS ::= BEGIN\n" +
+		"\t-- Sequence doc\n" +
+		"\t/* spans\n" +
+		"\t   lines */\n" +
+		"\tMySeq ::= SEQUENCE {\n" +
+		"\t\t-- num doc\n" +
+		"\t\tnum INTEGER, -- num comment\n" +
+		"\t\tstr OCTET STRING OPTIONAL -- str comment\n" +
+		"\t}\n" +
+		"\n" +
+		"\t-- detached\n" +
+		"\n" +
+		"\tMyInt ::= INTEGER -- int comment\n" +
+		"\tmyVal MyInt ::= 1 -- value comment\n" +
+		"END\n"
+	r := testNotFails(t, content)
+	lines := func(g CommentGroup) string { return strings.Join(g.Lines(), "|") }
+	check := func(what string, g CommentGroup, exp string) {
+		if got := lines(g); got != exp {
+			t.Errorf("%v: expected %q, got %q", what, exp, got)
+		}
+	}
+	check("Module doc", r.Doc, "Module doc")
+	seq := r.ModuleBody.AssignmentList.GetType("MySeq")
+	check("MySeq doc", seq.Doc, "Sequence doc|spans|lines")
+	check("MySeq line comment", seq.LineComment, "")
+	components := seq.Type.(SequenceType).Components
+	num := components[0].(NamedComponentType).NamedType
+	check("num doc", num.Doc, "num doc")
+	check("num line comment", num.LineComment, "num comment")
+	str := components[1].(NamedComponentType).NamedType
+	check("str doc", str.Doc, "")
+	check("str line comment", str.LineComment, "str comment")
+	myInt := r.ModuleBody.AssignmentList.GetType("MyInt")
+	check("MyInt doc", myInt.Doc, "")
+	check("MyInt line comment", myInt.LineComment, "int comment")
+	myVal := r.ModuleBody.AssignmentList.GetValue("myVal")
+	check("myVal doc", myVal.Doc, "")
+	check("myVal line comment", myVal.LineComment, "value comment")
+	if exp := (Span{Position{Line: 8, Column: 16}, Position{Line: 8, Column: 30}}); num.LineComment[0].Span != exp {
+		t.Errorf("Comment span: expected %v-%v, got %v-%v", exp.Start, exp.End, num.LineComment[0].Span.Start, num.LineComment[0].Span.End)
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	content := "TestSpec DEFINITIONS ::= BEGIN\n" +
+		"\tMySeq ::= SEQUENCE { num INTEGER,, }\n" +
+		"END\n"
+	_, err := ParseString(content)
+	if err == nil {
+		t.Fatal("Expected syntax error")
+	}
+	if exp := "2:35: "; !strings.HasPrefix(err.Error(), exp) {
+		t.Errorf("Expected error to start with %q, got %q", exp, err.Error())
+	}
+	if exp := `unexpected ","`; !strings.Contains(err.Error(), exp) {
+		t.Errorf("Expected error to contain %q, got %q", exp, err.Error())
+	}
+	_, err = parseStream("test.asn1", strings.NewReader("TestSpec DEFINITIONS ::= BEGIN\n\tflags BIT STRING ::= '012'B\nEND"))
+	if err == nil {
+		t.Fatal("Expected lexer error")
+	}
+	if exp := "test.asn1:2:23: "; !strings.HasPrefix(err.Error(), exp) {
+		t.Errorf("Expected error to start with %q, got %q", exp, err.Error())
+	}
+}
+
+func testFirstError(t *testing.T, err error) *ParseError {
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) == 0 {
+		t.Fatalf("Expected non-empty ErrorList, got %T: %v", err, err)
+	}
+	return errs[0]
+}
+
+func TestParseError(t *testing.T) {
+	content := "TestSpec DEFINITIONS ::= BEGIN\n" +
+		"\tMySeq ::= SEQUENCE { num INTEGER,, }\n" +
+		"END\n"
+	_, err := ParseString(content)
+	parseErr := testFirstError(t, err)
+	if exp := (Position{Line: 2, Column: 35}); parseErr.Pos != exp {
+		t.Errorf("Expected error at %v, got %v", exp, parseErr.Pos)
+	}
+	if parseErr.Token != "," {
+		t.Errorf("Expected offending token %q, got %q", ",", parseErr.Token)
+	}
+	if exp, got := []string{"identifier", "...", "COMPONENTS"}, parseErr.Expected; !reflect.DeepEqual(exp, got) {
+		t.Errorf("Expected tokens %v, got %v", exp, got)
+	}
+	if exp := "\tMySeq ::= SEQUENCE { num INTEGER,, }\n\t                                 ^"; parseErr.Snippet != exp {
+		t.Errorf("Snippet mismatch:\n exp:\n%v\n got:\n%v", exp, parseErr.Snippet)
+	}
+	if exp := `2:35: syntax error: unexpected ",", expecting identifier, "..." or COMPONENTS`; parseErr.Error() != exp {
+		t.Errorf("Expected message %q, got %q", exp, parseErr.Error())
+	}
+}
+
+func TestParseErrorExpectedTokens(t *testing.T) {
+	for _, tc := range []struct {
+		content  string
+		expected []string
+	}{
+		{"TestSpec DEFINITIONS BEGIN END", []string{"::=", "AUTOMATIC", "EXPLICIT", "EXTENSIBILITY", "IMPLICIT"}},
+		{"TestSpec DEFINITIONS ::= BEGIN\n\tMyInt ::= INTEGER", []string{"typereference", "identifier", "{", "(", "END"}},
+		{"TestSpec DEFINITIONS ::= BEGIN\n\tMyInt INTEGER\nEND", []string{"::="}},
+	} {
+		_, err := ParseString(tc.content)
+		parseErr := testFirstError(t, err)
+		if !reflect.DeepEqual(tc.expected, parseErr.Expected) {
+			t.Errorf("%v: expected tokens %v, got %v", tc.content, tc.expected, parseErr.Expected)
+		}
+	}
+}
+
+func TestParseErrorLexical(t *testing.T) {
+	_, err := parseStream("test.asn1", strings.NewReader("TestSpec DEFINITIONS ::= BEGIN\n\tflags BIT STRING ::= '012'B\nEND"))
+	parseErr := testFirstError(t, err)
+	if parseErr.Token != "'012'B" {
+		t.Errorf("Expected offending token %q, got %q", "'012'B", parseErr.Token)
+	}
+	if len(parseErr.Expected) != 0 {
+		t.Errorf("Expected no expected tokens for lexical error, got %v", parseErr.Expected)
+	}
+	if exp := "\tflags BIT STRING ::= '012'B\n\t                     ^"; parseErr.Snippet != exp {
+		t.Errorf("Snippet mismatch:\n exp:\n%v\n got:\n%v", exp, parseErr.Snippet)
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	content := `
+	First DEFINITIONS ::= BEGIN
+		Broken ::= SEQUENCE { num INTEGER,, }
+		MyInt ::= INTEGER
+		MyBool ::= BOOLEAN junk
+		MySet ::= SET { flag BOOLEAN }
+		brokenValue ::= 5
+		myValue INTEGER ::= 5
+	END
+	Second DEFINITIONS ::= BEGN
+		MyInt ::= INTEGER
+	END
+	Third DEFINITIONS ::= BEGIN
+		MyInt ::= INTEGER (1..
+		MyReal ::= REAL
+	END
+	`
+	modules, err := ParseString(content)
+	errs, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("Expected ErrorList, got %T: %v", err, err)
+	}
+	// MyReal after unfinished range may start value reference MyReal.value, so error is at ::= following it
+	expectedErrors := []Position{{Line: 3, Column: 37}, {Line: 6, Column: 13}, {Line: 7, Column: 15}, {Line: 10, Column: 25}, {Line: 15, Column: 10}}
+	if len(errs) != len(expectedErrors) {
+		t.Fatalf("Expected %v errors, got %v: %v", len(expectedErrors), len(errs), errs)
+	}
+	for i, pos := range expectedErrors {
+		if errs[i].Pos != pos {
+			t.Errorf("Expected error %v at %v, got %v", i, pos, errs[i])
+		}
+	}
+	expectedAssignments := map[string][]string{
+		"First": {"MyInt", "MyBool", "MySet", "myValue"},
+		"Third": {"MyReal"},
+	}
+	if len(modules) != len(expectedAssignments) {
+		t.Fatalf("Expected %v modules, got %v", len(expectedAssignments), len(modules))
+	}
+	for _, module := range modules {
+		var names []string
+		for _, assignment := range module.ModuleBody.AssignmentList {
+			names = append(names, assignment.Reference().Name())
+		}
+		if exp := expectedAssignments[module.ModuleIdentifier.Reference]; !reflect.DeepEqual(exp, names) {
+			t.Errorf("Module %v: expected assignments %v, got %v", module.ModuleIdentifier.Reference, exp, names)
+		}
+	}
+}
+
+func TestErrorRecoveryLexical(t *testing.T) {
+	content := `
+	TestSpec DEFINITIONS ::= BEGIN
+		flags BIT STRING ::= '012'B
+		MyInt ::= INTEGER # 
+		MyBool ::= BOOLEAN
+	END
+	`
+	modules, err := ParseString(content)
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", err)
+	}
+	if len(modules) != 1 || len(modules[0].ModuleBody.AssignmentList) != 3 {
+		t.Fatalf("Expected all 3 assignments to be parsed, got %+v", modules)
+	}
+}
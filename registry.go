@@ -0,0 +1,1065 @@
+package asn1go
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Registry indexes modules by name and by DefinitiveIdentifier OID and resolves imports between them
+type Registry struct {
+	modules []*ModuleDefinition
+	byName  map[string][]*ModuleDefinition
+	byOID   map[string][]*ModuleDefinition
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: map[string][]*ModuleDefinition{},
+		byOID:  map[string][]*ModuleDefinition{},
+	}
+}
+
+// Add registers modules
+func (r *Registry) Add(modules ...ModuleDefinition) {
+	for i := range modules {
+		module := modules[i]
+		r.modules = append(r.modules, &module)
+		name := module.ModuleIdentifier.Reference
+		r.byName[name] = append(r.byName[name], &module)
+		if key := module.ModuleIdentifier.DefinitiveIdentifier.key(); key != "" {
+			r.byOID[key] = append(r.byOID[key], &module)
+		}
+	}
+}
+
+// LoadFiles parses files and registers modules found in them. Errors of all files are returned together,
+// modules parsed before an error are registered anyway.
+func (r *Registry) LoadFiles(names ...string) error {
+	var errs ErrorList
+	for _, name := range names {
+		modules, err := parseFile(name)
+		r.Add(modules...)
+		if list, ok := err.(ErrorList); ok {
+			errs = append(errs, list...)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Modules returns registered modules in order they were added
+func (r *Registry) Modules() []ModuleDefinition {
+	res := make([]ModuleDefinition, len(r.modules))
+	for i, module := range r.modules {
+		res[i] = *module
+	}
+	return res
+}
+
+// Module returns module with name, nil if there is no such module or name is ambiguous
+func (r *Registry) Module(name string) *ModuleDefinition {
+	if modules := r.byName[name]; len(modules) == 1 {
+		return modules[0]
+	}
+	return nil
+}
+
+// ModuleByOID returns module with DefinitiveIdentifier equal to oid, nil if there is no such module or oid is ambiguous
+func (r *Registry) ModuleByOID(oid DefinitiveIdentifier) *ModuleDefinition {
+	if modules := r.byOID[oid.key()]; len(modules) == 1 {
+		return modules[0]
+	}
+	return nil
+}
+
+// ResolveModule finds module referenced in IMPORTS. AssignedIdentifier takes precedence over the name when
+// it is given and matches registered module, as module names are not required to be unique.
+func (r *Registry) ResolveModule(ref GlobalModuleReference) (*ModuleDefinition, error) {
+	if oid, ok := ref.AssignedIdentifier.(ObjectIdentifierValue); ok {
+		if modules := r.byOID[oidValueKey(oid)]; len(modules) == 1 {
+			return modules[0], nil
+		}
+	}
+	switch modules := r.byName[ref.Reference]; len(modules) {
+	case 0:
+		return nil, fmt.Errorf("module %v not found", ref.Reference)
+	case 1:
+		return modules[0], nil
+	default:
+		var places []string
+		for _, module := range modules {
+			places = append(places, module.Span.Start.String())
+		}
+		return nil, fmt.Errorf("module reference %v is ambiguous, defined at %v", ref.Reference, strings.Join(places, ", "))
+	}
+}
+
+// ResolvedSymbol is imported symbol together with its definition
+type ResolvedSymbol struct {
+	Module     *ModuleDefinition // module defining the symbol
+	Assignment Assignment
+}
+
+// ResolveSymbol finds definition of symbol imported by module. Returns nil without error if module doesn't
+// import name.
+func (r *Registry) ResolveSymbol(module *ModuleDefinition, name string) (*ResolvedSymbol, error) {
+	var res *ResolvedSymbol
+	var from string
+	for _, imports := range module.ModuleBody.Imports {
+		if !importsSymbol(imports, name) {
+			continue
+		}
+		source, err := r.ResolveModule(imports.Module)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil && res.Module != source {
+			return nil, fmt.Errorf("%v is imported from both %v and %v", name, from, imports.Module.Reference)
+		}
+		assignment := source.ModuleBody.AssignmentList.Get(name)
+		if assignment == nil {
+			return nil, fmt.Errorf("module %v does not define %v", imports.Module.Reference, name)
+		}
+		if !source.ModuleBody.Exports.Exported(name) {
+			return nil, fmt.Errorf("module %v does not export %v", imports.Module.Reference, name)
+		}
+		res = &ResolvedSymbol{Module: source, Assignment: assignment}
+		from = imports.Module.Reference
+	}
+	return res, nil
+}
+
+func importsSymbol(imports SymbolsFromModule, name string) bool {
+	for _, symbol := range imports.SymbolList {
+		if reference, ok := symbol.(Reference); ok && reference.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveExternalType finds assignment of type referenced as Module.Type in module. Referenced module
+// must export the type unless it's the module itself.
+func (r *Registry) ResolveExternalType(module *ModuleDefinition, ref ExternalTypeReference) (*ModuleDefinition, *TypeAssignment, error) {
+	name := ref.TypeReference.Name()
+	source := module
+	if ref.ModuleReference.Name() != module.ModuleIdentifier.Reference {
+		var err error
+		if source, err = r.ResolveModule(GlobalModuleReference{Reference: ref.ModuleReference.Name()}); err != nil {
+			return nil, nil, err
+		}
+		if !source.ModuleBody.Exports.Exported(name) {
+			return nil, nil, fmt.Errorf("module %v does not export %v", ref.ModuleReference, name)
+		}
+	}
+	assignment := source.ModuleBody.AssignmentList.GetType(name)
+	if assignment == nil {
+		return nil, nil, fmt.Errorf("module %v does not define type %v", ref.ModuleReference, name)
+	}
+	return source, assignment, nil
+}
+
+// ExpandComponentsOf replaces COMPONENTS OF in SEQUENCE or SET t of module by root components of the referenced
+// type, which must be SEQUENCE or SET respectively (X.680 25.5, 27.2). Extension additions of the referenced type
+// aren't included. Included components lose tags assigned by automatic tagging of their own type, and if t is
+// tagged automatically, all its components are numbered again in order after the expansion. References to types
+// of other modules in included components are replaced by external references, references in nested inline
+// types are kept as is.
+func (r *Registry) ExpandComponentsOf(module *ModuleDefinition, t Type) (Type, error) {
+	switch tt := t.(type) {
+	case SequenceType:
+		return r.expandComponentsOf(module, tt, false, nil)
+	case SetType:
+		expanded, err := r.expandComponentsOf(module, SequenceType(tt), true, nil)
+		return SetType(expanded), err
+	}
+	return t, fmt.Errorf("COMPONENTS OF can only be expanded in SEQUENCE or SET, got %v", t)
+}
+
+func (r *Registry) expandComponentsOf(module *ModuleDefinition, t SequenceType, set bool, chain []string) (SequenceType, error) {
+	if !hasComponentsOf(t) {
+		return t, nil
+	}
+	// automatic tagging is decided before the expansion (X.680 25.7)
+	automatic := module.TagDefault == TAGS_AUTOMATIC && !hasTaggedComponent(t.Components) &&
+		!hasTaggedComponent(t.TrailingRootComponents)
+	var err error
+	if t.Components, err = r.includeComponents(module, t.Components, set, chain); err != nil {
+		return t, err
+	}
+	if t.TrailingRootComponents, err = r.includeComponents(module, t.TrailingRootComponents, set, chain); err != nil {
+		return t, err
+	}
+	additions := make([]ExtensionAddition, 0, len(t.ExtensionAdditions))
+	for _, addition := range t.ExtensionAdditions {
+		switch a := addition.(type) {
+		case ComponentsOfComponentType:
+			included, err := r.includeComponents(module, ComponentTypeList{a}, set, chain)
+			if err != nil {
+				return t, err
+			}
+			for _, component := range included {
+				additions = append(additions, component.(NamedComponentType))
+			}
+		case ExtensionAdditionGroup:
+			if a.Components, err = r.includeComponents(module, a.Components, set, chain); err != nil {
+				return t, err
+			}
+			additions = append(additions, a)
+		default:
+			additions = append(additions, addition)
+		}
+	}
+	t.ExtensionAdditions = additions
+	if automatic {
+		t = retagComponents(t)
+	}
+	return t, nil
+}
+
+func hasComponentsOf(t SequenceType) bool {
+	for _, component := range t.AllComponents() {
+		if _, ok := component.(ComponentsOfComponentType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// includeComponents returns copy of components with COMPONENTS OF replaced by included components
+func (r *Registry) includeComponents(module *ModuleDefinition, components ComponentTypeList, set bool, chain []string) (ComponentTypeList, error) {
+	res := make(ComponentTypeList, 0, len(components))
+	for _, component := range components {
+		componentsOf, ok := component.(ComponentsOfComponentType)
+		if !ok {
+			res = append(res, component)
+			continue
+		}
+		source, included, err := r.componentsOfType(module, componentsOf.Type, set, chain)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range append(append(ComponentTypeList{}, included.Components...), included.TrailingRootComponents...) {
+			named := c.(NamedComponentType)
+			named.NamedType.Type = untagAutomatic(named.NamedType.Type)
+			if source.ModuleIdentifier.Reference != module.ModuleIdentifier.Reference {
+				named.NamedType.Type = r.qualifyReferences(source, named.NamedType.Type)
+			}
+			res = append(res, named)
+		}
+	}
+	return res, nil
+}
+
+// componentsOfType resolves type referenced by COMPONENTS OF in module to SEQUENCE or SET with COMPONENTS OF
+// expanded, returns it together with module defining it
+func (r *Registry) componentsOfType(module *ModuleDefinition, t Type, set bool, chain []string) (*ModuleDefinition, SequenceType, error) {
+	kind := "SEQUENCE"
+	if set {
+		kind = "SET"
+	}
+	resolved := t
+	for {
+		switch tt := resolved.(type) {
+		case TaggedType:
+			resolved = tt.Type
+			continue
+		case ConstraintedType:
+			resolved = tt.Type
+			continue
+		case TypeReference:
+			key := module.ModuleIdentifier.Reference + "." + tt.Name()
+			for _, visited := range chain {
+				if visited == key {
+					return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v includes itself", t)
+				}
+			}
+			chain = append(chain, key)
+			if assignment := module.ModuleBody.AssignmentList.GetType(tt.Name()); assignment != nil {
+				resolved = assignment.Type
+				continue
+			}
+			imported, err := r.ResolveSymbol(module, tt.Name())
+			if err != nil {
+				return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v: %v", t, err)
+			}
+			if imported == nil {
+				return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v: type %v is not defined", t, tt)
+			}
+			assignment, ok := imported.Assignment.(TypeAssignment)
+			if !ok {
+				return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v: %v is not a type", t, tt)
+			}
+			module, resolved = imported.Module, assignment.Type
+			continue
+		case ExternalTypeReference:
+			source, assignment, err := r.ResolveExternalType(module, tt)
+			if err != nil {
+				return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v: %v", t, err)
+			}
+			module, resolved = source, assignment.TypeReference
+			continue
+		case SequenceType:
+			if !set {
+				expanded, err := r.expandComponentsOf(module, tt, set, chain)
+				return module, expanded, err
+			}
+		case SetType:
+			if set {
+				expanded, err := r.expandComponentsOf(module, SequenceType(tt), set, chain)
+				return module, expanded, err
+			}
+		}
+		return nil, SequenceType{}, fmt.Errorf("COMPONENTS OF %v in %v does not reference %v type", t, kind, kind)
+	}
+}
+
+// qualifyReferences replaces references to types defined in or imported by source module with external
+// references, so that component type can be used in other modules
+func (r *Registry) qualifyReferences(source *ModuleDefinition, t Type) Type {
+	switch tt := t.(type) {
+	case TypeReference:
+		if source.ModuleBody.AssignmentList.GetType(tt.Name()) != nil {
+			return ExternalTypeReference{ModuleReference: ModuleReference(source.ModuleIdentifier.Reference), TypeReference: tt}
+		}
+		if imported, err := r.ResolveSymbol(source, tt.Name()); err == nil && imported != nil {
+			return ExternalTypeReference{ModuleReference: ModuleReference(imported.Module.ModuleIdentifier.Reference), TypeReference: tt}
+		}
+	case TaggedType:
+		tt.Type = r.qualifyReferences(source, tt.Type)
+		return tt
+	case ConstraintedType:
+		tt.Type = r.qualifyReferences(source, tt.Type)
+		return tt
+	case SequenceOfType:
+		tt.Type = r.qualifyReferences(source, tt.Type)
+		return tt
+	case SetOfType:
+		tt.Type = r.qualifyReferences(source, tt.Type)
+		return tt
+	}
+	return t
+}
+
+// ImportError describes import that can't be resolved
+type ImportError struct {
+	Pos     Position // position of SymbolsFromModule in importing module
+	Module  string   // importing module
+	Message string
+	Cycle   []string // modules forming import cycle, set only for cycles, which X.680 allows but Go packages don't
+}
+
+func (e *ImportError) position() Position {
+	return e.Pos
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", e.Pos, e.Module, e.Message)
+}
+
+// ImportErrorList is a list of all import errors found in registry
+type ImportErrorList []*ImportError
+
+func (l ImportErrorList) Error() string {
+	return errorList[*ImportError](l).Error()
+}
+
+// ResolveImports checks imports of all registered modules: every imported module must be found unambiguously,
+// it must define and export imported symbols, symbol can't be imported from several modules or be imported and
+// defined at the same time. Modules importing each other in cycle are reported too, with Cycle set: X.680 allows
+// them, but Go packages generated for the modules would import each other. Cycles don't stop imports from being
+// resolved, they are only a warning.
+func (r *Registry) ResolveImports() error {
+	var errs ImportErrorList
+	report := func(module *ModuleDefinition, pos Position, format string, args ...interface{}) {
+		errs = append(errs, &ImportError{Pos: pos, Module: module.ModuleIdentifier.Reference, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, module := range r.modules {
+		importedFrom := map[string]string{}
+		for _, imports := range module.ModuleBody.Imports {
+			source, err := r.ResolveModule(imports.Module)
+			if err != nil {
+				report(module, imports.Span.Start, "%v", err)
+				continue
+			}
+			for _, symbol := range imports.SymbolList {
+				reference, ok := symbol.(Reference)
+				if !ok {
+					continue
+				}
+				name := reference.Name()
+				if from, ok := importedFrom[name]; ok && from != imports.Module.Reference {
+					report(module, imports.Span.Start, "%v is imported from both %v and %v", name, from, imports.Module.Reference)
+				}
+				importedFrom[name] = imports.Module.Reference
+				if source.ModuleBody.AssignmentList.Get(name) == nil {
+					report(module, imports.Span.Start, "module %v does not define %v", imports.Module.Reference, name)
+				} else if !source.ModuleBody.Exports.Exported(name) {
+					report(module, imports.Span.Start, "module %v does not export %v", imports.Module.Reference, name)
+				}
+				if module.ModuleBody.AssignmentList.Get(name) != nil {
+					report(module, imports.Span.Start, "%v is both imported from %v and defined in module", name, imports.Module.Reference)
+				}
+			}
+		}
+	}
+	for _, cycle := range r.importCycles() {
+		report(cycle.module, cycle.pos, "import cycle: %v", strings.Join(cycle.path, " -> "))
+		errs[len(errs)-1].Cycle = cycle.path
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type importCycle struct {
+	module *ModuleDefinition // module whose import closes the cycle
+	pos    Position
+	path   []string
+}
+
+// importCycles finds cycles in module import graph with depth first search, each cycle is reported once
+func (r *Registry) importCycles() []importCycle {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := map[*ModuleDefinition]int{}
+	var stack []*ModuleDefinition
+	var res []importCycle
+	var visit func(module *ModuleDefinition)
+	visit = func(module *ModuleDefinition) {
+		state[module] = inProgress
+		stack = append(stack, module)
+		for _, imports := range module.ModuleBody.Imports {
+			source, err := r.ResolveModule(imports.Module)
+			if err != nil {
+				continue
+			}
+			switch state[source] {
+			case unvisited:
+				visit(source)
+			case inProgress:
+				var path []string
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == source {
+						for _, m := range stack[i:] {
+							path = append(path, m.ModuleIdentifier.Reference)
+						}
+						break
+					}
+				}
+				path = append(path, source.ModuleIdentifier.Reference)
+				res = append(res, importCycle{module: module, pos: imports.Span.Start, path: path})
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[module] = done
+	}
+	for _, module := range r.modules {
+		if state[module] == unvisited {
+			visit(module)
+		}
+	}
+	return res
+}
+
+// wellKnownArcs are names of top-level OID arcs that may be used without a number
+var wellKnownArcs = map[string]int{
+	"itu-t":           0,
+	"ccitt":           0,
+	"iso":             1,
+	"joint-iso-itu-t": 2,
+	"joint-iso-ccitt": 2,
+}
+
+// wellKnownSubArcs are names of second-level OID arcs that may be used without a number, by top-level arc
+var wellKnownSubArcs = map[int]map[string]int{
+	0: {
+		"recommendation":          0,
+		"question":                1,
+		"administration":          2,
+		"network-operator":        3,
+		"identified-organization": 4,
+	},
+	1: {
+		"standard":                0,
+		"registration-authority":  1,
+		"member-body":             2,
+		"identified-organization": 3,
+	},
+}
+
+// key returns dotted form of identifier used to index modules, empty if identifier is absent
+func (d DefinitiveIdentifier) key() string {
+	var arcs []string
+	for i, c := range d {
+		arcs = append(arcs, arcKey(i, c.Name, c.Id))
+	}
+	return strings.Join(arcs, ".")
+}
+
+// oidValueKey returns the same key as DefinitiveIdentifier.key for AssignedIdentifier
+func oidValueKey(oid ObjectIdentifierValue) string {
+	var arcs []string
+	for i, c := range oid {
+		element, ok := c.(ObjectIdElement)
+		if !ok || element.Reference != nil {
+			// defined values can't be compared without resolving them
+			return ""
+		}
+		arcs = append(arcs, arcKey(i, element.Name, element.Id))
+	}
+	return strings.Join(arcs, ".")
+}
+
+func arcKey(i int, name string, id int) string {
+	if id == 0 && name != "" {
+		if number, ok := wellKnownArcs[name]; ok && i == 0 {
+			return strconv.Itoa(number)
+		}
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// ResolveValue resolves references in value used in module: DefinedValue, identifiers that name value
+// assignments and references within OBJECT IDENTIFIER values. References are followed across modules
+// until a value without references is reached. Values without references are returned as is.
+func (r *Registry) ResolveValue(module *ModuleDefinition, value Value) (Value, error) {
+	return r.resolveValue(module, value, nil)
+}
+
+// valueKey identifies value assignment in the chain of references being resolved
+type valueKey struct {
+	module *ModuleDefinition
+	name   string
+}
+
+// valueCycleError is returned when value references lead back to value being resolved, cycle starts and ends
+// with that value
+type valueCycleError struct {
+	cycle []valueKey
+}
+
+func (e *valueCycleError) Error() string {
+	var names []string
+	for _, k := range e.cycle {
+		names = append(names, k.name)
+	}
+	return fmt.Sprintf("value reference cycle: %v", strings.Join(names, " -> "))
+}
+
+// key is the same for cycles of the same values, whichever value they start with
+func (e *valueCycleError) key() string {
+	var names []string
+	for _, k := range e.cycle[1:] {
+		names = append(names, k.module.ModuleIdentifier.Reference+"."+k.name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+func (r *Registry) resolveValue(module *ModuleDefinition, value Value, chain []valueKey) (Value, error) {
+	switch v := value.(type) {
+	case DefinedValue:
+		source, assignment, err := r.lookupValueAssignment(module, v)
+		if err != nil {
+			return nil, err
+		}
+		return r.resolveAssignedValue(source, assignment, chain)
+	case IdentifiedIntegerValue:
+		// identifier is either a named number of the type or a reference to value
+		ref := DefinedValue{ValueReference: ValueReference(v.Name)}
+		if source, assignment, err := r.lookupValueAssignment(module, ref); err == nil {
+			return r.resolveAssignedValue(source, assignment, chain)
+		}
+		return value, nil
+	case ObjectIdentifierValue:
+		return r.resolveObjectIdentifier(module, v, chain)
+	}
+	return value, nil
+}
+
+// ObjectIdentifierArcs resolves OBJECT IDENTIFIER value used in module and returns its arcs as numbers.
+// Name forms are allowed only for well-known arcs and values defined in module.
+func (r *Registry) ObjectIdentifierArcs(module *ModuleDefinition, value Value) ([]int, error) {
+	resolved, err := r.ResolveValue(module, value)
+	if err != nil {
+		return nil, err
+	}
+	oid, ok := resolved.(ObjectIdentifierValue)
+	if !ok {
+		return nil, fmt.Errorf("value %v is not an OBJECT IDENTIFIER", value)
+	}
+	arcs := make([]int, 0, len(oid))
+	for i, component := range oid {
+		element, ok := component.(ObjectIdElement)
+		if !ok {
+			return nil, fmt.Errorf("unexpected OID component %v", component)
+		}
+		number := element.Id
+		if element.NameForm {
+			ok = false
+			if i == 0 {
+				number, ok = wellKnownArcs[element.Name]
+			} else if i == 1 {
+				number, ok = wellKnownSubArcs[arcs[0]][element.Name]
+			}
+			if !ok {
+				return nil, fmt.Errorf("unknown OID arc %v", element.Name)
+			}
+		}
+		arcs = append(arcs, number)
+	}
+	return arcs, nil
+}
+
+// lookupValueAssignment finds assignment of referenced value and module it belongs to
+func (r *Registry) lookupValueAssignment(module *ModuleDefinition, ref DefinedValue) (*ModuleDefinition, *ValueAssignment, error) {
+	name := ref.ValueReference.Name()
+	if ref.ModuleReference != "" {
+		source, err := r.ResolveModule(GlobalModuleReference{Reference: ref.ModuleReference.Name()})
+		if err != nil {
+			return nil, nil, err
+		}
+		assignment := source.ModuleBody.AssignmentList.GetValue(name)
+		if assignment == nil {
+			return nil, nil, fmt.Errorf("module %v does not define value %v", ref.ModuleReference, name)
+		}
+		if source != module && !source.ModuleBody.Exports.Exported(name) {
+			return nil, nil, fmt.Errorf("module %v does not export %v", ref.ModuleReference, name)
+		}
+		return source, assignment, nil
+	}
+	if assignment := module.ModuleBody.AssignmentList.GetValue(name); assignment != nil {
+		return module, assignment, nil
+	}
+	imported, err := r.ResolveSymbol(module, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if imported != nil {
+		if assignment, ok := imported.Assignment.(ValueAssignment); ok {
+			return imported.Module, &assignment, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("value %v is not defined", ref)
+}
+
+func (r *Registry) resolveAssignedValue(module *ModuleDefinition, assignment *ValueAssignment, chain []valueKey) (Value, error) {
+	key := valueKey{module: module, name: assignment.ValueReference.Name()}
+	for i, visited := range chain {
+		if visited == key {
+			return nil, &valueCycleError{cycle: append(chain[i:len(chain):len(chain)], key)}
+		}
+	}
+	value, err := r.ResolveValueNotation(module, assignment.Type, assignment.Value)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveValue(module, value, append(chain, key))
+}
+
+// resolveObjectIdentifier replaces leading reference to other OBJECT IDENTIFIER with its components
+// and numbers given by references with their values
+func (r *Registry) resolveObjectIdentifier(module *ModuleDefinition, oid ObjectIdentifierValue, chain []valueKey) (Value, error) {
+	res := make(ObjectIdentifierValue, 0, len(oid))
+	for i, component := range oid {
+		var ref *DefinedValue
+		switch c := component.(type) {
+		case DefinedValue:
+			ref = &c
+		case ObjectIdElement:
+			if c.Reference != nil {
+				number, err := r.resolveValue(module, *c.Reference, chain)
+				if err != nil {
+					return nil, err
+				}
+				n, ok := number.(Number)
+				if !ok {
+					return nil, fmt.Errorf("value %v of OID component %v is not a number", c.Reference, c.Name)
+				}
+				res = append(res, ObjectIdElement{Name: c.Name, Id: n.IntValue()})
+				continue
+			}
+			if c.NameForm {
+				// name form is either a reference to value or an arc name like iso
+				name := DefinedValue{ValueReference: ValueReference(c.Name)}
+				if _, _, err := r.lookupValueAssignment(module, name); err != nil {
+					break
+				} else if i == 0 {
+					ref = &name
+					break
+				}
+				number, err := r.resolveValue(module, name, chain)
+				if err != nil {
+					return nil, err
+				}
+				n, ok := number.(Number)
+				if !ok {
+					return nil, fmt.Errorf("value %v of OID component is not a number", c.Name)
+				}
+				res = append(res, ObjectIdElement{Name: c.Name, Id: n.IntValue()})
+				continue
+			}
+		case ObjectIdentifierValue:
+			nested, err := r.resolveObjectIdentifier(module, c, chain)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, nested.(ObjectIdentifierValue)...)
+			continue
+		}
+		if ref == nil {
+			res = append(res, component)
+			continue
+		}
+		if i != 0 {
+			return nil, fmt.Errorf("reference %v is allowed only as the first OID component", ref)
+		}
+		value, err := r.resolveValue(module, *ref, chain)
+		if err != nil {
+			return nil, err
+		}
+		prefix, ok := value.(ObjectIdentifierValue)
+		if !ok {
+			return nil, fmt.Errorf("value %v is not an OBJECT IDENTIFIER", ref)
+		}
+		res = append(res, prefix...)
+	}
+	return res, nil
+}
+
+// ResolveValueNotation gives meaning to value notation which depends on type t governing value used in module:
+// BracedValue becomes value of SEQUENCE, SET, SEQUENCE OF, SET OF, OBJECT IDENTIFIER, BIT STRING, REAL or
+// character string type, components of SEQUENCE, SET and CHOICE values are resolved with their types.
+// Values of other types are returned as is.
+func (r *Registry) ResolveValueNotation(module *ModuleDefinition, t Type, value Value) (Value, error) {
+	return valueResolver{registry: r}.resolve(module, t, value)
+}
+
+// valueNotation resolves value notation with type given in place, values governed by type references and values
+// that don't match their types are left for Registry.ResolveValueNotation
+func valueNotation(t Type, value Value) Value {
+	if resolved, err := (valueResolver{inPlace: true}).resolve(nil, t, value); err == nil {
+		return resolved
+	}
+	return value
+}
+
+// valueResolver resolves value notation by type governing it. Value notation is resolved in place while modules
+// are parsed, then types can't be looked up: values governed by type references are left as is and COMPONENTS OF
+// are not expanded. Otherwise types are looked up in registry.
+type valueResolver struct {
+	registry *Registry
+	inPlace  bool
+}
+
+func (resolver valueResolver) resolve(module *ModuleDefinition, t Type, value Value) (Value, error) {
+	switch tt := t.(type) {
+	case TaggedType:
+		return resolver.resolve(module, tt.Type, value)
+	case ConstraintedType:
+		return resolver.resolve(module, tt.Type, value)
+	case TypeReference:
+		if resolver.inPlace {
+			return value, nil
+		}
+		if assignment := module.ModuleBody.AssignmentList.GetType(tt.Name()); assignment != nil {
+			return resolver.resolve(module, assignment.Type, value)
+		}
+		imported, err := resolver.registry.ResolveSymbol(module, tt.Name())
+		if err != nil {
+			return value, err
+		}
+		if imported != nil {
+			if assignment, ok := imported.Assignment.(TypeAssignment); ok {
+				return resolver.resolve(imported.Module, assignment.Type, value)
+			}
+		}
+		// useful types are not defined in modules
+		return value, nil
+	case ExternalTypeReference:
+		if resolver.inPlace {
+			return value, nil
+		}
+		source, assignment, err := resolver.registry.ResolveExternalType(module, tt)
+		if err != nil {
+			return value, err
+		}
+		return resolver.resolve(source, assignment.Type, value)
+	case ObjectIdentifierType:
+		return objectIdentifierNotation(value)
+	case SequenceType:
+		if !resolver.inPlace {
+			expanded, err := resolver.registry.expandComponentsOf(module, tt, false, nil)
+			if err != nil {
+				return value, err
+			}
+			tt = expanded
+		}
+		return resolver.resolveSequenceValue(module, tt, value)
+	case SetType:
+		if !resolver.inPlace {
+			expanded, err := resolver.registry.expandComponentsOf(module, SequenceType(tt), true, nil)
+			if err != nil {
+				return value, err
+			}
+			tt = SetType(expanded)
+		}
+		return resolver.resolveSequenceValue(module, SequenceType(tt), value)
+	case SequenceOfType:
+		return resolver.resolveSequenceOfValue(module, tt.Type, value)
+	case SetOfType:
+		return resolver.resolveSequenceOfValue(module, tt.Type, value)
+	case ChoiceType:
+		choice, ok := value.(ChoiceValue)
+		if !ok {
+			return value, nil
+		}
+		for _, alternative := range tt.Alternatives() {
+			if alternative.Identifier == choice.Identifier {
+				resolved, err := resolver.resolve(module, alternative.Type, choice.Value)
+				return ChoiceValue{Identifier: choice.Identifier, Value: resolved}, err
+			}
+		}
+		return value, fmt.Errorf("CHOICE has no alternative %v", choice.Identifier)
+	case BitStringType:
+		braced, ok := value.(BracedValue)
+		if !ok {
+			return value, nil
+		} else if len(braced) == 0 {
+			return BitString{}, nil
+		}
+		bits := make(NamedBitListValue, 0, len(braced))
+		for _, item := range braced {
+			name, ok := item[0].(IdentifiedIntegerValue)
+			if len(item) != 1 || !ok {
+				return value, fmt.Errorf("expected identifier of bit, got %v", item)
+			}
+			bits = append(bits, Identifier(name.Name))
+		}
+		return bits, nil
+	case RealType:
+		return realNotation(value)
+	case CharacterStringType, RestrictedStringType:
+		return characterStringNotation(value)
+	}
+	return value, nil
+}
+
+// objectIdentifierNotation converts components of OBJECT IDENTIFIER parsed as BracedValue
+func objectIdentifierNotation(value Value) (Value, error) {
+	braced, ok := value.(BracedValue)
+	if !ok {
+		return value, nil
+	} else if len(braced) != 1 {
+		return value, fmt.Errorf("OBJECT IDENTIFIER value %v has no components or is separated by commas", value)
+	}
+	oid := make(ObjectIdentifierValue, 0, len(braced[0]))
+	for _, element := range braced[0] {
+		switch v := element.(type) {
+		case IdentifiedIntegerValue:
+			oid = append(oid, ObjectIdElement{Name: v.Name, NameForm: true})
+		case Number:
+			oid = append(oid, ObjectIdElement{Id: v.IntValue()})
+		case ObjectIdentifierValue:
+			oid = append(oid, v...)
+		case DefinedValue:
+			oid = append(oid, v)
+		default:
+			return value, fmt.Errorf("unexpected OBJECT IDENTIFIER component %v", element)
+		}
+	}
+	return oid, nil
+}
+
+// namedValues converts items of BracedValue given as identifier followed by value
+func namedValues(braced BracedValue) ([]NamedValue, error) {
+	res := make([]NamedValue, 0, len(braced))
+	for _, item := range braced {
+		name, ok := item[0].(IdentifiedIntegerValue)
+		if len(item) != 2 || !ok {
+			return nil, fmt.Errorf("expected identifier followed by value, got %v", item)
+		}
+		res = append(res, NamedValue{Name: Identifier(name.Name), Value: item[1]})
+	}
+	return res, nil
+}
+
+func (resolver valueResolver) resolveSequenceValue(module *ModuleDefinition, t SequenceType, value Value) (Value, error) {
+	var components SequenceValue
+	switch v := value.(type) {
+	case BracedValue:
+		named, err := namedValues(v)
+		if err != nil {
+			return value, err
+		}
+		components = named
+	case SequenceValue:
+		components = v
+	default:
+		return value, nil
+	}
+	res := make(SequenceValue, 0, len(components))
+	for _, component := range components {
+		var componentType Type
+		hasComponentsOf := false
+		for _, c := range t.AllComponents() {
+			switch ct := c.(type) {
+			case NamedComponentType:
+				if ct.NamedType.Identifier == component.Name {
+					componentType = ct.NamedType.Type
+				}
+			case ComponentsOfComponentType:
+				hasComponentsOf = true
+			}
+		}
+		if componentType == nil {
+			if !hasComponentsOf {
+				return value, fmt.Errorf("SEQUENCE has no component %v", component.Name)
+			}
+			// component may be included by COMPONENTS OF, which isn't expanded in place
+			res = append(res, component)
+			continue
+		}
+		resolved, err := resolver.resolve(module, componentType, component.Value)
+		if err != nil {
+			return value, err
+		}
+		res = append(res, NamedValue{Name: component.Name, Value: resolved})
+	}
+	return res, nil
+}
+
+func (resolver valueResolver) resolveSequenceOfValue(module *ModuleDefinition, elementType Type, value Value) (Value, error) {
+	var elements SequenceOfValue
+	switch v := value.(type) {
+	case BracedValue:
+		elements = make(SequenceOfValue, 0, len(v))
+		for _, item := range v {
+			switch len(item) {
+			case 1:
+				elements = append(elements, item[0])
+			case 2:
+				// NamedValueList form, identifiers are ignored
+				named, err := namedValues(BracedValue{item})
+				if err != nil {
+					return value, err
+				}
+				elements = append(elements, named[0].Value)
+			default:
+				return value, fmt.Errorf("expected value, got %v", item)
+			}
+		}
+	case SequenceOfValue:
+		elements = v
+	default:
+		return value, nil
+	}
+	res := make(SequenceOfValue, 0, len(elements))
+	for _, element := range elements {
+		resolved, err := resolver.resolve(module, elementType, element)
+		if err != nil {
+			return value, err
+		}
+		res = append(res, resolved)
+	}
+	return res, nil
+}
+
+// realNotation converts REAL value given as SEQUENCE of mantissa, base and exponent
+func realNotation(value Value) (Value, error) {
+	braced, ok := value.(BracedValue)
+	if !ok {
+		return value, nil
+	}
+	named, err := namedValues(braced)
+	if err != nil {
+		return value, err
+	}
+	numbers := map[Identifier]float64{}
+	for _, component := range named {
+		number, ok := component.Value.(Number)
+		if !ok {
+			return value, fmt.Errorf("%v of REAL should be number, got %v", component.Name, component.Value)
+		}
+		numbers[component.Name] = float64(number)
+	}
+	mantissa, hasMantissa := numbers["mantissa"]
+	base, hasBase := numbers["base"]
+	exponent, hasExponent := numbers["exponent"]
+	if len(named) != 3 || !hasMantissa || !hasBase || !hasExponent || (base != 2 && base != 10) {
+		return value, fmt.Errorf("REAL value should have mantissa, base 2 or 10 and exponent, got %v", value)
+	}
+	return Real(mantissa * math.Pow(base, exponent)), nil
+}
+
+// characterStringNotation converts CharacterStringList, Quadruple and Tuple to String, CharacterStringList
+// referencing other values is kept as such
+func characterStringNotation(value Value) (Value, error) {
+	braced, ok := value.(BracedValue)
+	if !ok {
+		return value, nil
+	}
+	if char, ok := characterNotation(braced); ok {
+		return char, nil
+	}
+	list := make(CharacterStringList, 0, len(braced))
+	var concatenated strings.Builder
+	for _, item := range braced {
+		if len(item) != 1 {
+			return value, fmt.Errorf("expected string, got %v", item)
+		}
+		element := item[0]
+		if nested, ok := element.(BracedValue); ok {
+			if element, ok = characterNotation(nested); !ok {
+				return value, fmt.Errorf("expected Quadruple or Tuple, got %v", nested)
+			}
+		}
+		switch v := element.(type) {
+		case String:
+			concatenated.WriteString(string(v))
+		case IdentifiedIntegerValue, DefinedValue:
+			// references are resolved when value is used
+		default:
+			return value, fmt.Errorf("expected string, got %v", element)
+		}
+		list = append(list, element)
+	}
+	for _, element := range list {
+		if _, ok := element.(String); !ok {
+			return list, nil
+		}
+	}
+	return String(concatenated.String()), nil
+}
+
+// characterNotation converts Quadruple { group, plane, row, cell } and Tuple { table column, table row }
+func characterNotation(braced BracedValue) (String, bool) {
+	numbers := make([]int, 0, len(braced))
+	for _, item := range braced {
+		number, ok := item[0].(Number)
+		if len(item) != 1 || !ok {
+			return "", false
+		}
+		numbers = append(numbers, number.IntValue())
+	}
+	switch len(numbers) {
+	case 4:
+		return String(rune(numbers[0]<<24 | numbers[1]<<16 | numbers[2]<<8 | numbers[3])), true
+	case 2:
+		return String(rune(numbers[0]*16 + numbers[1])), true
+	}
+	return "", false
+}
@@ -0,0 +1,480 @@
+package asn1go
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testRegistry(t *testing.T, content string) *Registry {
+	modules, err := ParseString(content)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	registry := NewRegistry()
+	registry.Add(modules...)
+	return registry
+}
+
+func TestRegistryModules(t *testing.T) {
+	registry := testRegistry(t, `
+		First { iso(1) 2 3 } DEFINITIONS ::= BEGIN END
+		Second { iso 2 4 } DEFINITIONS ::= BEGIN END
+		Dup DEFINITIONS ::= BEGIN END
+		Dup DEFINITIONS ::= BEGIN END
+	`)
+	if m := registry.Module("First"); m == nil || m.ModuleIdentifier.Reference != "First" {
+		t.Errorf("Expected First by name, got %v", m)
+	}
+	if m := registry.Module("Dup"); m != nil {
+		t.Errorf("Expected nil for ambiguous name, got %v", m)
+	}
+	if m := registry.Module("Missing"); m != nil {
+		t.Errorf("Expected nil for missing name, got %v", m)
+	}
+	oid := DefinitiveIdentifier{{Id: 1}, {Id: 2}, {Id: 4}}
+	if m := registry.ModuleByOID(oid); m == nil || m.ModuleIdentifier.Reference != "Second" {
+		t.Errorf("Expected Second by OID, got %v", m)
+	}
+	// OID takes precedence over module name
+	ref := GlobalModuleReference{Reference: "Other", AssignedIdentifier: ObjectIdentifierValue{
+		ObjectIdElement{Name: "iso", Id: 1}, ObjectIdElement{Id: 2}, ObjectIdElement{Id: 3},
+	}}
+	if m, err := registry.ResolveModule(ref); err != nil || m.ModuleIdentifier.Reference != "First" {
+		t.Errorf("Expected First by assigned identifier, got %v, %v", m, err)
+	}
+}
+
+func TestResolveImports(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS ::= BEGIN
+			Foo ::= INTEGER
+			Bar ::= BOOLEAN
+		END
+		Main DEFINITIONS ::= BEGIN
+			IMPORTS Foo FROM Defs;
+			Baz ::= SEQUENCE { foo Foo }
+		END
+	`)
+	if err := registry.ResolveImports(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resolved, err := registry.ResolveSymbol(registry.Module("Main"), "Foo")
+	if err != nil || resolved == nil {
+		t.Fatalf("Expected Foo to be resolved, got %v, %v", resolved, err)
+	}
+	if resolved.Module.ModuleIdentifier.Reference != "Defs" || resolved.Assignment.Reference().Name() != "Foo" {
+		t.Errorf("Expected Defs.Foo, got %v.%v", resolved.Module.ModuleIdentifier.Reference, resolved.Assignment.Reference().Name())
+	}
+	if resolved, err := registry.ResolveSymbol(registry.Module("Main"), "Bar"); resolved != nil || err != nil {
+		t.Errorf("Expected Bar not to be imported, got %v, %v", resolved, err)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	registry := testRegistry(t, `
+		A DEFINITIONS ::= BEGIN
+			IMPORTS X FROM Missing
+				Y FROM B
+				Z FROM B
+				Z FROM C;
+			Y ::= INTEGER
+		END
+		B DEFINITIONS ::= BEGIN
+			IMPORTS T FROM C;
+			Y ::= INTEGER
+			Z ::= INTEGER
+		END
+		C DEFINITIONS ::= BEGIN
+			IMPORTS Y FROM B;
+			T ::= INTEGER
+			Z ::= INTEGER
+		END
+	`)
+	err := registry.ResolveImports()
+	errs, ok := err.(ImportErrorList)
+	if !ok {
+		t.Fatalf("Expected ImportErrorList, got %v", err)
+	}
+	expected := []string{
+		"3:12: A: module Missing not found",
+		"4:5: A: Y is both imported from B and defined in module",
+		"6:5: A: Z is imported from both B and C",
+		"15:12: C: import cycle: B -> C -> B",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %v errors, got %v", len(expected), err)
+	}
+	for i, exp := range expected {
+		if got := errs[i].Error(); got != exp {
+			t.Errorf("Expected %q, got %q", exp, got)
+		}
+	}
+	if cycle := errs[3].Cycle; strings.Join(cycle, " ") != "B C B" {
+		t.Errorf("Expected cycle B C B, got %v", cycle)
+	}
+}
+
+func TestImportNotExported(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS ::= BEGIN
+			EXPORTS Foo;
+			Foo ::= INTEGER
+			Bar ::= BOOLEAN
+		END
+		Main DEFINITIONS ::= BEGIN
+			IMPORTS Foo, Bar FROM Defs;
+			Baz ::= SEQUENCE { foo Foo, bar Bar }
+		END
+	`)
+	exp := "8:12: Main: module Defs does not export Bar"
+	if err := registry.ResolveImports(); err == nil || err.Error() != exp {
+		t.Errorf("Expected error %q, got %v", exp, err)
+	}
+	if _, err := registry.ResolveSymbol(registry.Module("Main"), "Bar"); err == nil {
+		t.Error("Expected error resolving symbol that is not exported")
+	}
+	if resolved, err := registry.ResolveSymbol(registry.Module("Main"), "Foo"); err != nil || resolved == nil {
+		t.Errorf("Expected exported symbol to be resolved, got %v, %v", resolved, err)
+	}
+}
+
+func TestGenerateImportedType(t *testing.T) {
+	compiler := NewCompiler()
+	modules, err := compiler.ParseString(`
+		Defs DEFINITIONS ::= BEGIN Foo ::= SEQUENCE OF INTEGER END
+		Main DEFINITIONS ::= BEGIN IMPORTS Foo FROM Defs; Bar ::= SEQUENCE { foo Foo } END
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exp := "Foo Defs.Foo "; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+	}
+}
+
+func TestGenerateImportedInlineType(t *testing.T) {
+	compiler := NewCompiler()
+	modules, err := compiler.ParseString(`
+		Defs DEFINITIONS ::= BEGIN Foo ::= SEQUENCE { body SEQUENCE { x INTEGER } } END
+		Main DEFINITIONS ::= BEGIN IMPORTS Foo FROM Defs; foo Foo ::= { body { x 1 } } END
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exp := "var Foo = Defs.Foo{Body: Defs.Foo_Body{X: 1}}"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+	}
+}
+
+func TestGenerateExternalType(t *testing.T) {
+	compiler := NewCompiler()
+	modules, err := compiler.ParseString(`
+		Defs DEFINITIONS ::= BEGIN EXPORTS Foo; Foo ::= SEQUENCE OF INTEGER Hidden ::= INTEGER END
+		Main DEFINITIONS ::= BEGIN Bar ::= SEQUENCE { foo Defs.Foo, baz Main.Baz } Baz ::= INTEGER END
+		Bad DEFINITIONS ::= BEGIN Bar ::= SEQUENCE { hidden Defs.Hidden, missing Nowhere.Foo } END
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, exp := range []string{"import \"Defs\"", "Foo\tDefs.Foo\t", "Baz\tBaz\t"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := compiler.NewCodeGenerator(GenParams{ImportPrefix: "example.com/gen"}).Generate(modules[1], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, exp := range []string{"import \"example.com/gen/Defs\"", "Foo\tDefs.Foo\t"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+		}
+	}
+	err = compiler.NewCodeGenerator(GenParams{}).Generate(modules[2], &bytes.Buffer{})
+	if err == nil {
+		t.Fatalf("Expected errors for unresolvable references")
+	}
+	for _, exp := range []string{"module Defs does not export Hidden", "Nowhere"} {
+		if !strings.Contains(err.Error(), exp) {
+			t.Errorf("Expected %q in error, got: %v", exp, err)
+		}
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS ::= BEGIN
+			EXPORTS id-arc, ub-name;
+			id-arc OBJECT IDENTIFIER ::= { iso(1) identified-organization(3) 6 }
+			ub-name INTEGER ::= 64
+			hidden INTEGER ::= 1
+		END
+		Main DEFINITIONS ::= BEGIN
+			IMPORTS id-arc, ub-name FROM Defs;
+			max INTEGER ::= ub-name
+			id-main OBJECT IDENTIFIER ::= { id-arc 1 }
+			id-sub OBJECT IDENTIFIER ::= { id-main sub(max) }
+			loop-a INTEGER ::= loop-b
+			loop-b INTEGER ::= loop-a
+			external INTEGER ::= Defs.ub-name
+			Bounded ::= INTEGER (0..Defs.ub-name)
+		END
+	`)
+	main := registry.Module("Main")
+	bound := firstConstraintElements(main.ModuleBody.AssignmentList.GetType("Bounded").Type.(ConstraintedType)).(ValueRange).UpperEndpoint.Value
+	for _, tc := range []struct {
+		value    Value
+		expected string
+	}{
+		{DefinedValue{ValueReference: "max"}, "64"},
+		{IdentifiedIntegerValue{Name: "max"}, "64"},
+		{IdentifiedIntegerValue{Name: "named-number"}, "{<nil> named-number}"},
+		{DefinedValue{ModuleReference: "Defs", ValueReference: "ub-name"}, "64"},
+		{main.ModuleBody.AssignmentList.GetValue("external").Value, "64"},
+		{bound, "64"},
+		{DefinedValue{ValueReference: "id-sub"}, "[{iso 1 <nil> false} {identified-organization 3 <nil> false} { 6 <nil> false} { 1 <nil> false} {sub 64 <nil> false}]"},
+		{Number(5), "5"},
+	} {
+		got, err := registry.ResolveValue(main, tc.value)
+		if err != nil {
+			t.Errorf("Unexpected error resolving %v: %v", tc.value, err)
+		} else if fmt.Sprint(got) != tc.expected {
+			t.Errorf("Expected %v to resolve to %v, got %v", tc.value, tc.expected, got)
+		}
+	}
+	for _, tc := range []struct {
+		value    Value
+		expected string
+	}{
+		{DefinedValue{ValueReference: "missing"}, "value missing is not defined"},
+		{DefinedValue{ModuleReference: "Defs", ValueReference: "hidden"}, "module Defs does not export hidden"},
+		{DefinedValue{ValueReference: "loop-a"}, "value reference cycle: loop-a -> loop-b -> loop-a"},
+	} {
+		if _, err := registry.ResolveValue(main, tc.value); err == nil || err.Error() != tc.expected {
+			t.Errorf("Expected error %q resolving %v, got %v", tc.expected, tc.value, err)
+		}
+	}
+}
+
+func TestGenerateObjectIdentifiers(t *testing.T) {
+	compiler := NewCompiler()
+	modules, err := compiler.ParseString(`
+		Defs DEFINITIONS ::= BEGIN
+			EXPORTS id-arc, ub-arc;
+			id-arc OBJECT IDENTIFIER ::= { iso member-body 840 }
+			ub-arc INTEGER ::= 113549
+		END
+		Main DEFINITIONS ::= BEGIN
+			IMPORTS id-arc, ub-arc FROM Defs;
+			id-main OBJECT IDENTIFIER ::= { id-arc rsadsi(ub-arc) 1 }
+			id-alias OBJECT IDENTIFIER ::= id-main
+			id-itu OBJECT IDENTIFIER ::= { itu-t recommendation x(24) }
+			Algorithm ::= SEQUENCE { algorithm OBJECT IDENTIFIER, alias Name }
+			Name ::= OBJECT IDENTIFIER
+		END
+		Bad DEFINITIONS ::= BEGIN id-bad OBJECT IDENTIFIER ::= { nowhere 1 } END
+		BadSub DEFINITIONS ::= BEGIN id-bad OBJECT IDENTIFIER ::= { iso foo 1 } END
+		BadName DEFINITIONS ::= BEGIN id-bad OBJECT IDENTIFIER ::= { iso member-body us 113549 } END
+		BadJoint DEFINITIONS ::= BEGIN id-bad OBJECT IDENTIFIER ::= { joint-iso-itu-t ds 5 } END
+		Named DEFINITIONS ::= BEGIN
+			us INTEGER ::= 840
+			id-named OBJECT IDENTIFIER ::= { iso member-body us 113549 }
+		END
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, exp := range []string{
+		"var IdMain = asn1.ObjectIdentifier{1, 2, 840, 113549, 1}\n",
+		"var IdAlias = asn1.ObjectIdentifier{1, 2, 840, 113549, 1}\n",
+		"var IdItu = asn1.ObjectIdentifier{0, 0, 24}\n",
+		"Algorithm\tasn1.ObjectIdentifier\t",
+		"Alias\t\tasn1.ObjectIdentifier\t",
+		"type Name asn1.ObjectIdentifier\n",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+		}
+	}
+	for i, arc := range []string{"nowhere", "foo", "us", "ds"} {
+		err = compiler.NewCodeGenerator(GenParams{}).Generate(modules[2+i], &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), "value id-bad: unknown OID arc "+arc) {
+			t.Errorf("Expected error for unknown arc %v, got: %v", arc, err)
+		}
+	}
+	buf.Reset()
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(modules[6], buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if exp := "var IdNamed = asn1.ObjectIdentifier{1, 2, 840, 113549}\n"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+	}
+}
+
+func TestResolveValueNotation(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS ::= BEGIN
+			EXPORTS Point, Id;
+			Point ::= SEQUENCE { x INTEGER, tags SEQUENCE OF Id }
+			Id ::= OBJECT IDENTIFIER
+		END
+		Main DEFINITIONS ::= BEGIN
+			IMPORTS Point, Id FROM Defs;
+			Shape ::= CHOICE { point Point, id Defs.Id }
+			origin Point ::= { x 0, tags { { iso 1 }, { 2 5 } } }
+			shape Shape ::= id : { joint-iso-itu-t 5 }
+			id-origin Id ::= { iso 3 }
+			id-sub OBJECT IDENTIFIER ::= { id-origin 1 }
+		END
+	`)
+	main := registry.Module("Main")
+	for _, tc := range []struct {
+		t        Type
+		value    Value
+		expected string
+	}{
+		{TypeReference("Point"), main.ModuleBody.AssignmentList.GetValue("origin").Value, "[{x 0} {tags [[{iso 0 <nil> true} { 1 <nil> false}] [{ 2 <nil> false} { 5 <nil> false}]]}]"},
+		{TypeReference("Shape"), main.ModuleBody.AssignmentList.GetValue("shape").Value, "{id [{joint-iso-itu-t 0 <nil> true} { 5 <nil> false}]}"},
+		{TypeReference("GeneralizedTime"), BracedValue{}, "[]"},
+	} {
+		got, err := registry.ResolveValueNotation(main, tc.t, tc.value)
+		if err != nil {
+			t.Errorf("Unexpected error resolving %v: %v", tc.value, err)
+		} else if fmt.Sprint(got) != tc.expected {
+			t.Errorf("Expected %v to resolve to %v, got %v", tc.value, tc.expected, got)
+		}
+	}
+	if got, err := registry.ResolveValue(main, DefinedValue{ValueReference: "id-sub"}); err != nil || fmt.Sprint(got) != "[{iso 0 <nil> true} { 3 <nil> false} { 1 <nil> false}]" {
+		t.Errorf("Expected OID given with type reference to be resolved, got %v, %v", got, err)
+	}
+	for _, tc := range []struct {
+		t        Type
+		value    Value
+		expected string
+	}{
+		{TypeReference("Point"), BracedValue{{IdentifiedIntegerValue{Name: "z"}, Number(1)}}, "SEQUENCE has no component z"},
+		{TypeReference("Point"), BracedValue{{Number(1)}}, "expected identifier followed by value, got [1]"},
+		{TypeReference("Shape"), ChoiceValue{Identifier: "line", Value: NullValue{}}, "CHOICE has no alternative line"},
+		{TypeReference("Id"), BracedValue{{Number(1)}, {Number(2)}}, "OBJECT IDENTIFIER value [[1] [2]] has no components or is separated by commas"},
+		{ExternalTypeReference{ModuleReference: "Nowhere", TypeReference: "Point"}, BracedValue{}, "Nowhere"},
+	} {
+		if _, err := registry.ResolveValueNotation(main, tc.t, tc.value); err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("Expected error %q resolving %v, got %v", tc.expected, tc.value, err)
+		}
+	}
+}
+
+func TestExpandComponentsOf(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+			Base ::= SEQUENCE { id INTEGER, name UTF8String, ..., extra BOOLEAN }
+			Plain ::= SET { flag BOOLEAN }
+		END
+		Main DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+			IMPORTS Base, Plain FROM Defs;
+			Derived ::= SEQUENCE { first INTEGER, COMPONENTS OF Base, last Base }
+			Manual ::= SEQUENCE { first [5] INTEGER, COMPONENTS OF Base }
+			Nested ::= SEQUENCE { COMPONENTS OF Derived, ..., COMPONENTS OF Base }
+			WrongKind ::= SEQUENCE { COMPONENTS OF Plain }
+			NotComposite ::= SEQUENCE { COMPONENTS OF INTEGER }
+			Self ::= SEQUENCE { a INTEGER, COMPONENTS OF Self }
+		END
+	`)
+	module := registry.Module("Main")
+	describe := func(t Type) string {
+		var names []string
+		for _, c := range t.(SequenceType).AllComponents() {
+			named := c.(NamedComponentType)
+			tag := "-"
+			if tagged, ok := named.NamedType.Type.(TaggedType); ok {
+				tag = fmt.Sprint(tagged.Tag.ClassNumber)
+			}
+			names = append(names, fmt.Sprintf("%v:%v", named.NamedType.Identifier, tag))
+		}
+		return strings.Join(names, " ")
+	}
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"Derived", "first:0 id:1 name:2 last:3"},
+		{"Manual", "first:5 id:- name:-"},
+		{"Nested", "first:0 id:1 name:2 last:3 id:4 name:5"},
+	} {
+		expanded, err := registry.ExpandComponentsOf(module, module.ModuleBody.AssignmentList.GetType(tc.name).Type)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+		} else if got := describe(expanded); got != tc.expected {
+			t.Errorf("%v: expected %q, got %q", tc.name, tc.expected, got)
+		}
+	}
+	// included references to types of other module are qualified
+	expanded, _ := registry.ExpandComponentsOf(module, module.ModuleBody.AssignmentList.GetType("Derived").Type)
+	last := expanded.(SequenceType).Components[3].(NamedComponentType).NamedType.Type.(TaggedType).Type
+	if last != TypeReference("Base") {
+		t.Errorf("Expected own reference to be kept, got %v", last)
+	}
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{"WrongKind", "does not reference SEQUENCE type"},
+		{"NotComposite", "does not reference SEQUENCE type"},
+		{"Self", "includes itself"},
+	} {
+		_, err := registry.ExpandComponentsOf(module, module.ModuleBody.AssignmentList.GetType(tc.name).Type)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%v: expected error %q, got %v", tc.name, tc.expected, err)
+		}
+	}
+}
+
+func TestGenerateComponentsOf(t *testing.T) {
+	compiler := NewCompiler()
+	modules, err := compiler.ParseString(`
+		Defs DEFINITIONS IMPLICIT TAGS ::= BEGIN
+			Id ::= INTEGER
+			Base ::= SEQUENCE { id Id, name [1] UTF8String }
+		END
+		Main DEFINITIONS EXPLICIT TAGS ::= BEGIN
+			IMPORTS Base FROM Defs;
+			Derived ::= SEQUENCE { first BOOLEAN, COMPONENTS OF Base }
+			Bad ::= SET { COMPONENTS OF Base }
+		END
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	main := modules[1]
+	main.ModuleBody.AssignmentList = main.ModuleBody.AssignmentList[:1]
+	buf := &bytes.Buffer{}
+	if err := compiler.NewCodeGenerator(GenParams{}).Generate(main, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, exp := range []string{"First\tbool\t", "Id\tDefs.Id\t", "asn1:\"explicit,tag:1,utf8\""} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("Expected %q in output, got:\n%v", exp, buf.String())
+		}
+	}
+	err = compiler.NewCodeGenerator(GenParams{}).Generate(modules[1], &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "does not reference SET type") {
+		t.Errorf("Expected error for COMPONENTS OF SEQUENCE in SET, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package asn1go
+
+// applyAutomaticTags assigns context-specific tags to components of SEQUENCE and SET and to alternatives of CHOICE
+// in modules with AUTOMATIC TAGS, as X.680 24.7 and 28.3 define. Tags are assigned only if none of the root
+// components or alternatives is tagged, root components are numbered first, then extension additions in order.
+// Extension additions tagged in module keep their tags, though their positions are numbered too.
+// Tags are added as TaggedType without IMPLICIT or EXPLICIT keyword, so tags of CHOICE and open types are
+// explicit by the same rule as for tags written in module. Components included by COMPONENTS OF are numbered
+// when it is expanded, see Registry.ExpandComponentsOf.
+func applyAutomaticTags(modules []ModuleDefinition) {
+	for i := range modules {
+		module := &modules[i]
+		if module.TagDefault != TAGS_AUTOMATIC {
+			continue
+		}
+		for j, assignment := range module.ModuleBody.AssignmentList {
+			switch x := assignment.(type) {
+			case TypeAssignment:
+				x.Type = automaticTags(x.Type)
+				module.ModuleBody.AssignmentList[j] = x
+			case ValueAssignment:
+				x.Type = automaticTags(x.Type)
+				module.ModuleBody.AssignmentList[j] = x
+			}
+		}
+	}
+}
+
+// automaticTags assigns tags to components of t and of types nested in it
+func automaticTags(t Type) Type {
+	switch x := t.(type) {
+	case SequenceType:
+		return automaticComponentTags(x)
+	case SetType:
+		return SetType(automaticComponentTags(SequenceType(x)))
+	case ChoiceType:
+		return automaticAlternativeTags(x)
+	case TaggedType:
+		x.Type = automaticTags(x.Type)
+		return x
+	case ConstraintedType:
+		x.Type = automaticTags(x.Type)
+		return x
+	case SequenceOfType:
+		x.Type = automaticTags(x.Type)
+		return x
+	case SetOfType:
+		x.Type = automaticTags(x.Type)
+		return x
+	}
+	return t
+}
+
+func automaticComponentTags(t SequenceType) SequenceType {
+	tagger := automaticTagger{apply: !hasTaggedComponent(t.Components) && !hasTaggedComponent(t.TrailingRootComponents)}
+	tagger.components(t.Components)
+	tagger.components(t.TrailingRootComponents)
+	for i, addition := range t.ExtensionAdditions {
+		switch x := addition.(type) {
+		case NamedComponentType:
+			t.ExtensionAdditions[i] = tagger.components(ComponentTypeList{x})[0].(NamedComponentType)
+		case ExtensionAdditionGroup:
+			x.Components = tagger.components(x.Components)
+			t.ExtensionAdditions[i] = x
+		}
+	}
+	return t
+}
+
+func automaticAlternativeTags(t ChoiceType) ChoiceType {
+	tagger := automaticTagger{apply: true}
+	for _, alternative := range t.AlternativeTypeList {
+		if isTagged(alternative.Type) {
+			tagger.apply = false
+		}
+	}
+	tagger.namedTypes(t.AlternativeTypeList)
+	for i, extension := range t.ExtensionTypes {
+		switch x := extension.(type) {
+		case NamedType:
+			t.ExtensionTypes[i] = tagger.namedTypes([]NamedType{x})[0]
+		case ExtensionAdditionAlternativesGroup:
+			x.AlternativeTypeList = tagger.namedTypes(x.AlternativeTypeList)
+			t.ExtensionTypes[i] = x
+		}
+	}
+	return t
+}
+
+func hasTaggedComponent(components ComponentTypeList) bool {
+	for _, component := range components {
+		if c, ok := component.(NamedComponentType); ok && isTagged(c.NamedType.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTagged tells if t is tagged in module, tags assigned by automatic tagging don't count
+func isTagged(t Type) bool {
+	tagged, ok := t.(TaggedType)
+	return ok && !tagged.Automatic
+}
+
+func untagAutomatic(t Type) Type {
+	if tagged, ok := t.(TaggedType); ok && tagged.Automatic {
+		return tagged.Type
+	}
+	return t
+}
+
+// retagComponents numbers automatically tagged components of t again, after COMPONENTS OF are expanded. Types of
+// components are tagged already, so they aren't visited. Lists of t are modified in place.
+func retagComponents(t SequenceType) SequenceType {
+	tagger := automaticTagger{apply: true}
+	tagger.retag(t.Components)
+	tagger.retag(t.TrailingRootComponents)
+	for i, addition := range t.ExtensionAdditions {
+		switch x := addition.(type) {
+		case NamedComponentType:
+			t.ExtensionAdditions[i] = tagger.retag(ComponentTypeList{x})[0].(NamedComponentType)
+		case ExtensionAdditionGroup:
+			x.Components = tagger.retag(x.Components)
+			t.ExtensionAdditions[i] = x
+		}
+	}
+	return t
+}
+
+// automaticTagger numbers components of a single type, it only tags nested types if apply is false
+type automaticTagger struct {
+	apply bool
+	next  int
+}
+
+func (a *automaticTagger) components(components ComponentTypeList) ComponentTypeList {
+	for i, component := range components {
+		if c, ok := component.(NamedComponentType); ok {
+			c.NamedType = a.namedTypes([]NamedType{c.NamedType})[0]
+			components[i] = c
+		}
+	}
+	return components
+}
+
+func (a *automaticTagger) namedTypes(types []NamedType) []NamedType {
+	for i, t := range types {
+		t.Type = a.tag(automaticTags(t.Type))
+		types[i] = t
+	}
+	return types
+}
+
+func (a *automaticTagger) retag(components ComponentTypeList) ComponentTypeList {
+	for i, component := range components {
+		if c, ok := component.(NamedComponentType); ok {
+			c.NamedType.Type = a.tag(untagAutomatic(c.NamedType.Type))
+			components[i] = c
+		}
+	}
+	return components
+}
+
+func (a *automaticTagger) tag(t Type) Type {
+	if !a.apply {
+		return t
+	}
+	a.next++
+	if isTagged(t) {
+		// only extension additions may be tagged when tags are applied, they keep their tags
+		return t
+	}
+	return TaggedType{Tag: Tag{Class: CLASS_CONTEXT_SPECIFIC, ClassNumber: Number(a.next - 1)}, Type: t, TagType: TAGS_IMPLICIT, Automatic: true}
+}
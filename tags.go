@@ -0,0 +1,232 @@
+package asn1go
+
+import (
+	"fmt"
+)
+
+// EffectiveTag is outermost tag of a type as it's encoded
+type EffectiveTag struct {
+	Class   int // one of CLASS_*
+	Number  int
+	TagType int // TAGS_EXPLICIT or TAGS_IMPLICIT, UNIVERSAL tags of builtin types are TAGS_IMPLICIT
+}
+
+func (t EffectiveTag) String() string {
+	switch t.Class {
+	case CLASS_UNIVERSAL:
+		return fmt.Sprintf("[UNIVERSAL %d]", t.Number)
+	case CLASS_APPLICATION:
+		return fmt.Sprintf("[APPLICATION %d]", t.Number)
+	case CLASS_PRIVATE:
+		return fmt.Sprintf("[PRIVATE %d]", t.Number)
+	}
+	return fmt.Sprintf("[%d]", t.Number)
+}
+
+// Is tells if tags have the same class and number
+func (t EffectiveTag) Is(other EffectiveTag) bool {
+	return t.Class == other.Class && t.Number == other.Number
+}
+
+// universalTags are tags of builtin types, X.680 8.6
+var universalTags = map[int]int{
+	BMPString:       30,
+	GeneralString:   27,
+	GraphicString:   25,
+	IA5String:       22,
+	ISO646String:    26,
+	NumericString:   18,
+	PrintableString: 19,
+	TeletexString:   20,
+	T61String:       20,
+	UniversalString: 28,
+	UTF8String:      12,
+	VideotexString:  21,
+	VisibleString:   26,
+}
+
+// EffectiveTags returns outermost tags of t defined in module. Type references are followed, constraints are
+// skipped, and builtin types have their UNIVERSAL tags. Result is a single tag unless t is untagged CHOICE,
+// which has tags of all its alternatives. Tag is explicit if it's given by EXPLICIT keyword or module TagDefault,
+// tags of untagged CHOICE and open types are always explicit (X.680 31.2.7). Open types, recursive untagged
+// CHOICE and references or tag numbers which can't be resolved have no effective tags and yield an error.
+func (r *Registry) EffectiveTags(module *ModuleDefinition, t Type) ([]EffectiveTag, error) {
+	return r.effectiveTags(module, t, nil)
+}
+
+func (r *Registry) effectiveTags(module *ModuleDefinition, t Type, chain []string) ([]EffectiveTag, error) {
+	module, resolved, chain, err := r.resolveUntagged(module, t, chain)
+	if err != nil {
+		return nil, err
+	}
+	universal := func(number int) ([]EffectiveTag, error) {
+		return []EffectiveTag{{Class: CLASS_UNIVERSAL, Number: number, TagType: TAGS_IMPLICIT}}, nil
+	}
+	switch tt := resolved.(type) {
+	case TaggedType:
+		number, err := r.ResolveValue(module, tt.Tag.ClassNumber)
+		if err != nil {
+			return nil, err
+		}
+		n, ok := number.(Number)
+		if !ok {
+			return nil, fmt.Errorf("tag number %v is not a number", tt.Tag.ClassNumber)
+		}
+		tag := EffectiveTag{Class: tt.Tag.Class, Number: n.IntValue(), TagType: TAGS_IMPLICIT}
+		if r.isExplicitTag(module, tt) {
+			tag.TagType = TAGS_EXPLICIT
+		}
+		return []EffectiveTag{tag}, nil
+	case ChoiceType:
+		var res []EffectiveTag
+		for _, alternative := range tt.Alternatives() {
+			tags, err := r.effectiveTags(module, alternative.Type, chain)
+			if err != nil {
+				return nil, fmt.Errorf("alternative %v: %w", alternative.Identifier, err)
+			}
+			res = append(res, tags...)
+		}
+		return res, nil
+	case TypeReference:
+		if tt.Name() == UTCTimeName {
+			return universal(23)
+		}
+		return nil, fmt.Errorf("open type %v has no effective tag", tt)
+	case BooleanType:
+		return universal(1)
+	case IntegerType, BigInt:
+		return universal(2)
+	case BitStringType:
+		return universal(3)
+	case OctetStringType:
+		return universal(4)
+	case NullType:
+		return universal(5)
+	case ObjectIdentifierType:
+		return universal(6)
+	case RealType:
+		return universal(9)
+	case EnumeratedType:
+		return universal(10)
+	case SequenceType, SequenceOfType:
+		return universal(16)
+	case SetType, SetOfType:
+		return universal(17)
+	case RestrictedStringType:
+		if number, ok := universalTags[tt.LexType]; ok {
+			return universal(number)
+		}
+	case CharacterStringType:
+		return universal(29)
+	case StringType:
+		// StringStore of NCBI specifications is really a VisibleString
+		return universal(26)
+	}
+	return nil, fmt.Errorf("type %v has no effective tag", resolved)
+}
+
+// isExplicitTag tells if tag t written in module is explicit
+func (r *Registry) isExplicitTag(module *ModuleDefinition, t TaggedType) bool {
+	if t.HasTagType {
+		return t.TagType == TAGS_EXPLICIT
+	} else if module.TagDefault == TAGS_EXPLICIT {
+		return true
+	}
+	_, resolved, _, err := r.resolveUntagged(module, t.Type, nil)
+	if err != nil {
+		return false
+	}
+	switch tt := resolved.(type) {
+	case ChoiceType:
+		return true
+	case TypeReference:
+		return tt.Name() == "ANY"
+	}
+	return false
+}
+
+// resolveUntagged follows type references and skips constraints of t defined in module until it reaches tagged
+// or builtin type, returns it together with module defining it. Names of useful types, which aren't defined in
+// modules, and open type ANY are returned as TypeReference. chain holds references resolved so far, it's returned
+// with references followed by resolveUntagged added.
+func (r *Registry) resolveUntagged(module *ModuleDefinition, t Type, chain []string) (*ModuleDefinition, Type, []string, error) {
+	for {
+		switch tt := t.(type) {
+		case ConstraintedType:
+			t = tt.Type
+		case TypeReference:
+			key := module.ModuleIdentifier.Reference + "." + tt.Name()
+			for _, visited := range chain {
+				if visited == key {
+					return nil, nil, nil, fmt.Errorf("type %v is recursive without tag", tt)
+				}
+			}
+			chain = append(chain, key)
+			if assignment := module.ModuleBody.AssignmentList.GetType(tt.Name()); assignment != nil {
+				t = assignment.Type
+				continue
+			}
+			imported, err := r.ResolveSymbol(module, tt.Name())
+			if err != nil {
+				return nil, nil, nil, importError{err}
+			}
+			if imported != nil {
+				assignment, ok := imported.Assignment.(TypeAssignment)
+				if !ok {
+					return nil, nil, nil, fmt.Errorf("%v is not a type", tt)
+				}
+				module, t = imported.Module, assignment.Type
+				continue
+			}
+			if useful, ok := builtinUsefulTypes()[tt.Name()]; ok {
+				return module, useful, chain, nil
+			}
+			if tt.Name() == UTCTimeName || tt.Name() == "ANY" {
+				return module, tt, chain, nil
+			}
+			return nil, nil, nil, fmt.Errorf("type %v is not defined", tt)
+		case ExternalTypeReference:
+			source, assignment, err := r.ResolveExternalType(module, tt)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			module, t = source, assignment.TypeReference
+		default:
+			return module, t, chain, nil
+		}
+	}
+}
+
+// importError is error of resolving imported symbol, Registry.ResolveImports reports such errors
+type importError struct {
+	error
+}
+
+func (e importError) Unwrap() error {
+	return e.error
+}
+
+// withoutConstraints returns t with constraints removed
+func withoutConstraints(t Type) Type {
+	for {
+		constrained, ok := t.(ConstraintedType)
+		if !ok {
+			return t
+		}
+		t = constrained.Type
+	}
+}
+
+// withoutTags returns t with tags and constraints removed, references aren't followed
+func withoutTags(t Type) Type {
+	for {
+		switch tt := t.(type) {
+		case TaggedType:
+			t = tt.Type
+		case ConstraintedType:
+			t = tt.Type
+		default:
+			return t
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package asn1go
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEffectiveTags(t *testing.T) {
+	registry := testRegistry(t, `
+		Defs DEFINITIONS IMPLICIT TAGS ::= BEGIN
+			Name ::= [APPLICATION 1] UTF8String
+			Choice ::= CHOICE { a INTEGER, b [0] BOOLEAN }
+			Recursive ::= CHOICE { r Recursive, n NULL }
+		END
+		Main DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+			IMPORTS Name, Choice FROM Defs;
+			two INTEGER ::= 2
+			Alias ::= Name
+			Constrained ::= Alias (SIZE (1..10))
+			Implicit ::= [1] INTEGER
+			Explicit ::= [PRIVATE two] EXPLICIT INTEGER
+			TaggedChoice ::= [3] Choice
+			TaggedAny ::= [4] ANY
+			Nested ::= CHOICE { c Choice, s SEQUENCE { x INTEGER } }
+			Undefined ::= [two] Missing
+		END
+		Explicit DEFINITIONS EXPLICIT TAGS ::= BEGIN
+			Tagged ::= [1] INTEGER
+			Time ::= [2] GeneralizedTime
+		END
+	`)
+	for _, tc := range []struct {
+		module   string
+		t        Type
+		expected string
+	}{
+		{"Main", IntegerType{}, "[UNIVERSAL 2] implicit"},
+		{"Main", SequenceOfType{Type: BooleanType{}}, "[UNIVERSAL 16] implicit"},
+		{"Main", RestrictedStringType{LexType: IA5String}, "[UNIVERSAL 22] implicit"},
+		{"Main", TypeReference("GeneralizedTime"), "[UNIVERSAL 24] implicit"},
+		{"Main", TypeReference("Alias"), "[APPLICATION 1] implicit"},
+		{"Main", TypeReference("Constrained"), "[APPLICATION 1] implicit"},
+		{"Main", TypeReference("Implicit"), "[1] implicit"},
+		{"Main", TypeReference("Explicit"), "[PRIVATE 2] explicit"},
+		{"Main", TypeReference("TaggedChoice"), "[3] explicit"},
+		{"Main", TypeReference("TaggedAny"), "[4] explicit"},
+		{"Main", TypeReference("Choice"), "[UNIVERSAL 2] implicit, [0] implicit"},
+		{"Main", TypeReference("Nested"), "[0] explicit, [1] implicit"},
+		// tag is known even if tagged type isn't
+		{"Main", TypeReference("Undefined"), "[2] implicit"},
+		{"Main", ExternalTypeReference{ModuleReference: "Defs", TypeReference: "Name"}, "[APPLICATION 1] implicit"},
+		{"Explicit", TypeReference("Tagged"), "[1] explicit"},
+		{"Explicit", TypeReference("Time"), "[2] explicit"},
+	} {
+		tags, err := registry.EffectiveTags(registry.Module(tc.module), tc.t)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.t, err)
+			continue
+		}
+		var got []string
+		for _, tag := range tags {
+			mode := "implicit"
+			if tag.TagType == TAGS_EXPLICIT {
+				mode = "explicit"
+			}
+			got = append(got, fmt.Sprintf("%v %v", tag, mode))
+		}
+		if strings.Join(got, ", ") != tc.expected {
+			t.Errorf("%v: expected %v, got %v", tc.t, tc.expected, strings.Join(got, ", "))
+		}
+	}
+	for _, tc := range []struct {
+		t        Type
+		expected string
+	}{
+		{TypeReference("ANY"), "open type ANY"},
+		{ExternalTypeReference{ModuleReference: "Defs", TypeReference: "Recursive"}, "recursive"},
+		{TypeReference("Unknown"), "Unknown is not defined"},
+	} {
+		tags, err := registry.EffectiveTags(registry.Module("Main"), tc.t)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%v: expected error %q, got %v, %v", tc.t, tc.expected, tags, err)
+		}
+	}
+}
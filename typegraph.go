@@ -0,0 +1,172 @@
+package asn1go
+
+// typeGraph holds references between types of a module which generated Go types contain by value: components
+// and alternatives referencing other types, possibly through tags, constraints and nested inline types, and
+// types defined as other types. Elements of SEQUENCE OF and SET OF are held in slices, so they don't count.
+// Types of a strongly connected component with more than one type, or with a type referencing itself, contain
+// each other, so some of the references have to be pointers. These are references closing a cycle when the
+// graph is searched in order of assignments, and references to a type defined as other type if its own
+// reference closes a cycle, since Go type definition can't be a pointer.
+type typeGraph struct {
+	edges     map[string][]typeEdge
+	component map[string]int    // index of strongly connected component of each type
+	pointers  map[typeEdge]bool // references that have to be pointers
+	index     map[string]int    // order of visiting type by Tarjan's algorithm
+	lowLink   map[string]int    // lowest index reachable from type
+	stack     []string          // types visited but not assigned to component yet
+	onStack   map[string]bool   // true if type is in stack
+	onPath    map[string]bool   // true if type is being visited
+	closing   []typeEdge        // references to types being visited
+	direct    map[typeEdge]bool // references of types defined as other type
+}
+
+// typeEdge is reference from type from to type to
+type typeEdge struct {
+	from, to string
+}
+
+// newTypeGraph builds graph of type assignments of module and finds its strongly connected components and
+// references that have to be pointers, references to types of other modules aren't followed
+func newTypeGraph(registry *Registry, module *ModuleDefinition) *typeGraph {
+	g := &typeGraph{
+		edges:     map[string][]typeEdge{},
+		component: map[string]int{},
+		pointers:  map[typeEdge]bool{},
+		index:     map[string]int{},
+		lowLink:   map[string]int{},
+		onStack:   map[string]bool{},
+		onPath:    map[string]bool{},
+		direct:    map[typeEdge]bool{},
+	}
+	var names []string
+	for _, assignment := range module.ModuleBody.AssignmentList {
+		if a, ok := assignment.(TypeAssignment); ok {
+			name := a.TypeReference.Name()
+			names = append(names, name)
+			_, direct := withoutTags(a.Type).(TypeReference)
+			for _, reference := range g.references(registry, module, a.Type, nil) {
+				edge := typeEdge{from: name, to: reference}
+				g.edges[name] = append(g.edges[name], edge)
+				if direct {
+					g.direct[edge] = true
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		if _, visited := g.index[name]; !visited {
+			g.connect(name)
+		}
+	}
+	for _, edge := range g.closing {
+		g.breakCycle(edge)
+	}
+	return g
+}
+
+// references appends names of types of module, which t contains by value, to res
+func (g *typeGraph) references(registry *Registry, module *ModuleDefinition, t Type, res []string) []string {
+	switch tt := t.(type) {
+	case TaggedType:
+		return g.references(registry, module, tt.Type, res)
+	case ConstraintedType:
+		return g.references(registry, module, tt.Type, res)
+	case TypeReference:
+		if module.ModuleBody.AssignmentList.GetType(tt.Name()) != nil {
+			return append(res, tt.Name())
+		}
+	case SequenceType, SetType:
+		// included components are fields of the same struct
+		expanded, err := registry.ExpandComponentsOf(module, tt)
+		if err != nil {
+			expanded = tt
+		}
+		sequence, ok := expanded.(SequenceType)
+		if !ok {
+			sequence = SequenceType(expanded.(SetType))
+		}
+		for _, component := range sequence.AllComponents() {
+			if named, ok := component.(NamedComponentType); ok {
+				res = g.references(registry, module, named.NamedType.Type, res)
+			}
+		}
+	case ChoiceType:
+		for _, alternative := range tt.Alternatives() {
+			res = g.references(registry, module, alternative.Type, res)
+		}
+	}
+	return res
+}
+
+// connect visits types reachable from name and assigns them to strongly connected components, see Tarjan's
+// algorithm. References to types being visited close cycles and are collected.
+func (g *typeGraph) connect(name string) {
+	g.index[name] = len(g.index)
+	g.lowLink[name] = g.index[name]
+	g.stack = append(g.stack, name)
+	g.onStack[name] = true
+	g.onPath[name] = true
+	for _, edge := range g.edges[name] {
+		next := edge.to
+		if _, visited := g.index[next]; !visited {
+			g.connect(next)
+			if g.lowLink[next] < g.lowLink[name] {
+				g.lowLink[name] = g.lowLink[next]
+			}
+		} else if g.onStack[next] && g.index[next] < g.lowLink[name] {
+			g.lowLink[name] = g.index[next]
+		}
+		if g.onPath[next] {
+			g.closing = append(g.closing, edge)
+		}
+	}
+	g.onPath[name] = false
+	if g.lowLink[name] != g.index[name] {
+		return
+	}
+	component := len(g.component)
+	for {
+		last := g.stack[len(g.stack)-1]
+		g.stack = g.stack[:len(g.stack)-1]
+		g.onStack[last] = false
+		g.component[last] = component
+		if last == name {
+			break
+		}
+	}
+}
+
+// breakCycle makes edge a pointer. Direct reference can't be a pointer, so references to its source are made
+// pointers instead.
+func (g *typeGraph) breakCycle(edge typeEdge) {
+	if !g.direct[edge] {
+		g.pointers[edge] = true
+		return
+	}
+	targets := []string{edge.from}
+	seen := map[string]bool{edge.from: true}
+	for len(targets) > 0 {
+		target := targets[0]
+		targets = targets[1:]
+		for source, edges := range g.edges {
+			if g.component[source] != g.component[target] {
+				continue
+			}
+			for _, e := range edges {
+				if e.to != target {
+					continue
+				} else if !g.direct[e] {
+					g.pointers[e] = true
+				} else if !seen[source] {
+					seen[source] = true
+					targets = append(targets, source)
+				}
+			}
+		}
+	}
+}
+
+// breaksCycle tells if reference from type from to type to has to be a pointer
+func (g *typeGraph) breaksCycle(from, to string) bool {
+	return g.pointers[typeEdge{from: from, to: to}]
+}
@@ -0,0 +1,43 @@
+package asn1go
+
+import (
+	"testing"
+)
+
+func TestTypeGraph(t *testing.T) {
+	registry := testRegistry(t, `Test DEFINITIONS ::= BEGIN
+		Node ::= SEQUENCE { value INTEGER, next Node OPTIONAL }
+		Tree ::= SEQUENCE { children SEQUENCE OF Tree }
+		Expr ::= CHOICE { number INTEGER, sum Sum, neg [0] SEQUENCE { operand Expr } }
+		Sum ::= SEQUENCE { left Expr, right [1] Expr }
+		Holder ::= SEQUENCE { expr Expr, node Node }
+		Alias ::= Wrap
+		Wrap ::= SEQUENCE { inner Alias OPTIONAL }
+		Base ::= SEQUENCE { parent Derived OPTIONAL }
+		Derived ::= SEQUENCE { COMPONENTS OF Base }
+	END`)
+	g := newTypeGraph(registry, registry.Module("Test"))
+	for _, tc := range []struct {
+		from, to string
+		expected bool
+	}{
+		{"Node", "Node", true},
+		{"Tree", "Tree", false},
+		{"Expr", "Sum", false},
+		{"Expr", "Expr", true},
+		{"Sum", "Expr", true},
+		{"Holder", "Expr", false},
+		{"Holder", "Node", false},
+		{"Alias", "Wrap", false},
+		{"Wrap", "Alias", true},
+		{"Base", "Derived", false},
+		{"Derived", "Derived", true},
+	} {
+		if got := g.breaksCycle(tc.from, tc.to); got != tc.expected {
+			t.Errorf("%v -> %v: expected %v, got %v", tc.from, tc.to, tc.expected, got)
+		}
+	}
+	if g.component["Expr"] != g.component["Sum"] || g.component["Holder"] == g.component["Expr"] {
+		t.Errorf("Unexpected components: %v", g.component)
+	}
+}
@@ -0,0 +1,242 @@
+package asn1go
+
+import (
+	"fmt"
+	goast "go/ast"
+	gotoken "go/token"
+	"strings"
+)
+
+// resolveLeaf follows references, tags and constraints of t used in module until it reaches builtin type, returns
+// it together with module defining it and name of the type assigned to it there, empty if t isn't a reference
+func (r *Registry) resolveLeaf(module *ModuleDefinition, t Type) (*ModuleDefinition, Type, string, error) {
+	var chain []string
+	for {
+		source, resolved, followed, err := r.resolveUntagged(module, t, chain)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		module, chain = source, followed
+		tagged, ok := resolved.(TaggedType)
+		if !ok {
+			name := ""
+			if len(chain) > 0 {
+				// imported types keep their names
+				name = chain[len(chain)-1][strings.Index(chain[len(chain)-1], ".")+1:]
+			}
+			return module, resolved, name, nil
+		}
+		t = tagged.Type
+	}
+}
+
+// needsCheck tells if values of t used in module may be decoded as values that t doesn't have: t is ENUMERATED
+// without extension marker or holds values of such type. encoding/asn1 decodes any number as asn1.Enumerated.
+func (ctx *moduleContext) needsCheck(module *ModuleDefinition, t Type, visited map[string]bool) bool {
+	module, resolved, name, err := ctx.compiler.registry.resolveLeaf(module, t)
+	if err != nil {
+		return false
+	} else if name != "" {
+		key := module.ModuleIdentifier.Reference + "." + name
+		// types containing each other are checked once
+		if visited[key] {
+			return false
+		}
+		visited[key] = true
+	}
+	switch tt := resolved.(type) {
+	case EnumeratedType:
+		return !tt.Extensible
+	case SequenceOfType:
+		return ctx.needsCheck(module, tt.Type, visited)
+	case SetOfType:
+		return ctx.needsCheck(module, tt.Type, visited)
+	case SequenceType, SetType:
+		expanded, err := ctx.compiler.registry.ExpandComponentsOf(module, tt)
+		if err != nil {
+			expanded = tt
+		}
+		sequence, ok := expanded.(SequenceType)
+		if !ok {
+			sequence = SequenceType(expanded.(SetType))
+		}
+		for _, component := range sequence.AllComponents() {
+			if named, ok := component.(NamedComponentType); ok && ctx.needsCheck(module, named.NamedType.Type, visited) {
+				return true
+			}
+		}
+	case ChoiceType:
+		for _, alternative := range tt.Alternatives() {
+			if ctx.needsCheck(module, alternative.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateCheck generates Check method of Go type name declared for t, if its values need to be checked after
+// decoding, see needsCheck. Check of SEQUENCE, SET and CHOICE checks their fields, of SEQUENCE OF and SET OF their
+// elements and type defined as other type is checked as that type. Types defined as ENUMERATED have Valid method
+// instead, see generateNamedValues.
+func (ctx *moduleContext) generateCheck(name string, t Type) []goast.Decl {
+	if !ctx.needsCheck(ctx.module, t, map[string]bool{}) {
+		return nil
+	}
+	receiver := goast.NewIdent("v")
+	field := func(identifier Identifier) goast.Expr {
+		return &goast.SelectorExpr{X: receiver, Sel: goast.NewIdent(goifyName(identifier.Name()))}
+	}
+	var body []goast.Stmt
+	switch tt := withoutTags(t).(type) {
+	case EnumeratedType:
+		return nil
+	case SequenceType, SetType:
+		expanded := ctx.expandComponentsOf(tt)
+		sequence, ok := expanded.(SequenceType)
+		if !ok {
+			sequence = SequenceType(expanded.(SetType))
+		}
+		addComponents := func(components ComponentTypeList, optional bool) {
+			for _, component := range components {
+				if f, ok := component.(NamedComponentType); ok {
+					identifier := f.NamedType.Identifier
+					optional := f.IsOptional || (optional && f.Default == nil)
+					body = append(body, ctx.checkStmts(field(identifier), identifier.Name(), f.NamedType.Type, optional, 0)...)
+				}
+			}
+		}
+		addComponents(sequence.Components, false)
+		for _, addition := range sequence.ExtensionAdditions {
+			switch a := addition.(type) {
+			case ExtensionAdditionGroup:
+				addComponents(a.Components, true)
+			case ComponentType:
+				addComponents(ComponentTypeList{a}, true)
+			}
+		}
+		addComponents(sequence.TrailingRootComponents, false)
+	case ChoiceType:
+		// fields of alternatives not chosen are left empty
+		for _, alternative := range tt.Alternatives() {
+			body = append(body, ctx.checkStmts(field(alternative.Identifier), alternative.Identifier.Name(), alternative.Type, true, 0)...)
+		}
+	case SequenceOfType, SetOfType:
+		body = ctx.checkStmts(&goast.ParenExpr{X: &goast.StarExpr{X: receiver}}, inlineItem, tt, false, 0)
+	case TypeReference, ExternalTypeReference:
+		if _, resolved, _, _ := ctx.compiler.registry.resolveLeaf(ctx.module, tt); resolved == nil {
+			return nil
+		} else if _, ok := resolved.(EnumeratedType); ok {
+			// fields of the type are asn1.Enumerated, checked with Valid of ENUMERATED type
+			return nil
+		}
+		goType := ctx.generateTypeBody(tt, true)
+		converted := call(&goast.ParenExpr{X: &goast.StarExpr{X: goType}}, receiver)
+		body = []goast.Stmt{returnStmt(call(&goast.SelectorExpr{X: converted, Sel: goast.NewIdent("Check")}))}
+	default:
+		return nil
+	}
+	nilReceiver := &goast.IfStmt{
+		Cond: &goast.BinaryExpr{X: receiver, Op: gotoken.EQL, Y: goast.NewIdent("nil")},
+		Body: &goast.BlockStmt{List: []goast.Stmt{returnStmt(goast.NewIdent("nil"))}},
+	}
+	if len(body) == 0 {
+		// types with nothing to check have no Check method
+		return nil
+	} else if _, ok := body[len(body)-1].(*goast.ReturnStmt); !ok {
+		body = append(body, returnStmt(goast.NewIdent("nil")))
+	}
+	return []goast.Decl{&goast.FuncDecl{
+		Recv: fieldList(receiver, &goast.StarExpr{X: goast.NewIdent(name)}),
+		Name: goast.NewIdent("Check"),
+		Type: &goast.FuncType{Params: &goast.FieldList{}, Results: fieldList(nil, goast.NewIdent("error"))},
+		Body: &goast.BlockStmt{List: append([]goast.Stmt{nilReceiver}, body...)},
+	}}
+}
+
+// checkStmts generates statements returning error if value x of type t of component, alternative or item
+// identifier isn't its value. Absent OPTIONAL components and alternatives not chosen are left empty, so if optional
+// is true zero number is accepted for ENUMERATED, and SEQUENCE, SET and CHOICE values are checked only if they are
+// not nil pointers or zero structs. depth is number of enclosing loops over elements of SEQUENCE OF and SET OF.
+func (ctx *moduleContext) checkStmts(x goast.Expr, identifier string, t Type, optional bool, depth int) []goast.Stmt {
+	if !ctx.needsCheck(ctx.module, t, map[string]bool{}) {
+		return nil
+	}
+	ctx.requireModule("fmt")
+	fail := func(format string, args ...goast.Expr) goast.Stmt {
+		return returnStmt(call(selector("fmt", "Errorf"), append([]goast.Expr{stringLit(identifier + ": " + format)}, args...)...))
+	}
+	checkEnumerated := func(name string) []goast.Stmt {
+		var cond goast.Expr = &goast.UnaryExpr{Op: gotoken.NOT, X: call(&goast.SelectorExpr{X: call(goast.NewIdent(name), x), Sel: goast.NewIdent("Valid")})}
+		if optional {
+			cond = &goast.BinaryExpr{X: &goast.BinaryExpr{X: x, Op: gotoken.NEQ, Y: intLit(0)}, Op: gotoken.LAND, Y: cond}
+		}
+		return []goast.Stmt{&goast.IfStmt{Cond: cond, Body: &goast.BlockStmt{List: []goast.Stmt{fail("unknown "+name+" %d", x)}}}}
+	}
+	checkValue := func(pointer bool) []goast.Stmt {
+		err := goast.NewIdent("err")
+		check := []goast.Stmt{&goast.IfStmt{
+			Init: &goast.AssignStmt{
+				Lhs: []goast.Expr{err},
+				Tok: gotoken.DEFINE,
+				Rhs: []goast.Expr{call(&goast.SelectorExpr{X: x, Sel: goast.NewIdent("Check")})},
+			},
+			Cond: &goast.BinaryExpr{X: err, Op: gotoken.NEQ, Y: goast.NewIdent("nil")},
+			Body: &goast.BlockStmt{List: []goast.Stmt{fail("%w", err)}},
+		}}
+		if !optional {
+			return check
+		}
+		var present goast.Expr = &goast.BinaryExpr{X: x, Op: gotoken.NEQ, Y: goast.NewIdent("nil")}
+		if !pointer {
+			ctx.requireModule("reflect")
+			isZero := &goast.SelectorExpr{X: call(selector("reflect", "ValueOf"), x), Sel: goast.NewIdent("IsZero")}
+			present = &goast.UnaryExpr{Op: gotoken.NOT, X: call(isZero)}
+		}
+		return []goast.Stmt{&goast.IfStmt{Cond: present, Body: &goast.BlockStmt{List: check}}}
+	}
+	switch tt := t.(type) {
+	case TaggedType:
+		return ctx.checkStmts(x, identifier, tt.Type, optional, depth)
+	case ConstraintedType:
+		return ctx.checkStmts(x, identifier, tt.Type, optional, depth)
+	case EnumeratedType:
+		return checkEnumerated(ctx.qualifiedInlineName(identifier))
+	case SequenceType, SetType, ChoiceType:
+		// inline types are never pointers
+		return checkValue(false)
+	case SequenceOfType, SetOfType:
+		element := Type(nil)
+		if sequenceOf, ok := tt.(SequenceOfType); ok {
+			element = sequenceOf.Type
+		} else {
+			element = tt.(SetOfType).Type
+		}
+		index := goast.NewIdent("i" + strings.Repeat("i", depth))
+		return []goast.Stmt{&goast.RangeStmt{
+			Key:  index,
+			Tok:  gotoken.DEFINE,
+			X:    x,
+			Body: &goast.BlockStmt{List: ctx.checkStmts(&goast.IndexExpr{X: x, Index: index}, identifier, element, false, depth+1)},
+		}}
+	case TypeReference, ExternalTypeReference:
+		module, resolved, name, err := ctx.compiler.registry.resolveLeaf(ctx.module, tt)
+		if err != nil {
+			return nil
+		}
+		if _, ok := resolved.(EnumeratedType); ok {
+			// fields of ENUMERATED types are asn1.Enumerated, constants and Valid belong to the type defining it
+			name = goifyName(name)
+			if module.ModuleIdentifier.Reference != ctx.module.ModuleIdentifier.Reference {
+				ctx.requireGeneratedModule(module.ModuleIdentifier.Reference)
+				name = goifyName(module.ModuleIdentifier.Reference) + "." + name
+			}
+			return checkEnumerated(name)
+		}
+		goType := ctx.generateInlineType(identifier, tt, false)
+		ident, ok := goType.(*goast.Ident)
+		return checkValue(ok && strings.HasPrefix(ident.Name, "*"))
+	}
+	ctx.appendError(fmt.Errorf("%v: can't check values of type %v", identifier, t))
+	return nil
+}
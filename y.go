@@ -1,22 +1,31 @@
-// Code generated by goyacc asn1.y. DO NOT EDIT.
+// Code generated by goyacc -v /dev/null asn1.y. DO NOT EDIT.
 
-// asn1.y:3
+//line asn1.y:3
 package asn1go
 
 import __yyfmt__ "fmt"
 
-// asn1.y:3
+//line asn1.y:3
 
 import (
 	"fmt"
 	"math"
 )
 
-// asn1.y:15
+// nodeSpan returns span of the rule being reduced, from the first symbol up to the last consumed token
+func nodeSpan(yylex yyLexer, first Span, lookahead int) Span {
+	return yylex.(*MyLexer).spanFrom(first.Start, lookahead >= 0)
+}
+
+//line asn1.y:20
 type yySymType struct {
 	yys        int
 	name       string
 	numberRepr string
+	span       Span
+	cstring    string
+	bstring    BitString
+	hstring    OctetString
 
 	Number                            Number
 	Real                              Real
@@ -31,6 +40,8 @@ type yySymType struct {
 	DefinedValue                      DefinedValue
 	ObjectIdentifierValue             ObjectIdentifierValue
 	Value                             Value
+	ValueList                         []Value
+	BracedValue                       BracedValue
 	Assignment                        Assignment
 	AssignmentList                    AssignmentList
 	ModuleBody                        ModuleBody
@@ -50,9 +61,8 @@ type yySymType struct {
 	NamedType                         NamedType
 	ComponentType                     ComponentType
 	ComponentTypeList                 ComponentTypeList
-	IntegerEnumType                    IntegerEnumType
-	IntegerEnumItemList                IntegerEnumItemList
-	IntegerEnumItem                    IntegerEnumItem
+	NamedNumberList                   NamedNumberList
+	NamedNumber                       NamedNumber
 	EnumeratedType                    EnumeratedType
 	EnumeratedItemList                EnumeratedItemList
 	EnumeratedItem                    EnumeratedItem
@@ -64,6 +74,7 @@ type yySymType struct {
 	SetOfType                         SetOfType
 	NamedBitList                      []NamedBit
 	NamedBit                          NamedBit
+	Exports                           Exports
 	Imports                           []SymbolsFromModule
 	SymbolsFromModule                 SymbolsFromModule
 	SymbolList                        []Symbol
@@ -73,6 +84,9 @@ type yySymType struct {
 	ChoiceType                        ChoiceType
 	ExtensionAdditionAlternative      ChoiceExtension
 	ExtensionAdditionAlternativesList []ChoiceExtension
+	ExtensionAddition                 ExtensionAddition
+	ExtensionAdditionList             []ExtensionAddition
+	ExceptionSpec                     *ExceptionSpec
 }
 
 const WHITESPACE = 57346
@@ -326,10 +340,6 @@ var yyToknames = [...]string{
 	"INSTANCE",
 	"REAL",
 	"WITH",
-	"\"t\"",
-	"\"o\"",
-	"\"d\"",
-	"\",\"",
 }
 
 var yyStatenames = [...]string{}
@@ -338,340 +348,395 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-// asn1.y:1030
+//line asn1.y:1219
 
-// yacctab:1
+//line yacctab:1
 var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 33,
-	52, 26,
-	-2, 29,
-	-1, 176,
-	44, 233,
-	94, 233,
-	-2, 229,
-	-1, 178,
-	46, 236,
-	53, 236,
-	-2, 231,
-	-1, 182,
-	60, 239,
-	-2, 237,
-	-1, 190,
-	16, 257,
-	28, 257,
-	-2, 251,
-	-1, 322,
-	46, 236,
-	53, 236,
-	-2, 232,
+	-1, 37,
+	52, 28,
+	-2, 32,
+	-1, 41,
+	42, 34,
+	-2, 0,
+	-1, 44,
+	42, 39,
+	-2, 0,
+	-1, 54,
+	31, 7,
+	-2, 6,
+	-1, 56,
+	52, 27,
+	-2, 0,
+	-1, 200,
+	44, 265,
+	94, 265,
+	-2, 261,
+	-1, 202,
+	46, 268,
+	53, 268,
+	-2, 263,
+	-1, 206,
+	60, 271,
+	-2, 269,
+	-1, 214,
+	16, 289,
+	28, 289,
+	-2, 283,
+	-1, 219,
+	16, 145,
+	28, 145,
+	-2, 144,
+	-1, 347,
+	46, 268,
+	53, 268,
+	-2, 264,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 791
+const yyLast = 1080
 
 var yyAct = [...]int16{
-	162, 382, 285, 306, 161, 175, 310, 217, 272, 341,
-	19, 326, 248, 216, 208, 192, 19, 203, 190, 204,
-	178, 211, 180, 244, 168, 256, 259, 158, 182, 157,
-	212, 351, 127, 372, 318, 57, 21, 330, 300, 342,
-	7, 21, 251, 290, 234, 148, 251, 163, 40, 47,
-	4, 4, 31, 13, 11, 133, 140, 292, 222, 135,
-	221, 291, 319, 120, 262, 128, 188, 319, 37, 26,
-	21, 25, 67, 64, 24, 23, 33, 145, 95, 21,
-	213, 134, 125, 101, 21, 96, 110, 102, 147, 213,
-	224, 129, 260, 38, 265, 139, 257, 121, 12, 210,
-	90, 266, 97, 114, 106, 303, 111, 149, 91, 44,
-	98, 113, 63, 62, 263, 302, 119, 105, 99, 142,
-	94, 107, 301, 141, 60, 108, 136, 123, 299, 109,
-	379, 199, 205, 209, 137, 115, 199, 199, 198, 378,
-	331, 199, 199, 116, 220, 152, 112, 117, 235, 228,
-	233, 118, 219, 235, 100, 238, 232, 227, 235, 229,
-	230, 235, 225, 235, 250, 235, 218, 245, 138, 21,
-	166, 226, 223, 151, 124, 218, 61, 239, 240, 243,
-	218, 237, 241, 52, 122, 49, 50, 373, 163, 128,
-	311, 268, 255, 48, 357, 126, 273, 371, 167, 370,
-	369, 154, 368, 104, 384, 324, 313, 242, 34, 128,
-	267, 270, 128, 367, 43, 276, 366, 332, 312, 253,
-	308, 284, 199, 199, 281, 252, 286, 29, 297, 294,
-	296, 170, 365, 56, 42, 65, 359, 293, 295, 288,
-	170, 46, 160, 55, 46, 56, 250, 250, 159, 282,
-	279, 277, 283, 280, 278, 55, 289, 275, 258, 64,
-	352, 316, 315, 46, 350, 17, 345, 255, 46, 305,
-	307, 169, 21, 317, 304, 298, 199, 287, 274, 336,
-	169, 338, 322, 334, 209, 340, 339, 323, 328, 321,
-	199, 21, 206, 27, 344, 32, 335, 333, 343, 195,
-	337, 132, 131, 347, 130, 9, 355, 21, 349, 346,
-	348, 201, 354, 320, 247, 269, 66, 213, 14, 30,
-	234, 353, 21, 20, 16, 358, 356, 309, 243, 254,
-	16, 20, 273, 21, 28, 5, 363, 49, 50, 360,
-	361, 314, 362, 57, 50, 2, 328, 6, 264, 261,
-	200, 41, 36, 1, 57, 21, 166, 381, 364, 197,
-	196, 74, 374, 150, 236, 377, 199, 380, 45, 59,
-	340, 339, 58, 383, 163, 39, 375, 271, 71, 80,
-	185, 88, 120, 146, 167, 199, 385, 154, 231, 103,
-	86, 214, 383, 215, 85, 83, 84, 95, 82, 202,
-	75, 207, 101, 177, 96, 110, 102, 76, 69, 87,
-	139, 93, 92, 194, 73, 193, 325, 170, 191, 90,
-	189, 97, 114, 106, 184, 111, 187, 91, 160, 98,
-	113, 186, 183, 181, 159, 119, 105, 99, 179, 94,
-	107, 57, 21, 166, 108, 176, 376, 174, 109, 173,
-	172, 171, 89, 70, 115, 35, 51, 169, 53, 54,
-	165, 163, 116, 164, 155, 112, 117, 185, 81, 120,
-	118, 167, 156, 100, 154, 153, 249, 246, 78, 68,
-	72, 77, 79, 8, 95, 18, 15, 3, 10, 101,
-	22, 96, 110, 102, 0, 0, 0, 139, 0, 0,
-	194, 0, 0, 0, 170, 0, 90, 0, 97, 114,
-	106, 0, 111, 0, 91, 160, 98, 113, 57, 0,
-	318, 159, 119, 105, 99, 0, 94, 107, 0, 0,
-	0, 108, 0, 0, 0, 109, 0, 0, 0, 0,
-	0, 115, 0, 0, 169, 0, 120, 0, 319, 116,
-	0, 0, 112, 117, 0, 0, 0, 118, 0, 0,
-	100, 95, 0, 0, 0, 0, 101, 0, 96, 110,
-	102, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 90, 0, 97, 114, 106, 0, 111,
-	0, 91, 57, 98, 113, 0, 0, 0, 0, 119,
-	105, 99, 0, 94, 107, 0, 0, 0, 108, 0,
-	0, 0, 109, 0, 0, 0, 0, 0, 115, 0,
-	120, 0, 21, 166, 0, 0, 116, 0, 0, 112,
-	117, 0, 0, 0, 118, 95, 0, 100, 0, 235,
-	101, 163, 96, 110, 102, 0, 0, 0, 0, 0,
-	144, 167, 0, 0, 154, 0, 0, 90, 0, 97,
-	114, 106, 57, 111, 0, 91, 0, 98, 113, 0,
-	0, 0, 0, 119, 105, 99, 329, 94, 107, 0,
-	0, 0, 108, 0, 170, 0, 109, 0, 21, 166,
-	120, 0, 115, 0, 143, 160, 0, 0, 0, 0,
-	116, 159, 0, 112, 117, 95, 0, 163, 118, 327,
-	101, 100, 96, 110, 102, 0, 0, 167, 0, 0,
-	154, 0, 0, 0, 169, 0, 0, 90, 0, 97,
-	114, 106, 0, 111, 0, 91, 0, 98, 113, 0,
-	0, 0, 329, 119, 105, 99, 0, 94, 107, 0,
-	170, 0, 108, 0, 0, 0, 109, 0, 0, 0,
-	0, 160, 115, 0, 0, 0, 0, 159, 0, 0,
-	116, 0, 0, 112, 117, 0, 0, 0, 118, 0,
-	0, 100, 0, 0, 0, 0, 0, 0, 0, 0,
-	169,
+	225, 415, 223, 237, 241, 229, 372, 404, 228, 199,
+	335, 239, 268, 23, 189, 5, 5, 308, 309, 295,
+	23, 255, 351, 267, 182, 214, 216, 233, 321, 204,
+	256, 202, 270, 278, 113, 20, 238, 206, 281, 192,
+	236, 158, 137, 10, 176, 312, 44, 35, 212, 17,
+	15, 259, 314, 25, 143, 284, 52, 246, 25, 52,
+	245, 145, 72, 313, 25, 150, 30, 138, 370, 375,
+	29, 28, 27, 49, 240, 41, 51, 130, 61, 51,
+	37, 52, 155, 144, 157, 139, 282, 52, 67, 76,
+	130, 61, 42, 53, 16, 62, 53, 149, 279, 13,
+	9, 51, 135, 159, 131, 285, 47, 51, 62, 25,
+	54, 55, 71, 57, 70, 69, 133, 424, 53, 240,
+	6, 25, 190, 177, 53, 184, 130, 180, 146, 248,
+	68, 138, 151, 264, 287, 320, 386, 181, 152, 181,
+	187, 288, 230, 234, 412, 224, 134, 25, 162, 242,
+	191, 244, 147, 220, 242, 132, 251, 240, 148, 46,
+	242, 130, 163, 6, 55, 343, 130, 235, 130, 130,
+	73, 332, 48, 243, 336, 354, 264, 21, 250, 411,
+	252, 253, 406, 194, 399, 398, 397, 183, 271, 181,
+	136, 249, 247, 344, 179, 260, 396, 349, 338, 138,
+	178, 258, 337, 38, 332, 242, 357, 138, 305, 275,
+	181, 36, 302, 269, 272, 274, 433, 33, 429, 290,
+	293, 273, 296, 193, 220, 188, 160, 434, 420, 420,
+	355, 277, 329, 356, 303, 330, 427, 304, 425, 421,
+	130, 403, 310, 242, 395, 394, 289, 388, 369, 307,
+	316, 318, 299, 301, 298, 280, 72, 292, 25, 25,
+	130, 130, 378, 319, 327, 181, 306, 300, 271, 297,
+	31, 263, 315, 317, 311, 221, 142, 345, 231, 226,
+	331, 141, 140, 181, 181, 12, 384, 181, 291, 18,
+	328, 75, 181, 269, 220, 34, 325, 220, 220, 323,
+	405, 220, 230, 359, 340, 234, 342, 361, 326, 32,
+	341, 25, 25, 24, 181, 347, 348, 353, 387, 368,
+	346, 240, 373, 327, 258, 333, 385, 362, 260, 130,
+	365, 271, 364, 363, 334, 358, 366, 276, 360, 376,
+	24, 377, 63, 55, 367, 333, 25, 258, 55, 25,
+	258, 379, 63, 181, 380, 325, 269, 296, 323, 430,
+	418, 25, 6, 383, 6, 55, 257, 326, 6, 55,
+	382, 413, 418, 401, 286, 389, 390, 258, 353, 391,
+	283, 271, 6, 25, 324, 400, 1, 181, 392, 181,
+	402, 54, 55, 331, 414, 407, 230, 8, 410, 417,
+	393, 6, 258, 220, 419, 416, 269, 3, 423, 74,
+	422, 6, 408, 63, 55, 373, 54, 25, 190, 177,
+	2, 184, 7, 180, 222, 428, 426, 431, 230, 432,
+	417, 224, 368, 361, 83, 161, 187, 261, 50, 359,
+	66, 64, 209, 65, 129, 58, 191, 54, 55, 63,
+	55, 43, 45, 40, 294, 80, 88, 96, 156, 104,
+	254, 111, 94, 339, 109, 201, 103, 119, 110, 371,
+	374, 93, 149, 91, 92, 218, 90, 232, 227, 194,
+	84, 98, 112, 219, 123, 115, 78, 120, 95, 99,
+	179, 106, 122, 101, 100, 82, 178, 128, 114, 107,
+	217, 102, 116, 350, 215, 213, 117, 208, 211, 210,
+	118, 207, 205, 203, 200, 409, 124, 198, 197, 193,
+	196, 188, 195, 97, 125, 79, 39, 121, 126, 56,
+	59, 60, 127, 186, 185, 108, 54, 25, 190, 177,
+	266, 184, 174, 180, 165, 175, 171, 169, 168, 172,
+	166, 167, 173, 89, 170, 164, 187, 262, 381, 322,
+	86, 77, 209, 81, 129, 85, 191, 87, 11, 22,
+	19, 4, 14, 26, 0, 0, 0, 0, 0, 104,
+	0, 0, 0, 0, 109, 0, 103, 119, 110, 0,
+	0, 0, 149, 0, 0, 218, 0, 0, 0, 194,
+	0, 98, 0, 219, 123, 115, 0, 120, 0, 99,
+	179, 106, 122, 0, 0, 0, 178, 128, 114, 107,
+	0, 102, 116, 54, 55, 343, 117, 0, 0, 0,
+	118, 0, 0, 0, 0, 0, 124, 0, 0, 193,
+	0, 188, 0, 0, 125, 0, 0, 121, 126, 0,
+	0, 129, 127, 344, 0, 108, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 104, 0, 0, 0,
+	0, 109, 0, 103, 119, 110, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 98, 0,
+	105, 123, 115, 54, 120, 0, 99, 0, 106, 122,
+	0, 0, 0, 0, 128, 114, 107, 0, 102, 116,
+	0, 0, 0, 117, 0, 0, 0, 118, 0, 0,
+	0, 129, 0, 124, 0, 0, 0, 0, 0, 0,
+	0, 125, 0, 0, 121, 126, 104, 0, 0, 127,
+	0, 109, 108, 103, 119, 110, 0, 0, 0, 0,
+	0, 154, 0, 0, 0, 0, 0, 0, 98, 0,
+	105, 123, 115, 0, 120, 0, 99, 0, 106, 122,
+	54, 25, 0, 0, 128, 114, 107, 0, 102, 116,
+	0, 0, 0, 117, 0, 0, 0, 118, 0, 0,
+	0, 0, 0, 124, 0, 153, 0, 0, 129, 0,
+	0, 125, 0, 0, 121, 126, 0, 0, 0, 127,
+	0, 0, 108, 104, 0, 0, 0, 0, 109, 0,
+	103, 119, 110, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 98, 0, 105, 123, 115,
+	54, 120, 0, 99, 0, 106, 122, 0, 0, 0,
+	0, 128, 114, 107, 0, 102, 116, 0, 0, 0,
+	117, 0, 0, 0, 118, 0, 0, 0, 129, 0,
+	124, 0, 0, 0, 0, 0, 0, 0, 125, 0,
+	0, 121, 126, 104, 0, 0, 127, 0, 109, 108,
+	103, 119, 110, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 98, 0, 105, 123, 115,
+	0, 120, 0, 99, 0, 106, 122, 0, 0, 0,
+	0, 128, 114, 107, 0, 102, 116, 0, 0, 0,
+	117, 0, 0, 0, 118, 0, 0, 0, 0, 0,
+	124, 0, 6, 25, 190, 177, 0, 184, 125, 180,
+	0, 121, 126, 0, 0, 0, 127, 0, 0, 108,
+	0, 0, 187, 0, 352, 0, 6, 25, 190, 177,
+	0, 184, 191, 180, 6, 25, 190, 177, 0, 184,
+	0, 180, 0, 0, 0, 0, 187, 265, 0, 0,
+	0, 0, 0, 0, 187, 0, 191, 354, 0, 0,
+	0, 0, 0, 0, 191, 194, 0, 0, 0, 183,
+	0, 0, 0, 0, 0, 0, 179, 0, 0, 0,
+	0, 0, 178, 0, 0, 0, 0, 0, 0, 194,
+	0, 0, 0, 183, 0, 0, 0, 194, 0, 0,
+	179, 183, 0, 0, 0, 193, 178, 188, 179, 0,
+	0, 0, 0, 0, 178, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 193,
+	0, 188, 0, 0, 0, 0, 0, 193, 0, 188,
 }
 
 var yyPact = [...]int16{
-	329, 329, -1000, -79, 279, -1000, -1000, -10, -1000, 315,
-	3, -4, -7, -9, 266, 315, -1000, -1000, -1000, 195,
-	-1000, -1000, 304, -60, -1000, -1000, -1000, -1000, -1000, 323,
-	9, -1000, 175, 0, -1000, 41, -68, 179, -1000, 337,
-	331, 71, 70, 229, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 337, -1000, -1000, -1000, 301, 656, -1000, 55, 331,
-	-1000, 43, -1000, -1000, 331, -1000, 656, 180, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	25, -1000, -1000, -1000, 278, 276, 275, -1000, -49, 15,
-	-1000, 33, 30, 586, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 11,
-	-14, -1000, -1000, 329, -1000, 177, 162, -1000, 348, 273,
-	326, 284, 265, -1000, -1000, 72, 29, -33, -35, 177,
-	63, 29, 177, 656, 656, -1000, 36, -1000, -1000, -1000,
-	-1000, 21, -1000, -1000, 171, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, 34, -1000, -1000, 194, 321, -1000, -1000,
-	-1000, 51, -1000, -1000, 228, -1000, -1000, 32, -1000, 20,
-	-1000, 48, -1000, 32, -1000, 348, -1000, -1000, -1000, -1000,
-	-1000, 299, 177, 183, -1000, 326, 251, 227, -1000, 656,
-	224, -1000, 223, -1000, -1000, 192, -1000, 222, -1000, 189,
-	-1000, 196, 250, 51, -1000, 226, -1000, -54, -36, 177,
-	-1000, 29, 29, -1000, -1000, 196, 248, 177, -1000, 177,
-	177, 93, -1000, -1000, -1000, -90, -1000, -1000, -1000, 83,
-	76, 66, 320, 194, 247, 34, 34, -1000, -1000, -1000,
-	188, -1000, 319, 182, 194, -1000, 173, 512, 296, -1000,
-	435, 435, -1000, -1000, 435, -1000, -1000, -1000, 172, 681,
-	-1000, 10, -1000, 185, -1000, 300, 177, -1000, 326, -1000,
-	326, 31, -1000, 326, 312, 239, 292, -1000, -1000, 77,
-	-1000, 162, 656, 177, -1000, 177, -1000, 237, -1000, -1000,
-	-98, -1000, -1000, -1000, -1000, 233, -1000, -1000, 38, 281,
-	-1000, -1000, 318, -1000, -1000, -1000, -1000, 157, -1000, 317,
-	206, -1000, -1000, -1000, -1000, -1000, -1000, 615, -1000, -1000,
-	-1000, 326, 36, 202, -1000, -1000, 184, -1000, 181, 169,
-	167, 166, -1000, -1000, 164, -1000, -1000, -1000, -1000, 177,
-	-1000, -95, -1000, 154, -1000, 182, -1000, 162, -1000, 348,
-	-1000, -1000, 106, 97, 196, 326, 26, 312, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 174, -1000, -1000, 326, -1000,
+	405, 395, -32768, 48, -76, 259, -32768, -32768, 47, -32768,
+	-14, -32768, 305, -32768, 0, -7, -8, -12, 243, 305,
+	-32768, -32768, -32768, 185, -32768, -32768, 280, -65, -32768, -32768,
+	-32768, -32768, -32768, 332, 13, -32768, 170, 7, -32768, 40,
+	-70, 104, -32768, 443, 441, 73, 72, 70, 226, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, 407, -32768, -32768, -32768,
+	-32768, 276, 834, -32768, 62, -32768, 385, -32768, 32, -32768,
+	-32768, -32768, 385, -32768, -32768, 834, 175, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, 19, -32768,
+	-32768, -32768, 256, 255, 250, -32768, -50, 17, -32768, 35,
+	39, 687, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, 16, -18,
+	195, -32768, -32768, 356, -32768, 167, 968, -32768, 410, 249,
+	339, 252, 251, -32768, -32768, 140, 764, -33, -36, 167,
+	102, 764, 167, 834, 834, -32768, 358, -32768, -32768, -32768,
+	346, -32768, 245, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, 96, -32768, -32768, -32768, -32768, -32768, 960, 968, 190,
+	184, 329, -32768, -32768, -32768, 53, -32768, -32768, 225, -32768,
+	-32768, 26, -32768, 11, -32768, 88, -32768, 26, -32768, 410,
+	-32768, -32768, -32768, -32768, -32768, 272, 167, 229, -32768, -32768,
+	189, 339, 242, 224, -32768, 834, -32768, 240, 223, -32768,
+	180, -32768, 207, -32768, 176, -32768, 239, 219, 212, -32768,
+	53, -52, -41, 167, -32768, 764, 764, -32768, -32768, 236,
+	167, -32768, 167, 167, 100, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, 376, 968, -32768, 205, 968, -32768, -32768,
+	-32768, 139, -32768, 341, 326, 166, 184, -32768, 165, 617,
+	260, -32768, 530, 530, -32768, -32768, 530, -32768, -32768, -32768,
+	164, 936, -32768, 336, 203, -32768, 174, -32768, 304, 167,
+	-32768, 304, 157, -32768, 339, 157, -32768, 57, -32768, 218,
+	51, -32768, -32768, 968, 834, 167, -32768, 167, -32768, -32768,
+	-32768, 235, 376, -32768, -32768, -32768, -32768, 172, -32768, -32768,
+	968, -32768, 362, -32768, 261, -32768, -32768, 318, -32768, -32768,
+	-32768, -32768, 99, -32768, 310, 217, -32768, -32768, -32768, -32768,
+	-32768, -32768, 114, -32768, -32768, -32768, 339, 358, 215, -32768,
+	214, -32768, 163, 153, -32768, 152, 151, 212, -32768, 46,
+	-32768, 211, -32768, -32768, -32768, 292, -32768, 167, -32768, -32768,
+	968, 149, -32768, -32768, 166, -32768, 968, -32768, 410, -32768,
+	-32768, 146, 111, -32768, 354, 339, -32768, -32768, -32768, -32768,
+	-32768, 198, 209, 51, 46, 80, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, 208, -32768, -32768, -32768, 292, 206,
+	46, 46, -32768, 199, -32768, 342, 339, 339, 198, -32768,
+	-32768, -32768, 197, -32768, 339,
 }
 
 var yyPgo = [...]int16{
-	0, 24, 6, 49, 203, 0, 490, 488, 487, 486,
-	265, 318, 485, 483, 314, 3, 482, 481, 480, 479,
-	15, 478, 7, 477, 12, 476, 23, 29, 475, 18,
-	472, 468, 27, 464, 463, 460, 4, 9, 183, 459,
-	458, 456, 455, 193, 453, 452, 32, 451, 450, 449,
-	447, 446, 5, 445, 438, 20, 433, 22, 26, 28,
-	432, 431, 426, 424, 420, 66, 418, 416, 415, 11,
-	414, 412, 411, 409, 408, 407, 401, 14, 400, 399,
-	19, 398, 396, 395, 394, 13, 393, 391, 30, 390,
-	389, 388, 383, 381, 379, 378, 377, 8, 375, 372,
-	369, 124, 176, 109, 368, 364, 363, 361, 360, 359,
-	359, 1, 358, 357, 345, 353, 352, 351, 350, 17,
-	21, 2, 25, 350, 350, 350, 350, 350, 350, 350,
-	349, 348, 341,
+	0, 39, 10, 14, 34, 0, 573, 572, 571, 570,
+	177, 289, 569, 568, 35, 30, 44, 567, 565, 563,
+	561, 26, 560, 4, 559, 558, 32, 28, 557, 555,
+	25, 554, 553, 552, 551, 550, 549, 548, 547, 546,
+	545, 12, 544, 542, 540, 23, 534, 533, 24, 21,
+	113, 531, 530, 529, 526, 51, 525, 523, 42, 522,
+	520, 518, 517, 515, 9, 514, 513, 31, 512, 29,
+	38, 37, 511, 509, 508, 507, 505, 48, 504, 503,
+	500, 22, 495, 494, 493, 488, 486, 482, 480, 478,
+	8, 5, 477, 27, 476, 474, 473, 471, 11, 3,
+	471, 40, 17, 6, 470, 469, 7, 36, 33, 463,
+	462, 461, 460, 458, 457, 456, 455, 454, 19, 453,
+	452, 451, 441, 440, 88, 130, 73, 438, 437, 435,
+	434, 424, 2, 424, 1, 405, 400, 394, 420, 386,
+	386, 18, 380, 374,
 }
 
 var yyR1 = [...]uint8{
-	0, 115, 115, 114, 4, 3, 43, 37, 5, 8,
-	13, 13, 11, 11, 9, 9, 9, 10, 12, 7,
-	7, 7, 7, 6, 6, 42, 42, 116, 116, 116,
-	117, 117, 98, 98, 99, 99, 100, 100, 101, 106,
-	105, 105, 105, 102, 102, 103, 104, 104, 104, 41,
-	41, 38, 38, 73, 15, 40, 39, 20, 20, 20,
-	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
-	19, 19, 19, 19, 19, 19, 74, 74, 22, 29,
-	29, 29, 29, 28, 28, 28, 28, 18, 33, 33,
-	17, 17, 118, 118, 119, 119, 36, 36, 30, 30,
-	31, 32, 32, 34, 34, 35, 35, 1, 1, 1,
-	1, 2, 2, 95, 95, 96, 96, 97, 97, 94,
-	21, 78, 78, 79, 79, 80, 75, 75, 76, 76,
-	77, 82, 82, 82, 81, 81, 81, 120, 120, 121,
-	121, 88, 87, 123, 124, 124, 125, 125, 126, 126,
-	127, 128, 128, 86, 86, 85, 85, 85, 85, 107,
-	108, 108, 110, 112, 112, 113, 113, 111, 129, 109,
-	109, 89, 89, 89, 90, 91, 91, 92, 92, 92,
-	92, 83, 83, 84, 84, 16, 27, 27, 26, 26,
-	23, 23, 23, 23, 24, 24, 25, 14, 70, 70,
-	71, 71, 71, 71, 71, 71, 71, 71, 71, 71,
-	71, 71, 71, 72, 93, 44, 44, 45, 45, 45,
-	45, 46, 47, 48, 49, 49, 49, 50, 51, 52,
-	52, 53, 53, 54, 55, 55, 56, 57, 57, 60,
-	58, 130, 130, 131, 131, 59, 59, 63, 63, 63,
-	63, 61, 62, 66, 66, 67, 67, 68, 68, 69,
-	69, 65, 64, 122, 122, 132, 132, 132,
+	0, 139, 139, 139, 139, 138, 4, 3, 55, 49,
+	5, 8, 13, 13, 11, 11, 9, 9, 9, 10,
+	12, 7, 7, 7, 7, 6, 6, 54, 54, 119,
+	119, 119, 119, 120, 120, 121, 121, 121, 122, 122,
+	123, 123, 124, 129, 128, 128, 125, 125, 126, 127,
+	127, 127, 53, 53, 53, 53, 50, 50, 85, 85,
+	87, 15, 15, 16, 52, 51, 21, 21, 21, 20,
+	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
+	20, 20, 20, 20, 86, 86, 23, 30, 30, 42,
+	29, 29, 29, 29, 29, 29, 29, 29, 29, 29,
+	43, 43, 44, 44, 45, 45, 41, 41, 40, 19,
+	34, 34, 18, 18, 18, 92, 92, 93, 93, 48,
+	48, 31, 31, 32, 33, 33, 46, 46, 47, 47,
+	1, 1, 1, 2, 2, 116, 116, 35, 117, 117,
+	118, 118, 115, 36, 22, 39, 88, 88, 89, 89,
+	89, 90, 90, 91, 91, 91, 95, 95, 94, 94,
+	107, 140, 140, 101, 101, 101, 100, 141, 102, 102,
+	102, 102, 102, 102, 105, 105, 103, 103, 104, 106,
+	106, 99, 99, 98, 98, 98, 98, 130, 131, 131,
+	133, 136, 136, 136, 136, 137, 137, 134, 134, 135,
+	132, 132, 38, 110, 110, 110, 111, 112, 112, 113,
+	113, 113, 113, 96, 96, 97, 97, 17, 28, 27,
+	27, 24, 24, 24, 24, 25, 25, 26, 14, 82,
+	82, 83, 83, 83, 83, 83, 83, 83, 83, 83,
+	83, 83, 83, 83, 84, 37, 114, 56, 56, 57,
+	57, 57, 57, 58, 59, 60, 61, 61, 61, 62,
+	63, 64, 64, 65, 65, 66, 67, 67, 68, 69,
+	69, 72, 70, 142, 142, 143, 143, 71, 71, 75,
+	75, 75, 75, 73, 74, 78, 78, 79, 79, 80,
+	80, 81, 81, 77, 76, 108, 108, 109, 109, 109,
 }
 
 var yyR2 = [...]int8{
-	0, 1, 2, 8, 1, 1, 1, 1, 1, 2,
-	3, 0, 1, 2, 1, 1, 1, 1, 4, 2,
-	2, 2, 0, 2, 0, 3, 0, 3, 3, 0,
-	1, 0, 3, 0, 1, 0, 1, 2, 3, 2,
-	1, 1, 0, 1, 3, 1, 1, 1, 1, 1,
-	2, 1, 1, 1, 4, 3, 4, 1, 1, 1,
+	0, 1, 2, 2, 3, 8, 1, 1, 1, 1,
+	1, 2, 3, 0, 1, 2, 1, 1, 1, 1,
+	4, 2, 2, 2, 0, 2, 0, 3, 0, 3,
+	3, 3, 0, 1, 0, 3, 2, 0, 1, 0,
+	1, 2, 3, 2, 1, 0, 1, 3, 1, 1,
+	1, 1, 1, 2, 1, 2, 1, 1, 1, 1,
+	3, 1, 1, 3, 3, 4, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 2, 1,
-	3, 3, 3, 1, 1, 1, 1, 1, 1, 1,
-	1, 4, 1, 3, 4, 4, 1, 2, 1, 1,
-	1, 1, 1, 1, 2, 1, 1, 1, 3, 5,
-	3, 1, 2, 2, 5, 1, 3, 4, 4, 2,
-	1, 3, 4, 1, 3, 4, 3, 4, 1, 3,
-	4, 3, 5, 4, 3, 5, 4, 1, 2, 2,
-	0, 1, 1, 2, 2, 0, 1, 3, 1, 1,
-	4, 0, 2, 1, 3, 1, 2, 3, 3, 4,
-	5, 1, 1, 2, 0, 1, 3, 1, 4, 1,
-	3, 2, 3, 3, 4, 1, 1, 1, 1, 1,
-	0, 3, 3, 3, 3, 2, 3, 4, 1, 2,
-	1, 1, 1, 1, 1, 1, 4, 1, 1, 1,
+	2, 3, 1, 3, 1, 2, 1, 1, 2, 1,
+	1, 1, 1, 3, 4, 1, 3, 4, 4, 1,
+	2, 1, 1, 1, 1, 1, 1, 2, 1, 1,
+	3, 5, 3, 1, 2, 2, 5, 1, 1, 3,
+	4, 4, 2, 1, 1, 1, 3, 4, 1, 3,
+	5, 1, 3, 1, 4, 4, 3, 4, 3, 4,
+	2, 2, 0, 1, 4, 2, 1, 2, 0, 1,
+	3, 2, 3, 5, 1, 3, 1, 1, 4, 0,
+	2, 1, 3, 1, 2, 3, 3, 4, 1, 4,
+	1, 0, 2, 2, 4, 1, 3, 1, 1, 4,
+	1, 3, 3, 2, 3, 3, 4, 1, 1, 1,
+	1, 1, 0, 3, 3, 3, 3, 2, 3, 1,
+	2, 1, 1, 1, 1, 1, 1, 4, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 2, 1, 2, 1, 4, 4, 4,
-	4, 4, 1, 1, 1, 3, 5, 1, 1, 1,
-	2, 1, 3, 1, 1, 3, 1, 1, 2, 1,
-	2, 1, 1, 1, 1, 1, 3, 1, 1, 1,
-	1, 1, 3, 1, 2, 1, 2, 1, 1, 1,
-	1, 2, 1, 2, 0, 1, 1, 3,
+	1, 1, 1, 1, 2, 1, 1, 2, 1, 4,
+	4, 4, 4, 4, 1, 1, 1, 3, 5, 1,
+	1, 1, 2, 1, 3, 1, 1, 3, 1, 1,
+	2, 1, 2, 1, 1, 1, 1, 1, 3, 1,
+	1, 1, 1, 1, 3, 1, 2, 1, 2, 1,
+	1, 1, 1, 2, 1, 2, 0, 1, 1, 3,
 }
 
 var yyChk = [...]int16{
-	-1000, -115, -114, -8, -3, 6, -114, 119, -13, 26,
-	-7, 64, 108, 63, -11, -9, -14, -10, -12, -5,
-	8, 7, -6, 72, 78, 78, 78, 27, -11, 32,
-	15, 112, -10, 67, 33, -42, -116, 68, 52, -98,
-	116, -117, 55, -102, -103, -104, -4, -3, -43, 6,
-	7, -41, -38, -40, -39, -4, -43, 6, -99, -100,
-	-101, -102, 42, 42, 30, -38, 15, -20, -19, -74,
-	-44, -95, -18, -70, -107, -78, -75, -17, -21, -16,
-	-94, -31, -81, -83, -82, -84, -89, -73, -93, -45,
-	71, 79, -71, -72, 91, 49, 56, 73, 81, 89,
-	125, 54, 58, -90, -4, 88, 75, 92, 96, 100,
-	57, 77, 117, 82, 74, 106, 114, 118, 122, 87,
-	34, 42, -101, 84, -103, -20, 15, -46, 32, 66,
-	26, 26, 26, 104, 66, 26, 93, -46, -65, 62,
-	26, 93, -20, 108, 64, 66, -92, 102, 59, 121,
-	-106, -3, -29, -28, 39, -33, -30, -27, -32, 86,
-	80, -36, -5, 26, -34, -35, 8, 36, -1, 109,
-	69, -47, -48, -49, -50, -52, -53, 55, -55, -54,
-	-57, -56, -59, -60, -63, 32, -61, -62, -65, -64,
-	-29, -66, -20, -68, 65, 26, -108, -109, -22, -5,
-	-118, 27, -79, -119, -80, -5, 27, -76, -77, -5,
-	27, -120, -88, 17, -87, -86, -85, -22, 103, -20,
-	-22, 93, 93, -46, 27, -120, -88, -20, -22, -20,
-	-20, -91, -37, -15, 8, 127, -105, -27, -15, 6,
-	7, -32, 36, 8, -26, -15, -23, -14, -24, -25,
-	-5, 8, 31, 25, 8, -1, -122, 45, 30, -58,
-	60, -130, 44, 94, -131, 46, 53, -58, -52, 16,
-	28, -96, -97, -5, 27, 30, -20, 27, 30, 27,
-	30, 32, 27, 30, 32, -121, 30, 27, -122, 30,
-	97, 115, 93, -20, -22, -20, -22, -121, 27, 35,
-	128, 39, 39, 39, 27, -26, -15, -26, 32, 8,
-	-2, 8, 36, 33, -132, -36, -15, -20, 8, 36,
-	17, -59, -55, -57, 33, -67, -69, 28, -29, 61,
-	27, 130, 32, -120, -22, -119, -5, -80, -5, -36,
-	-15, -37, 8, -77, -37, 27, 17, -85, -29, -20,
-	27, 129, 27, -24, -15, 25, 8, 37, 8, 30,
-	-69, -97, -37, -15, -112, 30, 32, 32, 33, 33,
-	33, 33, 128, 33, -2, -29, -51, -52, 33, 33,
-	-121, -113, -111, -22, 30, -111,
+	-32768, -139, -138, 2, -8, -3, 6, -138, 2, 52,
+	119, -13, 26, 52, -7, 64, 108, 63, -11, -9,
+	-14, -10, -12, -5, 8, 7, -6, 72, 78, 78,
+	78, 27, -11, 32, 15, 112, -10, 67, 33, -54,
+	-119, 68, 52, -121, 116, -120, 55, 2, -125, -126,
+	-127, -4, -3, -55, 6, 7, -53, -50, 2, -52,
+	-51, -4, -55, 6, -122, 2, -123, -124, -125, 42,
+	42, 42, 30, -50, 2, 15, -21, -20, -86, -56,
+	-116, -19, -82, -130, -88, -18, -22, -17, -115, -32,
+	-94, -96, -95, -97, -110, -85, -114, -57, 71, 79,
+	-83, -84, 91, 56, 49, 73, 81, 89, 125, 54,
+	58, -111, -87, -4, 88, 75, 92, 96, 100, 57,
+	77, 117, 82, 74, 106, 114, 118, 122, 87, 34,
+	-3, 42, -124, 84, -126, -21, 15, -58, 32, 66,
+	26, 26, 26, 104, 66, 26, 93, -58, -77, 62,
+	26, 93, -21, 108, 64, 66, -113, 102, 59, 121,
+	31, -129, -3, -30, -29, -42, -35, -34, -37, -38,
+	-31, -39, -36, -33, -43, -40, -16, 9, 86, 80,
+	13, -5, -48, 73, 11, -46, -47, 26, 111, -3,
+	8, 36, -1, 109, 69, -59, -60, -61, -62, -64,
+	-65, 55, -67, -66, -69, -68, -71, -72, -75, 32,
+	-73, -74, -77, -76, -30, -78, -21, -80, 65, 73,
+	-3, 26, -131, -132, -23, -5, 27, -89, -90, -91,
+	-5, 27, -92, -93, -5, 27, -101, -99, -107, -98,
+	17, -23, 103, -21, -23, 93, 93, -58, 27, -101,
+	-21, -23, -21, -21, -112, -49, -15, 8, -16, -55,
+	-4, -128, -28, 26, 37, 27, -44, -45, -41, -30,
+	-26, -5, -30, 31, 31, 25, 8, -1, -108, 45,
+	30, -70, 60, -142, 44, 94, -143, 46, 53, -70,
+	-64, 16, 28, 31, -117, -118, -5, 27, 30, -21,
+	27, 30, 32, 27, 30, 32, 27, 30, -102, -141,
+	30, -108, 97, 115, 93, -21, -23, -21, -23, 27,
+	35, -27, -24, -14, 8, -26, -16, -5, -30, 27,
+	30, -41, 32, -55, 8, -2, 8, 36, 33, -109,
+	-48, -15, -21, 8, 36, 17, -71, -67, -69, 33,
+	-79, -81, 28, -30, 61, 27, 30, 32, -107, -23,
+	-107, -91, -48, -15, -93, -48, -15, -107, -98, 30,
+	17, -105, -103, -98, -104, 18, -30, -21, 27, -27,
+	-45, -25, 8, -15, 25, 8, 37, 8, 30, -81,
+	-118, -49, -15, -136, 30, 30, 33, 33, 33, 33,
+	-102, -99, -141, 30, -106, 8, 33, -2, -30, -63,
+	-64, 33, 33, 17, -137, -134, -135, -23, 18, -90,
+	30, 30, -103, -99, 37, 30, -106, 30, -99, 19,
+	17, -134, -132, 19, 30,
 }
 
 var yyDef = [...]int16{
-	0, -2, 1, 0, 11, 5, 2, 22, 9, 0,
-	24, 0, 0, 0, 0, 12, 14, 15, 16, 197,
-	17, 8, 0, 0, 19, 20, 21, 10, 13, 0,
-	0, 23, 0, -2, 18, 0, 33, 31, 3, 0,
-	35, 0, 0, 30, 43, 45, 46, 47, 48, 4,
-	6, 25, 49, 51, 52, 0, 0, 4, 0, 34,
-	36, 0, 27, 28, 0, 50, 0, 0, 57, 58,
-	59, 60, 61, 62, 63, 64, 65, 66, 67, 68,
-	69, 70, 71, 72, 73, 74, 75, 76, 77, 216,
-	0, 87, 198, 199, 0, 90, 0, 120, 0, 0,
-	100, 0, 0, 0, 53, 214, 200, 201, 202, 203,
-	204, 205, 206, 207, 208, 209, 210, 211, 212, 0,
-	180, 32, 37, 0, 44, 55, 0, 215, 0, 113,
-	0, 0, 0, 185, 119, 0, 0, 0, 0, 0,
-	0, 0, 171, 0, 0, 213, 0, 177, 178, 179,
-	38, 42, 56, 79, 0, 83, 84, 85, 86, 88,
-	89, 98, 99, 0, 101, 102, 96, 0, 103, 105,
-	106, 264, 222, 223, 224, 227, -2, 0, -2, 0,
-	234, 0, -2, 0, 245, 0, 247, 248, 249, 250,
-	-2, 0, 262, 253, 258, 0, 0, 161, 169, 0,
-	0, 121, 0, 92, 123, 0, 126, 0, 128, 0,
-	134, 140, 0, 137, 141, 142, 153, 155, 0, 181,
-	182, 0, 0, 261, 131, 140, 0, 183, 184, 172,
-	173, 0, 175, 176, 7, 0, 39, 40, 41, 0,
-	0, 0, 0, 107, 0, 193, 188, 190, 191, 192,
-	197, 194, 0, 0, 97, 104, 0, 0, 0, 230,
-	0, 0, 241, 242, 0, 243, 244, 238, 0, 0,
-	254, 0, 115, 0, 159, 0, 78, 91, 0, 122,
-	0, 0, 127, 0, 0, 0, 0, 136, 138, 0,
-	156, 0, 0, 217, 219, 218, 220, 0, 133, 174,
-	0, 80, 81, 82, 186, 0, 193, 189, 0, 108,
-	110, 111, 0, 221, 263, 265, 266, 0, 96, 0,
-	225, 240, -2, 235, 246, 252, 255, 0, 259, 260,
-	114, 0, 0, 164, 170, 93, 0, 124, 0, 0,
-	0, 0, 7, 129, 0, 135, 139, 154, 157, 158,
-	132, 0, 187, 0, 195, 0, 112, 0, 97, 0,
-	256, 116, 0, 0, 140, 0, 0, 0, 94, 95,
-	125, 130, 54, 196, 109, 267, 226, 228, 117, 118,
-	160, 163, 165, 167, 0, 166,
+	0, -2, 1, 0, 0, 13, 7, 2, 0, 3,
+	24, 11, 0, 4, 26, 0, 0, 0, 0, 14,
+	16, 17, 18, 228, 19, 10, 0, 0, 21, 22,
+	23, 12, 15, 0, 0, 25, 0, -2, 20, 0,
+	37, -2, 5, 0, -2, 0, 0, 0, 33, 46,
+	48, 49, 50, 51, -2, 8, -2, 52, 54, 56,
+	57, 0, 0, 6, 0, 36, 38, 40, 0, 29,
+	30, 31, 0, 53, 55, 0, 0, 66, 67, 68,
+	69, 70, 71, 72, 73, 74, 75, 76, 77, 78,
+	79, 80, 81, 82, 83, 84, 85, 248, 0, 109,
+	229, 230, 0, 0, 112, 144, 0, 0, 123, 0,
+	0, 0, 58, 59, 246, 231, 232, 233, 234, 235,
+	236, 237, 238, 239, 240, 241, 242, 243, 0, 212,
+	0, 35, 41, 0, 47, 64, 0, 247, 0, 135,
+	0, 0, 0, 217, 142, 0, 0, 0, 0, 0,
+	0, 0, 203, 0, 0, 244, 0, 209, 210, 211,
+	0, 42, 45, 65, 87, 88, 90, 91, 92, 93,
+	94, 95, 96, 97, 98, 99, 89, 137, 110, 111,
+	245, 122, 121, 145, 143, 124, 125, 0, 0, 0,
+	119, 0, 126, 128, 129, 296, 254, 255, 256, 259,
+	-2, 0, -2, 0, 266, 0, -2, 0, 277, 0,
+	279, 280, 281, 282, -2, 0, 294, 285, 290, -2,
+	0, 0, 0, 188, 200, 0, 146, 0, 148, 151,
+	153, 113, 0, 115, 0, 158, 0, 163, 168, 181,
+	296, 183, 0, 213, 214, 0, 0, 293, 156, 0,
+	215, 216, 204, 205, 0, 207, 208, 9, 61, 62,
+	60, 43, 44, 0, 0, 100, 0, 102, 104, 106,
+	107, 122, 108, 0, 0, 0, 120, 127, 0, 0,
+	0, 262, 0, 0, 273, 274, 0, 275, 276, 270,
+	0, 0, 286, 0, 0, 138, 0, 187, 0, 86,
+	147, 0, 0, 114, 0, 0, 159, 0, 165, 169,
+	0, 160, 184, 0, 0, 249, 251, 250, 252, 157,
+	206, 0, 219, 221, 222, 223, 224, 228, 202, 101,
+	0, 105, 0, 63, 130, 132, 133, 0, 253, 295,
+	297, 298, 0, 119, 0, 257, 272, -2, 267, 278,
+	284, 287, 0, 291, 292, 136, 0, 0, 191, 201,
+	149, 152, 0, 0, 116, 0, 0, 168, 182, 0,
+	167, 171, 174, 176, 177, 179, 185, 186, 218, 220,
+	103, 0, 225, 226, 0, 134, 0, 120, 0, 288,
+	139, 0, 0, 189, 0, 0, 154, 155, 117, 118,
+	164, 170, 172, 0, 0, 0, 227, 131, 299, 258,
+	260, 140, 141, 192, 193, 195, 197, 198, 179, 150,
+	0, 0, 175, 0, 180, 0, 0, 0, 173, 178,
+	194, 196, 0, 199, 0,
 }
 
-var yyTok1 = [...]uint8{
-	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 130, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	129, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 128, 3, 3, 3, 3, 127,
+var yyTok1 = [...]int8{
+	1,
 }
 
 var yyTok2 = [...]int8{
@@ -700,7 +765,7 @@ var yyErrorMessages = [...]struct {
 	msg   string
 }{}
 
-// yaccpar:1
+//line yaccpar:1
 
 /*	parser for yacc output	*/
 
@@ -733,7 +798,7 @@ func yyNewParser() yyParser {
 	return &yyParserImpl{}
 }
 
-const yyFlag = -1000
+const yyFlag = -32768
 
 func yyTokname(c int) string {
 	if c >= 1 && c-1 < len(yyToknames) {
@@ -1033,799 +1098,1084 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:329
+//line asn1.y:354
 		{
 			yylex.(*MyLexer).result = append(make([]ModuleDefinition, 0), yyDollar[1].ModuleDefinition)
 		}
 	case 2:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:330
+//line asn1.y:355
 		{
 			yylex.(*MyLexer).result = append(yylex.(*MyLexer).result, yyDollar[2].ModuleDefinition)
 		}
-	case 3:
+	case 5:
 		yyDollar = yyS[yypt-8 : yypt+1]
-// asn1.y:343
+//line asn1.y:371
 		{
-			yyVAL.ModuleDefinition = ModuleDefinition{ModuleIdentifier: yyDollar[1].ModuleIdentifier, TagDefault: yyDollar[3].TagDefault, ExtensibilityImplied: yyDollar[4].ExtensionDefault, ModuleBody: yyDollar[7].ModuleBody}
+			yyVAL.ModuleDefinition = ModuleDefinition{ModuleIdentifier: yyDollar[1].ModuleIdentifier, TagDefault: yyDollar[3].TagDefault, ExtensibilityImplied: yyDollar[4].ExtensionDefault, ModuleBody: yyDollar[7].ModuleBody, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 4:
+	case 6:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:348
+//line asn1.y:376
 		{
 			yyVAL.TypeReference = TypeReference(yyDollar[1].name)
 		}
-	case 6:
+	case 8:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:353
+//line asn1.y:383
 		{
 			yyVAL.ValueReference = ValueReference(yyDollar[1].name)
 		}
-	case 9:
+	case 11:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:364
+//line asn1.y:394
 		{
 			yyVAL.ModuleIdentifier = ModuleIdentifier{Reference: yyDollar[1].name, DefinitiveIdentifier: yyDollar[2].DefinitiveIdentifier}
 		}
-	case 10:
+	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:367
+//line asn1.y:397
 		{
 			yyVAL.DefinitiveIdentifier = DefinitiveIdentifier(yyDollar[2].DefinitiveObjIdComponentList)
 		}
-	case 11:
+	case 13:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:368
+//line asn1.y:398
 		{
 			yyVAL.DefinitiveIdentifier = DefinitiveIdentifier(make([]DefinitiveObjIdComponent, 0))
 		}
-	case 12:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:371
+//line asn1.y:401
 		{
 			yyVAL.DefinitiveObjIdComponentList = append(make([]DefinitiveObjIdComponent, 0), yyDollar[1].DefinitiveObjIdComponent)
 		}
-	case 13:
+	case 15:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:372
+//line asn1.y:402
 		{
 			yyVAL.DefinitiveObjIdComponentList = append(append(make([]DefinitiveObjIdComponent, 0), yyDollar[1].DefinitiveObjIdComponent), yyDollar[2].DefinitiveObjIdComponentList...)
 		}
-	case 14:
+	case 16:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:375
+//line asn1.y:405
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Name: yyDollar[1].name}
 		}
-	case 15:
+	case 17:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:376
+//line asn1.y:406
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Id: yyDollar[1].Number.IntValue()}
 		}
-	case 16:
+	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:377
+//line asn1.y:407
 		{
 			yyVAL.DefinitiveObjIdComponent = yyDollar[1].DefinitiveObjIdComponent
 		}
-	case 17:
+	case 19:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:380
+//line asn1.y:410
 		{
 			yyVAL.Number = yyDollar[1].Number
 		}
-	case 18:
+	case 20:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:384
+//line asn1.y:414
 		{
 			yyVAL.DefinitiveObjIdComponent = DefinitiveObjIdComponent{Name: yyDollar[1].name, Id: yyDollar[3].Number.IntValue()}
 		}
-	case 19:
+	case 21:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:387
+//line asn1.y:417
 		{
 			yyVAL.TagDefault = TAGS_EXPLICIT
 		}
-	case 20:
+	case 22:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:388
+//line asn1.y:418
 		{
 			yyVAL.TagDefault = TAGS_IMPLICIT
 		}
-	case 21:
+	case 23:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:389
+//line asn1.y:419
 		{
 			yyVAL.TagDefault = TAGS_AUTOMATIC
 		}
-	case 22:
+	case 24:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:390
+//line asn1.y:420
 		{
 			yyVAL.TagDefault = TAGS_EXPLICIT
 		}
-	case 23:
+	case 25:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:393
+//line asn1.y:423
 		{
 			yyVAL.ExtensionDefault = true
 		}
-	case 24:
+	case 26:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:394
+//line asn1.y:424
 		{
 			yyVAL.ExtensionDefault = false
 		}
-	case 25:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:397
+//line asn1.y:427
 		{
-			yyVAL.ModuleBody = ModuleBody{Imports: yyDollar[2].Imports, AssignmentList: yyDollar[3].AssignmentList}
+			yyVAL.ModuleBody = ModuleBody{Exports: yyDollar[1].Exports, Imports: yyDollar[2].Imports, AssignmentList: yyDollar[3].AssignmentList}
 		}
-	case 26:
+	case 28:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:398
+//line asn1.y:428
 		{
 			yyVAL.ModuleBody = ModuleBody{}
 		}
+	case 29:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:432
+		{
+			yyVAL.Exports = Exports{Mode: EXPORTS_SYMBOLS, SymbolList: yyDollar[2].SymbolList}
+		}
+	case 30:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:433
+		{
+			yyVAL.Exports = Exports{Mode: EXPORTS_ALL}
+		}
+	case 31:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:435
+		{
+			yyVAL.Exports = Exports{Mode: EXPORTS_ALL}
+		}
 	case 32:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:436
+		{
+			yyVAL.Exports = Exports{Mode: EXPORTS_ABSENT}
+		}
+	case 33:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:439
+		{
+			yyVAL.SymbolList = yyDollar[1].SymbolList
+		}
+	case 34:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:440
+		{
+			yyVAL.SymbolList = make([]Symbol, 0)
+		}
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:411
+//line asn1.y:443
 		{
 			yyVAL.Imports = yyDollar[2].Imports
 		}
-	case 33:
+	case 36:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:445
+		{
+			yyVAL.Imports = make([]SymbolsFromModule, 0)
+			if yylex.(*MyLexer).resync() {
+				yyrcvr.char = -1 // yyclearin
+				Errflag = 0      // yyerrok
+			}
+		}
+	case 37:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:412
+//line asn1.y:452
 		{
 			yyVAL.Imports = make([]SymbolsFromModule, 0)
 		}
-	case 34:
+	case 38:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:415
+//line asn1.y:455
 		{
 			yyVAL.Imports = yyDollar[1].Imports
 		}
-	case 35:
+	case 39:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:416
+//line asn1.y:456
 		{
 			yyVAL.Imports = make([]SymbolsFromModule, 0)
 		}
-	case 36:
+	case 40:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:419
+//line asn1.y:459
 		{
 			yyVAL.Imports = append(make([]SymbolsFromModule, 0), yyDollar[1].SymbolsFromModule)
 		}
-	case 37:
+	case 41:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:420
+//line asn1.y:460
 		{
 			yyVAL.Imports = append(yyDollar[1].Imports, yyDollar[2].SymbolsFromModule)
 		}
-	case 38:
+	case 42:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:423
+//line asn1.y:463
 		{
-			yyVAL.SymbolsFromModule = SymbolsFromModule{yyDollar[1].SymbolList, yyDollar[3].GlobalModuleReference}
+			yyVAL.SymbolsFromModule = SymbolsFromModule{SymbolList: yyDollar[1].SymbolList, Module: yyDollar[3].GlobalModuleReference, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 39:
+	case 43:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:426
+//line asn1.y:466
 		{
 			yyVAL.GlobalModuleReference = GlobalModuleReference{yyDollar[1].name, yyDollar[2].Value}
 		}
-	case 40:
+	case 44:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:429
+//line asn1.y:471
 		{
 			yyVAL.Value = yyDollar[1].ObjectIdentifierValue
 		}
-	case 41:
-		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:430
-		{
-			yyVAL.Value = yyDollar[1].DefinedValue
-		}
-	case 42:
+	case 45:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:431
+//line asn1.y:472
 		{
 			yyVAL.Value = nil
 		}
-	case 43:
+	case 46:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:434
+//line asn1.y:475
 		{
 			yyVAL.SymbolList = append(make([]Symbol, 0), yyDollar[1].Symbol)
 		}
-	case 44:
+	case 47:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:435
+//line asn1.y:476
 		{
 			yyVAL.SymbolList = append(yyDollar[1].SymbolList, yyDollar[3].Symbol)
 		}
-	case 46:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:442
+//line asn1.y:483
 		{
 			yyVAL.Symbol = TypeReference(yyDollar[1].TypeReference)
 		}
-	case 47:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:443
+//line asn1.y:484
 		{
 			yyVAL.Symbol = ModuleReference(yyDollar[1].name)
 		}
-	case 48:
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:444
+//line asn1.y:485
 		{
 			yyVAL.Symbol = ValueReference(yyDollar[1].ValueReference)
 		}
-	case 49:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:450
+//line asn1.y:491
 		{
 			yyVAL.AssignmentList = NewAssignmentList(yyDollar[1].Assignment)
 		}
-	case 50:
+	case 53:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:451
+//line asn1.y:492
 		{
 			yyVAL.AssignmentList = yyDollar[1].AssignmentList.Append(yyDollar[2].Assignment)
 		}
-	case 53:
+	case 54:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:467
+//line asn1.y:495
+		{
+			yyVAL.AssignmentList = NewAssignmentList()
+			if yylex.(*MyLexer).resync() {
+				yyrcvr.char = -1 // yyclearin
+				Errflag = 0      // yyerrok
+			}
+		}
+	case 55:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:503
+		{
+			yyVAL.AssignmentList = yyDollar[1].AssignmentList
+			if yylex.(*MyLexer).resync() {
+				yyrcvr.char = -1 // yyclearin
+				Errflag = 0      // yyerrok
+			}
+		}
+	case 59:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:525
 		{
 			yyVAL.Type = yyDollar[1].TypeReference
 		}
-	case 54:
-		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:474
+	case 60:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:532
 		{
-			yyVAL.DefinedValue = DefinedValue{}
+			yyVAL.Type = ExternalTypeReference{ModuleReference: ModuleReference(yyDollar[1].name), TypeReference: yyDollar[3].TypeReference}
 		}
-	case 55:
+	case 62:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:538
+		{
+			yyVAL.DefinedValue = DefinedValue{ValueReference: yyDollar[1].ValueReference}
+		}
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:482
+//line asn1.y:544
 		{
-			yyVAL.Assignment = TypeAssignment{yyDollar[1].TypeReference, yyDollar[3].Type, ""}
+			yyVAL.DefinedValue = DefinedValue{ModuleReference: ModuleReference(yyDollar[1].name), ValueReference: yyDollar[3].ValueReference}
 		}
-	case 56:
+	case 64:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:549
+		{
+			yyVAL.Assignment = TypeAssignment{TypeReference: yyDollar[1].TypeReference, Type: yyDollar[3].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
+		}
+	case 65:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:485
+//line asn1.y:552
 		{
-			yyVAL.Assignment = ValueAssignment{yyDollar[1].ValueReference, yyDollar[2].Type, yyDollar[4].Value}
+			yyVAL.Assignment = ValueAssignment{ValueReference: yyDollar[1].ValueReference, Type: yyDollar[2].Type, Value: valueNotation(yyDollar[2].Type, yyDollar[4].Value), Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 78:
+	case 86:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:531
+//line asn1.y:597
 		{
-			yyVAL.NamedType = NamedType{Identifier: Identifier(yyDollar[1].name), Type: yyDollar[2].Type}
+			yyVAL.NamedType = NamedType{Identifier: Identifier(yyDollar[1].name), Type: yyDollar[2].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 80:
-		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:539
+	case 89:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:611
 		{
-			yyVAL.Value = String(yyDollar[2].name)
+			yyVAL.Value = yyDollar[1].DefinedValue
 		}
-	case 81:
+	case 98:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:633
+		{
+			yyVAL.Value = yyDollar[1].BracedValue
+		}
+	case 100:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:638
+		{
+			yyVAL.BracedValue = BracedValue{}
+		}
+	case 101:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:540
+//line asn1.y:639
 		{
-			yyVAL.Value = String(yyDollar[2].name)
+			yyVAL.BracedValue = yyDollar[2].BracedValue
 		}
-	case 82:
+	case 102:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:642
+		{
+			yyVAL.BracedValue = BracedValue{yyDollar[1].ValueList}
+		}
+	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:541
+//line asn1.y:643
 		{
-			yyVAL.Value = yyDollar[2].Value
+			yyVAL.BracedValue = append(yyDollar[1].BracedValue, yyDollar[3].ValueList)
 		}
-	case 85:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:557
+//line asn1.y:646
 		{
-			yyVAL.Value = yyDollar[1].ObjectIdentifierValue
+			yyVAL.ValueList = []Value{yyDollar[1].Value}
 		}
-	case 87:
+	case 105:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:647
+		{
+			yyVAL.ValueList = append(yyDollar[1].ValueList, yyDollar[2].Value)
+		}
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:570
+//line asn1.y:653
+		{
+			yyVAL.Value = NewObjectIdentifierValue(yyDollar[1].ObjIdComponents)
+		}
+	case 108:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:658
+		{
+			yyVAL.Value = ContainingValue{Value: yyDollar[2].Value}
+		}
+	case 109:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:663
 		{
 			yyVAL.Type = BooleanType{}
 		}
-	case 88:
+	case 110:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:573
+//line asn1.y:666
 		{
 			yyVAL.Value = Boolean(true)
 		}
-	case 89:
+	case 111:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:574
+//line asn1.y:667
 		{
 			yyVAL.Value = Boolean(false)
 		}
-	case 90:
+	case 112:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:579
+//line asn1.y:673
 		{
 			yyVAL.Type = IntegerType{}
 		}
-	case 91:
-		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:580
+	case 113:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:674
 		{
 			yyVAL.Type = IntegerType{}
 		}
-	case 96:
+	case 114:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:675
+		{
+			yyVAL.Type = IntegerType{NamedNumberList: yyDollar[3].NamedNumberList}
+		}
+	case 115:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:591
+//line asn1.y:678
+		{
+			yyVAL.NamedNumberList = append(make(NamedNumberList, 0), yyDollar[1].NamedNumber)
+		}
+	case 116:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:679
+		{
+			yyVAL.NamedNumberList = append(yyDollar[1].NamedNumberList, yyDollar[3].NamedNumber)
+		}
+	case 117:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:682
+		{
+			yyVAL.NamedNumber = NamedNumber{Name: Identifier(yyDollar[1].name), Value: yyDollar[3].Number, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
+		}
+	case 118:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:683
+		{
+			yyVAL.NamedNumber = NamedNumber{Name: Identifier(yyDollar[1].name), Value: yyDollar[3].DefinedValue, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
+		}
+	case 119:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:686
 		{
 			yyVAL.Number = yyDollar[1].Number
 		}
-	case 97:
+	case 120:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:592
+//line asn1.y:687
 		{
 			yyVAL.Number = yyDollar[2].Number.UnaryMinus()
 		}
-	case 98:
+	case 121:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:597
+//line asn1.y:692
 		{
 			yyVAL.Value = yyDollar[1].Number
 		}
-	case 99:
+	case 122:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:598
+//line asn1.y:693
 		{
 			yyVAL.Value = IdentifiedIntegerValue{Name: yyDollar[1].name}
 		}
-	case 100:
+	case 123:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:603
+//line asn1.y:698
 		{
 			yyVAL.Type = RealType{}
 		}
-	case 103:
+	case 126:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:612
+//line asn1.y:707
 		{
 			yyVAL.Value = yyDollar[1].Real
 		}
-	case 104:
+	case 127:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:613
+//line asn1.y:708
 		{
 			yyVAL.Value = yyDollar[2].Real.UnaryMinus()
 		}
-	case 105:
+	case 128:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:617
+//line asn1.y:712
 		{
 			yyVAL.Value = Real(math.Inf(1))
 		}
-	case 106:
+	case 129:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:618
+//line asn1.y:713
 		{
 			yyVAL.Value = Real(math.Inf(-1))
 		}
-	case 107:
-		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:622
-		{
-			yyVAL.Real = parseRealNumber(yyDollar[1].Number, 0, 0)
-		}
-	case 108:
+	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:623
+//line asn1.y:718
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, yyDollar[3].Number, 0)
 		}
-	case 109:
+	case 131:
 		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:624
+//line asn1.y:719
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, yyDollar[3].Number, yyDollar[5].Number)
 		}
-	case 110:
+	case 132:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:625
+//line asn1.y:720
 		{
 			yyVAL.Real = parseRealNumber(yyDollar[1].Number, 0, yyDollar[3].Number)
 		}
-	case 112:
+	case 134:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:629
+//line asn1.y:724
 		{
 			yyVAL.Number = Number(-int(yyDollar[2].Number))
 		}
-	case 113:
+	case 135:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:634
+//line asn1.y:729
 		{
-			yyVAL.Type = BitStringType{}
+			yyVAL.Type = BitStringType{Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 114:
+	case 136:
 		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:635
+//line asn1.y:730
 		{
-			yyVAL.Type = BitStringType{NamedBits: yyDollar[4].NamedBitList}
+			yyVAL.Type = BitStringType{NamedBits: yyDollar[4].NamedBitList, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 115:
+	case 137:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:638
+//line asn1.y:733
+		{
+			yyVAL.Value = yyDollar[1].bstring
+		}
+	case 138:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:736
 		{
 			yyVAL.NamedBitList = append(make([]NamedBit, 0), yyDollar[1].NamedBit)
 		}
-	case 116:
+	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:639
+//line asn1.y:737
 		{
 			yyVAL.NamedBitList = append(yyDollar[1].NamedBitList, yyDollar[3].NamedBit)
 		}
-	case 117:
+	case 140:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:642
+//line asn1.y:740
 		{
-			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number}
+			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 118:
+	case 141:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:643
+//line asn1.y:741
 		{
-			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].DefinedValue}
+			yyVAL.NamedBit = NamedBit{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].DefinedValue, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 119:
+	case 142:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:648
+//line asn1.y:746
 		{
 			yyVAL.Type = OctetStringType{}
 		}
-	case 120:
+	case 143:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:749
+		{
+			yyVAL.Value = yyDollar[1].hstring
+		}
+	case 144:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:653
+//line asn1.y:754
 		{
 			yyVAL.Type = NullType{}
 		}
-	case 121:
+	case 145:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:761
+		{
+			yyVAL.Value = NullValue{}
+		}
+	case 146:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:656
+//line asn1.y:768
 		{
-			yyVAL.Type = IntegerEnumType{}
+			yyVAL.Type = EnumeratedType{Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 122:
+	case 147:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:657
+//line asn1.y:770
 		{
-			yyVAL.Type = IntegerEnumType{Enums: yyDollar[3].IntegerEnumItemList}
+			enumerated := yyDollar[3].EnumeratedType
+			enumerated.Span = nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)
+			yyVAL.Type = enumerated
 		}
-	case 123:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:659
+//line asn1.y:778
 		{
-			yyVAL.IntegerEnumItemList = append(make(IntegerEnumItemList, 0), yyDollar[1].IntegerEnumItem)
+			yyVAL.EnumeratedType = EnumeratedType{Enums: yyDollar[1].EnumeratedItemList}
 		}
-	case 124:
+	case 149:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:660
+//line asn1.y:779
 		{
-			yyVAL.IntegerEnumItemList = append(yyDollar[1].IntegerEnumItemList, yyDollar[3].IntegerEnumItem)
+			yyVAL.EnumeratedType = EnumeratedType{Enums: yyDollar[1].EnumeratedItemList, Extensible: true, ExceptionSpec: yyDollar[3].ExceptionSpec}
 		}
-	case 125:
-		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:663
+	case 150:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line asn1.y:781
 		{
-			yyVAL.IntegerEnumItem = IntegerEnumItem{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number}
+			yyVAL.EnumeratedType = EnumeratedType{Enums: yyDollar[1].EnumeratedItemList, Extensible: true, ExceptionSpec: yyDollar[3].ExceptionSpec, AdditionalEnums: yyDollar[5].EnumeratedItemList}
 		}
-	case 126:
+	case 151:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:786
+		{
+			yyVAL.EnumeratedItemList = append(make(EnumeratedItemList, 0), yyDollar[1].EnumeratedItem)
+		}
+	case 152:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:668
+//line asn1.y:787
 		{
-			yyVAL.Type = EnumeratedType{}
+			yyVAL.EnumeratedItemList = append(yyDollar[1].EnumeratedItemList, yyDollar[3].EnumeratedItem)
 		}
-	case 127:
+	case 153:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:790
+		{
+			yyVAL.EnumeratedItem = EnumeratedItem{Name: Identifier(yyDollar[1].name), Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
+		}
+	case 154:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:669
+//line asn1.y:791
 		{
-			yyVAL.Type = EnumeratedType{Enums: yyDollar[3].EnumeratedItemList}
+			yyVAL.EnumeratedItem = EnumeratedItem{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 128:
-		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:671
+	case 155:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:792
 		{
-			yyVAL.EnumeratedItemList = append(make(EnumeratedItemList, 0), yyDollar[1].EnumeratedItem)
+			yyVAL.EnumeratedItem = EnumeratedItem{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].DefinedValue, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 129:
+	case 156:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:672
+//line asn1.y:798
 		{
-			yyVAL.EnumeratedItemList = append(yyDollar[1].EnumeratedItemList, yyDollar[3].EnumeratedItem)
+			yyVAL.Type = SetType{Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 130:
+	case 157:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:675
+//line asn1.y:800
 		{
-			yyVAL.EnumeratedItem = EnumeratedItem{Name: Identifier(yyDollar[1].name), Index: yyDollar[3].Number}
+			set := SetType(yyDollar[3].SequenceType)
+			set.Span = nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)
+			yyVAL.Type = set
 		}
-	case 131:
+	case 158:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:679
+//line asn1.y:811
 		{
-			yyVAL.Type = SetType{}
+			yyVAL.Type = SequenceType{Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 132:
-		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:680
+	case 159:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:813
+		{
+			sequence := yyDollar[3].SequenceType
+			sequence.Span = nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)
+			yyVAL.Type = sequence
+		}
+	case 160:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:821
+		{
+			yyVAL.ExceptionSpec = yyDollar[2].ExceptionSpec
+		}
+	case 163:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:838
 		{
-			yyVAL.Type = SetType{}
+			yyVAL.SequenceType = SequenceType{Components: yyDollar[1].ComponentTypeList}
 		}
-	case 133:
+	case 164:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:681
+//line asn1.y:840
 		{
-			yyVAL.Type = SetType{Components: yyDollar[3].ComponentTypeList}
+			lists := yyDollar[4].SequenceType
+			lists.Components = yyDollar[1].ComponentTypeList
+			lists.Extensible = true
+			lists.ExceptionSpec = yyDollar[3].ExceptionSpec
+			yyVAL.SequenceType = lists
 		}
-	case 134:
+	case 165:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:848
+		{
+			lists := yyDollar[2].SequenceType
+			lists.Extensible = true
+			lists.ExceptionSpec = yyDollar[1].ExceptionSpec
+			yyVAL.SequenceType = lists
+		}
+	case 168:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:863
+		{
+			yyVAL.SequenceType = SequenceType{}
+		}
+	case 169:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:864
+		{
+			yyVAL.SequenceType = SequenceType{}
+		}
+	case 170:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:686
+//line asn1.y:865
 		{
-			yyVAL.Type = SequenceType{}
+			yyVAL.SequenceType = SequenceType{TrailingRootComponents: yyDollar[3].ComponentTypeList}
 		}
-	case 135:
+	case 171:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:866
+		{
+			yyVAL.SequenceType = SequenceType{ExtensionAdditions: yyDollar[2].ExtensionAdditionList}
+		}
+	case 172:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:867
+		{
+			yyVAL.SequenceType = SequenceType{ExtensionAdditions: yyDollar[2].ExtensionAdditionList}
+		}
+	case 173:
 		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:687
+//line asn1.y:868
 		{
-			yyVAL.Type = SequenceType{}
+			yyVAL.SequenceType = SequenceType{ExtensionAdditions: yyDollar[2].ExtensionAdditionList, TrailingRootComponents: yyDollar[5].ComponentTypeList}
 		}
-	case 136:
+	case 174:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:871
+		{
+			yyVAL.ExtensionAdditionList = append(make([]ExtensionAddition, 0), yyDollar[1].ExtensionAddition)
+		}
+	case 175:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:872
+		{
+			yyVAL.ExtensionAdditionList = append(yyDollar[1].ExtensionAdditionList, yyDollar[3].ExtensionAddition)
+		}
+	case 176:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:875
+		{
+			yyVAL.ExtensionAddition = yyDollar[1].ComponentType.(ExtensionAddition)
+		}
+	case 177:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:876
+		{
+			yyVAL.ExtensionAddition = yyDollar[1].ExtensionAddition
+		}
+	case 178:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:688
+//line asn1.y:880
 		{
-			yyVAL.Type = SequenceType{Components: yyDollar[3].ComponentTypeList}
+			yyVAL.ExtensionAddition = ExtensionAdditionGroup{Version: yyDollar[2].Number, Components: yyDollar[3].ComponentTypeList, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 153:
+	case 179:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:885
+		{
+			yyVAL.Number = 0
+		}
+	case 180:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:886
+		{
+			yyVAL.Number = yyDollar[1].Number
+		}
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:731
+//line asn1.y:889
 		{
 			yyVAL.ComponentTypeList = append(make(ComponentTypeList, 0), yyDollar[1].ComponentType)
 		}
-	case 154:
+	case 182:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:732
+//line asn1.y:890
 		{
 			yyVAL.ComponentTypeList = append(yyDollar[1].ComponentTypeList, yyDollar[3].ComponentType)
 		}
-	case 155:
+	case 183:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:735
+//line asn1.y:893
 		{
-			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType}
+			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 156:
+	case 184:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:736
+//line asn1.y:894
 		{
-			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, IsOptional: true}
+			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, IsOptional: true, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 157:
+	case 185:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:737
+//line asn1.y:895
 		{
-			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, Default: yyDollar[3].Value}
+			yyVAL.ComponentType = NamedComponentType{NamedType: yyDollar[1].NamedType, Default: valueNotation(yyDollar[1].NamedType.Type, yyDollar[3].Value), Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 158:
+	case 186:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:738
+//line asn1.y:896
 		{
-			yyVAL.ComponentType = ComponentsOfComponentType{Type: yyDollar[3].Type}
+			yyVAL.ComponentType = ComponentsOfComponentType{Type: yyDollar[3].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 159:
+	case 187:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:744
-		{
-			yyVAL.Type = yyDollar[3].ChoiceType
-		}
-	case 160:
-		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:747
+//line asn1.y:903
 		{
-			yyVAL.ChoiceType = ChoiceType{yyDollar[1].AlternativeTypeList, yyDollar[4].ExtensionAdditionAlternativesList}
+			choice := yyDollar[3].ChoiceType
+			choice.Span = nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)
+			yyVAL.Type = choice
 		}
-	case 161:
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:748
+//line asn1.y:918
 		{
 			yyVAL.ChoiceType = ChoiceType{AlternativeTypeList: yyDollar[1].AlternativeTypeList}
 		}
-	case 163:
-		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:755
+	case 189:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:920
 		{
-			yyVAL.ExtensionAdditionAlternativesList = yyDollar[2].ExtensionAdditionAlternativesList
+			yyVAL.ChoiceType = ChoiceType{AlternativeTypeList: yyDollar[1].AlternativeTypeList, Extensible: true, ExceptionSpec: yyDollar[3].ExceptionSpec, ExtensionTypes: yyDollar[4].ExtensionAdditionAlternativesList}
 		}
-	case 164:
+	case 191:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:756
+//line asn1.y:929
 		{
 			yyVAL.ExtensionAdditionAlternativesList = make([]ChoiceExtension, 0)
 		}
-	case 165:
+	case 192:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:930
+		{
+			yyVAL.ExtensionAdditionAlternativesList = make([]ChoiceExtension, 0)
+		}
+	case 193:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:931
+		{
+			yyVAL.ExtensionAdditionAlternativesList = yyDollar[2].ExtensionAdditionAlternativesList
+		}
+	case 194:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:932
+		{
+			yyVAL.ExtensionAdditionAlternativesList = yyDollar[2].ExtensionAdditionAlternativesList
+		}
+	case 195:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:759
+//line asn1.y:935
 		{
 			yyVAL.ExtensionAdditionAlternativesList = append(make([]ChoiceExtension, 0), yyDollar[1].ExtensionAdditionAlternative)
 		}
-	case 166:
+	case 196:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:760
+//line asn1.y:936
 		{
 			yyVAL.ExtensionAdditionAlternativesList = append(yyDollar[1].ExtensionAdditionAlternativesList, yyDollar[3].ExtensionAdditionAlternative)
 		}
-	case 167:
+	case 197:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:764
+//line asn1.y:939
+		{
+			yyVAL.ExtensionAdditionAlternative = yyDollar[1].ExtensionAdditionAlternative
+		}
+	case 198:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:940
 		{
 			yyVAL.ExtensionAdditionAlternative = yyDollar[1].NamedType
 		}
-	case 169:
+	case 199:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line asn1.y:944
+		{
+			yyVAL.ExtensionAdditionAlternative = ExtensionAdditionAlternativesGroup{Version: yyDollar[2].Number, AlternativeTypeList: yyDollar[3].AlternativeTypeList, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
+		}
+	case 200:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:771
+//line asn1.y:949
 		{
 			yyVAL.AlternativeTypeList = append(make([]NamedType, 0), yyDollar[1].NamedType)
 		}
-	case 170:
+	case 201:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:772
+//line asn1.y:950
 		{
 			yyVAL.AlternativeTypeList = append(yyDollar[1].AlternativeTypeList, yyDollar[3].NamedType)
 		}
-	case 171:
+	case 202:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:955
+		{
+			yyVAL.Value = ChoiceValue{Identifier: Identifier(yyDollar[1].name), Value: yyDollar[3].Value}
+		}
+	case 203:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:777
+//line asn1.y:960
 		{
-			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[2].Type}
+			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[2].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 172:
+	case 204:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:778
+//line asn1.y:961
 		{
-			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_IMPLICIT, HasTagType: true}
+			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_IMPLICIT, HasTagType: true, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 173:
+	case 205:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:779
+//line asn1.y:962
 		{
-			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_EXPLICIT, HasTagType: true}
+			yyVAL.Type = TaggedType{Tag: yyDollar[1].Tag, Type: yyDollar[3].Type, TagType: TAGS_EXPLICIT, HasTagType: true, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 174:
+	case 206:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:782
+//line asn1.y:965
 		{
 			yyVAL.Tag = Tag{Class: yyDollar[2].Class, ClassNumber: yyDollar[3].Value}
 		}
-	case 175:
+	case 207:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:785
+//line asn1.y:968
 		{
 			yyVAL.Value = yyDollar[1].Number
 		}
-	case 176:
+	case 208:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:786
+//line asn1.y:969
 		{
 			yyVAL.Value = yyDollar[1].DefinedValue
 		}
-	case 177:
+	case 209:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:789
+//line asn1.y:972
 		{
 			yyVAL.Class = CLASS_UNIVERSAL
 		}
-	case 178:
+	case 210:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:790
+//line asn1.y:973
 		{
 			yyVAL.Class = CLASS_APPLICATION
 		}
-	case 179:
+	case 211:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:791
+//line asn1.y:974
 		{
 			yyVAL.Class = CLASS_PRIVATE
 		}
-	case 180:
+	case 212:
 		yyDollar = yyS[yypt-0 : yypt+1]
-// asn1.y:792
+//line asn1.y:975
 		{
 			yyVAL.Class = CLASS_CONTEXT_SPECIFIC
 		}
-	case 181:
+	case 213:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:797
+//line asn1.y:980
 		{
-			yyVAL.Type = SequenceOfType{yyDollar[3].Type}
+			yyVAL.Type = SequenceOfType{Type: yyDollar[3].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 182:
+	case 214:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:798
+//line asn1.y:981
 		{
-			yyVAL.Type = SequenceOfType{yyDollar[3].NamedType}
+			yyVAL.Type = SequenceOfType{Type: yyDollar[3].NamedType, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 183:
+	case 215:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:801
+//line asn1.y:984
 		{
-			yyVAL.Type = SetOfType{yyDollar[3].Type}
+			yyVAL.Type = SetOfType{Type: yyDollar[3].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 184:
+	case 216:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:802
+//line asn1.y:985
 		{
-			yyVAL.Type = SetOfType{yyDollar[3].NamedType}
+			yyVAL.Type = SetOfType{Type: yyDollar[3].NamedType, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 185:
+	case 217:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:807
+//line asn1.y:990
 		{
 			yyVAL.Type = ObjectIdentifierType{}
 		}
-	case 186:
+	case 218:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:812
+//line asn1.y:997
 		{
 			yyVAL.ObjectIdentifierValue = yyDollar[2].ObjectIdentifierValue
 		}
-	case 187:
-		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:813
-		{
-			yyVAL.ObjectIdentifierValue = NewObjectIdentifierValue(yyDollar[2].DefinedValue).Append(yyDollar[3].ObjectIdentifierValue...)
-		}
-	case 188:
+	case 219:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:816
+//line asn1.y:1000
 		{
 			yyVAL.ObjectIdentifierValue = NewObjectIdentifierValue(yyDollar[1].ObjIdComponents)
 		}
-	case 189:
+	case 220:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:817
+//line asn1.y:1001
 		{
 			yyVAL.ObjectIdentifierValue = NewObjectIdentifierValue(yyDollar[1].ObjIdComponents).Append(yyDollar[2].ObjectIdentifierValue...)
 		}
-	case 190:
+	case 221:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:820
+//line asn1.y:1004
 		{
-			yyVAL.ObjIdComponents = ObjectIdElement{Name: yyDollar[1].name}
+			yyVAL.ObjIdComponents = ObjectIdElement{Name: yyDollar[1].name, NameForm: true}
 		}
-	case 193:
+	case 222:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:823
+//line asn1.y:1005
+		{
+			yyVAL.ObjIdComponents = ObjectIdElement{Id: yyDollar[1].Number.IntValue()}
+		}
+	case 224:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1007
 		{
 			yyVAL.ObjIdComponents = yyDollar[1].DefinedValue
 		}
-	case 194:
+	case 225:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:826
+//line asn1.y:1010
 		{
 			yyVAL.ObjIdComponents = ObjectIdElement{Id: yyDollar[1].Number.IntValue()}
 		}
-	case 195:
+	case 226:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:827
+//line asn1.y:1011
 		{
 			yyVAL.ObjIdComponents = yyDollar[1].DefinedValue
 		}
-	case 196:
+	case 227:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:831
+//line asn1.y:1015
 		{
 			switch v := yyDollar[3].ObjIdComponents.(type) {
 			case DefinedValue:
@@ -1836,282 +2186,318 @@ yydefault:
 				panic(fmt.Sprintf("Expected DefinedValue or ObjectIdElement from NumberForm, got %v", yyDollar[3].ObjIdComponents))
 			}
 		}
-	case 200:
+	case 231:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:852
+//line asn1.y:1036
 		{
 			yyVAL.Type = RestrictedStringType{LexType: BMPString}
 		}
-	case 201:
+	case 232:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:853
+//line asn1.y:1037
 		{
 			yyVAL.Type = RestrictedStringType{LexType: GeneralString}
 		}
-	case 202:
+	case 233:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:854
+//line asn1.y:1038
 		{
 			yyVAL.Type = RestrictedStringType{LexType: GraphicString}
 		}
-	case 203:
+	case 234:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:855
+//line asn1.y:1039
 		{
 			yyVAL.Type = RestrictedStringType{LexType: IA5String}
 		}
-	case 204:
+	case 235:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:856
+//line asn1.y:1040
 		{
 			yyVAL.Type = RestrictedStringType{LexType: ISO646String}
 		}
-	case 205:
+	case 236:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:857
+//line asn1.y:1041
 		{
 			yyVAL.Type = RestrictedStringType{LexType: NumericString}
 		}
-	case 206:
+	case 237:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:858
+//line asn1.y:1042
 		{
 			yyVAL.Type = RestrictedStringType{LexType: PrintableString}
 		}
-	case 207:
+	case 238:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:859
+//line asn1.y:1043
 		{
 			yyVAL.Type = RestrictedStringType{LexType: TeletexString}
 		}
-	case 208:
+	case 239:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:860
+//line asn1.y:1044
 		{
 			yyVAL.Type = RestrictedStringType{LexType: T61String}
 		}
-	case 209:
+	case 240:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:861
+//line asn1.y:1045
 		{
 			yyVAL.Type = RestrictedStringType{LexType: UniversalString}
 		}
-	case 210:
+	case 241:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:862
+//line asn1.y:1046
 		{
 			yyVAL.Type = RestrictedStringType{LexType: UTF8String}
 		}
-	case 211:
+	case 242:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:863
+//line asn1.y:1047
 		{
 			yyVAL.Type = RestrictedStringType{LexType: VideotexString}
 		}
-	case 212:
+	case 243:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:864
+//line asn1.y:1048
 		{
 			yyVAL.Type = RestrictedStringType{LexType: VisibleString}
 		}
-	case 213:
+	case 244:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:869
+//line asn1.y:1053
 		{
 			yyVAL.Type = CharacterStringType{}
 		}
-	case 214:
+	case 245:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1058
+		{
+			yyVAL.Value = String(yyDollar[1].cstring)
+		}
+	case 246:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:874
+//line asn1.y:1063
 		{
 			yyVAL.Type = TypeReference("GeneralizedTime")
 		}
-	case 215:
+	case 247:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:879
+//line asn1.y:1068
 		{
-			yyVAL.Type = ConstraintedType{yyDollar[1].Type, yyDollar[2].Constraint}
+			yyVAL.Type = ConstraintedType{Type: yyDollar[1].Type, Constraint: yyDollar[2].Constraint, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 217:
+	case 249:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:887
+//line asn1.y:1076
 		{
-			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].Type}, yyDollar[2].Constraint}
+			yyVAL.Type = ConstraintedType{Type: SequenceOfType{Type: yyDollar[4].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}, Constraint: yyDollar[2].Constraint, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 218:
+	case 250:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:888
+//line asn1.y:1077
 		{
-			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].Type}, SingleElementConstraint(yyDollar[2].Elements)}
+			yyVAL.Type = ConstraintedType{Type: SequenceOfType{Type: yyDollar[4].Type, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}, Constraint: SingleElementConstraint(yyDollar[2].Elements), Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 219:
+	case 251:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:891
+//line asn1.y:1080
 		{
-			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].NamedType}, yyDollar[2].Constraint}
+			yyVAL.Type = ConstraintedType{Type: SequenceOfType{Type: yyDollar[4].NamedType, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}, Constraint: yyDollar[2].Constraint, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 220:
+	case 252:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:892
+//line asn1.y:1081
 		{
-			yyVAL.Type = ConstraintedType{SequenceOfType{yyDollar[4].NamedType}, SingleElementConstraint(yyDollar[2].Elements)}
+			yyVAL.Type = ConstraintedType{Type: SequenceOfType{Type: yyDollar[4].NamedType, Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}, Constraint: SingleElementConstraint(yyDollar[2].Elements), Span: nodeSpan(yylex, yyDollar[1].span, yyrcvr.char)}
 		}
-	case 221:
+	case 253:
 		yyDollar = yyS[yypt-4 : yypt+1]
-// asn1.y:897
+//line asn1.y:1086
 		{
 			yyVAL.Constraint = Constraint{ConstraintSpec: yyDollar[2].ConstraintSpec}
 		}
-	case 222:
+	case 254:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:900
+//line asn1.y:1089
 		{
 			yyVAL.ConstraintSpec = yyDollar[1].SubtypeConstraint
 		}
-	case 225:
+	case 257:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:910
+//line asn1.y:1099
 		{
 			yyVAL.SubtypeConstraint = yyDollar[1].SubtypeConstraint
 		}
-	case 226:
+	case 258:
 		yyDollar = yyS[yypt-5 : yypt+1]
-// asn1.y:911
+//line asn1.y:1100
 		{
 			yyVAL.SubtypeConstraint = append(yyDollar[1].SubtypeConstraint, yyDollar[5].ElementSetSpec)
 		}
-	case 227:
+	case 259:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:914
+//line asn1.y:1103
 		{
 			yyVAL.SubtypeConstraint = SubtypeConstraint{yyDollar[1].ElementSetSpec}
 		}
-	case 229:
+	case 261:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:920
+//line asn1.y:1109
 		{
 			yyVAL.ElementSetSpec = yyDollar[1].Unions
 		}
-	case 230:
+	case 262:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:921
+//line asn1.y:1110
 		{
 			yyVAL.ElementSetSpec = yyDollar[2].Exclusions
 		}
-	case 231:
+	case 263:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:924
+//line asn1.y:1113
 		{
 			yyVAL.Unions = Unions{yyDollar[1].Intersections}
 		}
-	case 232:
+	case 264:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:925
+//line asn1.y:1114
 		{
 			yyVAL.Unions = append(yyDollar[1].Unions, yyDollar[3].Intersections)
 		}
-	case 234:
+	case 266:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:931
+//line asn1.y:1120
 		{
 			yyVAL.Intersections = Intersections{yyDollar[1].IntersectionElements}
 		}
-	case 235:
+	case 267:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:932
+//line asn1.y:1121
 		{
 			yyVAL.Intersections = append(yyDollar[1].Intersections, yyDollar[3].IntersectionElements)
 		}
-	case 237:
+	case 269:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:938
+//line asn1.y:1127
 		{
 			yyVAL.IntersectionElements = IntersectionElements{Elements: yyDollar[1].Elements}
 		}
-	case 238:
+	case 270:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:939
+//line asn1.y:1128
 		{
 			yyVAL.IntersectionElements = IntersectionElements{Elements: yyDollar[1].Elements, Exclusions: yyDollar[2].Exclusions}
 		}
-	case 240:
+	case 272:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:945
+//line asn1.y:1134
 		{
 			yyVAL.Exclusions = Exclusions{yyDollar[2].Elements}
 		}
-	case 245:
+	case 277:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:954
+//line asn1.y:1143
 		{
 			yyVAL.Elements = yyDollar[1].Elements
 		}
-	case 246:
+	case 278:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:956
+//line asn1.y:1145
 		{
 			yyVAL.Elements = yyDollar[2].ElementSetSpec
 		}
-	case 251:
+	case 283:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:971
+//line asn1.y:1160
 		{
 			yyVAL.Elements = SingleValue{yyDollar[1].Value}
 		}
-	case 252:
+	case 284:
 		yyDollar = yyS[yypt-3 : yypt+1]
-// asn1.y:976
+//line asn1.y:1165
 		{
 			yyVAL.Elements = ValueRange{yyDollar[1].RangeEndpoint, yyDollar[3].RangeEndpoint}
 		}
-	case 253:
+	case 285:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:979
+//line asn1.y:1168
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value}
 		}
-	case 254:
+	case 286:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:980
+//line asn1.y:1169
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value, IsOpen: true}
 		}
-	case 255:
+	case 287:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:983
+//line asn1.y:1172
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[1].Value}
 		}
-	case 256:
+	case 288:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:984
+//line asn1.y:1173
 		{
 			yyVAL.RangeEndpoint = RangeEndpoint{Value: yyDollar[2].Value, IsOpen: true}
 		}
-	case 258:
+	case 290:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:988
+//line asn1.y:1177
 		{
 			yyVAL.Value = nil
 		}
-	case 260:
+	case 292:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:992
+//line asn1.y:1181
 		{
 			yyVAL.Value = nil
 		}
-	case 261:
+	case 293:
 		yyDollar = yyS[yypt-2 : yypt+1]
-// asn1.y:997
+//line asn1.y:1186
 		{
 			yyVAL.Elements = SizeConstraint{yyDollar[2].Constraint}
 		}
-	case 262:
+	case 294:
 		yyDollar = yyS[yypt-1 : yypt+1]
-// asn1.y:1002
+//line asn1.y:1191
 		{
 			yyVAL.Elements = TypeConstraint{yyDollar[1].Type}
 		}
+	case 295:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line asn1.y:1196
+		{
+			yyVAL.ExceptionSpec = yyDollar[2].ExceptionSpec
+		}
+	case 296:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line asn1.y:1197
+		{
+			yyVAL.ExceptionSpec = nil
+		}
+	case 297:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1200
+		{
+			yyVAL.ExceptionSpec = &ExceptionSpec{Value: yyDollar[1].Number}
+		}
+	case 298:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line asn1.y:1201
+		{
+			yyVAL.ExceptionSpec = &ExceptionSpec{Value: yyDollar[1].DefinedValue}
+		}
+	case 299:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line asn1.y:1202
+		{
+			yyVAL.ExceptionSpec = &ExceptionSpec{Type: yyDollar[1].Type, Value: yyDollar[3].Value}
+		}
 	}
 	goto yystack /* stack new state and value */
 }